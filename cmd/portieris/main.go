@@ -1,4 +1,4 @@
-// Copyright 2018,2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -21,24 +21,62 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	kube "github.com/IBM/portieris/helpers/kube"
 	"github.com/IBM/portieris/internal/info"
+	informers "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/informers/externalversions"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	policyv2 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v2"
+	"github.com/IBM/portieris/pkg/breakglass"
 	"github.com/IBM/portieris/pkg/controller/multi"
+	"github.com/IBM/portieris/pkg/controller/status"
+	"github.com/IBM/portieris/pkg/controller/validate"
+	"github.com/IBM/portieris/pkg/conversion"
 	"github.com/IBM/portieris/pkg/kubernetes"
 	"github.com/IBM/portieris/pkg/metrics"
 	notaryclient "github.com/IBM/portieris/pkg/notary"
+	"github.com/IBM/portieris/pkg/policy"
+	"github.com/IBM/portieris/pkg/policy/validation"
 	registryclient "github.com/IBM/portieris/pkg/registry"
+	"github.com/IBM/portieris/pkg/transport"
 	notaryverifier "github.com/IBM/portieris/pkg/verifier/trust"
 	"github.com/IBM/portieris/pkg/webhook"
 	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
 )
 
+// statusResync is how often the status of each policy is checked again, to find changes to the secrets it references
+const statusResync = 5 * time.Minute
+
 func main() {
 	mkdir := flag.String("mkdir", "", "create directories needed for Portieris to run")
 	kubeconfig := flag.String("kubeconfig", "", "location of kubeconfig file to use for an out-of-cluster kube client configuration")
+	exceptionMaxLifetime := flag.Duration("exception-max-lifetime", policyv1.DefaultMaxExceptionLifetime, "how long after it is created an ImagePolicyException can apply, exceptions that expire later are denied and ignored, 0 is no limit")
+	breakGlassNamespaces := flag.String("break-glass-namespaces", "", "comma separated namespaces, which may contain a * wildcard, where the break-glass annotation can be used")
+	breakGlassUsers := flag.String("break-glass-users", "", "comma separated users, which may contain a * wildcard, that can use the break-glass annotation")
+	breakGlassGroups := flag.String("break-glass-groups", "", "comma separated groups, which may contain a * wildcard, whose members can use the break-glass annotation")
+	conversionNamespace := flag.String("conversion-namespace", "", "namespace of the service that serves the policy conversion webhook, the webhook is not configured when it is empty")
+	conversionService := flag.String("conversion-service", "portieris", "name of the service that serves the policy conversion webhook")
+	conversionPort := flag.Int("conversion-port", 443, "port of the service that serves the policy conversion webhook")
+	cacheSecrets := flag.Bool("cache-secrets", false, "read secrets and service accounts from informer caches, which needs list and watch permission")
+	cacheNamespace := flag.String("cache-namespace", "", "namespace to restrict the cached secrets and service accounts to, others are read from the API server")
+	cacheSecretSelector := flag.String("cache-secret-selector", "", "label selector to restrict the cached secrets to, others are read from the API server")
+	verificationCacheTTL := flag.Duration("verification-cache-ttl", 5*time.Minute, "how long the result of verifying an image that is allowed is reused, 0 disables caching it")
+	verificationCacheNegativeTTL := flag.Duration("verification-cache-negative-ttl", 30*time.Second, "how long the result of verifying an image that is denied is reused, 0 disables caching it")
+	verificationPrewarmInterval := flag.Duration("verification-prewarm-interval", 0, "how often the images of Deployments, StatefulSets and DaemonSets are verified in the background to warm the verification cache, 0 disables it")
+	verificationRecords := flag.Bool("verification-records", false, "share the verifications that allow images with other replicas as ImageVerification resources, which needs permission to manage them")
+	verificationRecordsKeyFile := flag.String("verification-records-key-file", "/etc/portieris/records/key", "file holding the key that shared verifications are signed with, only Portieris should be able to read it")
+	verificationWorkers := flag.Int("verification-workers", 4, "number of distinct images of a pod that are verified concurrently")
+	admissionTimeout := flag.Duration("admission-timeout", 10*time.Second, "timeout of the admission webhook, images that are not verified within 90% of it are denied")
+	transportConfig := transport.DefaultConfig()
+	flag.IntVar(&transportConfig.MaxIdleConnsPerHost, "http-max-idle-conns-per-host", transportConfig.MaxIdleConnsPerHost, "idle connections to each registry, notary server and vulnerability scanner that are kept for reuse")
+	flag.IntVar(&transportConfig.MaxConnsPerHost, "http-max-conns-per-host", transportConfig.MaxConnsPerHost, "limit of connections to each registry, notary server and vulnerability scanner, 0 is no limit")
+	flag.DurationVar(&transportConfig.IdleConnTimeout, "http-idle-conn-timeout", transportConfig.IdleConnTimeout, "how long an idle connection is kept for reuse")
 
 	flag.Parse() // glog flags
 
@@ -68,17 +106,30 @@ func main() {
 	kubeClientConfig := kube.GetKubeClientConfig(kubeconfig)
 	kubeClientset := kube.GetKubeClient(kubeClientConfig)
 	kubeWrapper := kubernetes.NewKubeClientsetWrapper(kubeClientset)
-	policyClient := kube.GetPolicyClient(kubeClientConfig)
+	// Secrets and service accounts are read from informers, which may be restricted so that less RBAC is needed
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClientset, 0, kubeinformers.WithNamespace(*cacheNamespace))
+	secretInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClientset, 0, kubeinformers.WithNamespace(*cacheNamespace),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) { options.LabelSelector = *cacheSecretSelector }))
+	// ConfigMaps that hold Rego modules, and workloads whose images are verified in the background, are read from
+	// informers in all namespaces
+	clusterInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClientset, 0)
+	if *cacheSecrets {
+		kubeWrapper = kubernetes.NewInformerKubeClientsetWrapper(kubeClientset, secretInformerFactory.Core().V1().Secrets(), kubeInformerFactory.Core().V1().ServiceAccounts())
+	}
+	kubeWrapper.WithConfigMaps(clusterInformerFactory.Core().V1().ConfigMaps())
+	policyClientset := kube.GetPolicyClientset(kubeClientConfig)
+	// Policies are read from informers, which also maintain the status of policies
+	informerFactory := informers.NewSharedInformerFactory(policyClientset, statusResync)
+	policyClient := policy.NewInformerClient(policyClientset, informerFactory).WithMaxExceptionLifetime(*exceptionMaxLifetime)
 
-	ca, err := ioutil.ReadFile("/etc/certs/ca.pem")
+	pmetrics := metrics.NewMetrics()
+	// Requests to notary servers, registry token services and vulnerability scanners share a pool of connections
+	transportConfig.CAFile = "/etc/certs/ca.pem"
+	httpTransport, err := transport.New(transportConfig, pmetrics)
 	if err != nil {
-		if os.IsNotExist(err) {
-			glog.Info("CA not provided at /etc/certs/ca.pem, will use default system pool")
-		} else {
-			glog.Fatal("Could not read /etc/certs/ca.pem", err)
-		}
+		glog.Fatal("Could not create HTTP transport", err)
 	}
-	trust, err := notaryclient.NewClient(".trust", ca)
+	trust, err := notaryclient.NewClient(".trust", httpTransport)
 	if err != nil {
 		glog.Fatal("Could not get trust client", err)
 	}
@@ -92,10 +143,64 @@ func main() {
 		glog.Fatal("Could not read /etc/certs/tls.key", err)
 	}
 
-	cr := registryclient.NewClient()
+	cr := registryclient.NewClient(httpTransport.Client(10 * time.Minute))
 	nv := notaryverifier.NewVerifier(kubeWrapper, trust, cr)
-	pmetrics := metrics.NewMetrics()
-	controller := multi.NewController(kubeWrapper, policyClient, nv, pmetrics)
+	var verificationCache *multi.VerificationCache
+	if *verificationCacheTTL > 0 || *verificationCacheNegativeTTL > 0 {
+		verificationCache = multi.NewVerificationCache(*verificationCacheTTL, *verificationCacheNegativeTTL, pmetrics).WithTransport(httpTransport)
+	}
+	var records *multi.VerificationRecords
+	if *verificationRecords {
+		if verificationCache == nil {
+			glog.Warning("Verification cache disabled, verifications are not shared with other replicas")
+		} else {
+			key, err := ioutil.ReadFile(*verificationRecordsKeyFile)
+			if err != nil || len(strings.TrimSpace(string(key))) == 0 {
+				glog.Fatalf("Could not read the key that shared verifications are signed with from %s: %v", *verificationRecordsKeyFile, err)
+			}
+			records = multi.NewVerificationRecords(policyClientset, informerFactory.Portieris().V1().ImageVerifications(), key)
+			verificationCache.ShareWith(records)
+		}
+	}
+	verificationConfig := multi.VerificationConfig{
+		Cache:   verificationCache,
+		Workers: *verificationWorkers,
+		// leave time to respond before the API server gives up on the webhook
+		Timeout:           *admissionTimeout - *admissionTimeout/10,
+		ScannerClient:     httpTransport.Client(10 * time.Second),
+		RegistryTransport: httpTransport,
+	}
+	controller := multi.NewController(kubeWrapper, policyClient, nv, pmetrics, breakglass.NewConfig(*breakGlassNamespaces, *breakGlassUsers, *breakGlassGroups), verificationConfig)
+
+	// Maintain the status of policies
+	statusController, err := status.NewController(kubeWrapper, policyClientset, informerFactory)
+	if err != nil {
+		glog.Fatal("Could not create policy status controller", err)
+	}
+	stopCh := make(chan struct{})
+	// Verify the images of workloads before their pods are next admitted, which only helps when results are cached
+	if *verificationPrewarmInterval > 0 {
+		if verificationCache == nil {
+			glog.Warning("Verification cache disabled, images are not verified in the background")
+		} else {
+			prewarmer := multi.NewPrewarmer(controller, *verificationPrewarmInterval, clusterInformerFactory)
+			if _, err := informerFactory.Portieris().V1().ImagePolicies().Informer().AddEventHandler(prewarmer.PolicyEventHandler()); err != nil {
+				glog.Fatal("Could not watch ImagePolicies for the prewarmer", err)
+			}
+			if _, err := informerFactory.Portieris().V1().ClusterImagePolicies().Informer().AddEventHandler(prewarmer.PolicyEventHandler()); err != nil {
+				glog.Fatal("Could not watch ClusterImagePolicies for the prewarmer", err)
+			}
+			go prewarmer.Run(stopCh, policyClient.HasSynced, kubeWrapper.HasSynced)
+		}
+	}
+	informerFactory.Start(stopCh)
+	kubeInformerFactory.Start(stopCh)
+	secretInformerFactory.Start(stopCh)
+	clusterInformerFactory.Start(stopCh)
+	go statusController.Run(stopCh)
+	if records != nil {
+		go records.Run(stopCh, time.Minute)
+	}
 
 	// Setup http handler for metrics
 	go func() {
@@ -105,5 +210,31 @@ func main() {
 	}()
 
 	webhook := webhook.NewServer("policy", controller, serverCert, serverKey)
+	webhook.AddReadinessCheck(policyClient.HasSynced)
+	webhook.AddReadinessCheck(kubeWrapper.HasSynced)
+	if records != nil {
+		// a replica that starts reuses the verifications of the others
+		webhook.AddReadinessCheck(records.HasSynced)
+	}
+	checker := validation.NewChecker(kubeWrapper, informerFactory.Portieris().V1().ImagePolicyProfiles().Lister())
+	webhook.HandleController("/validate", validate.NewController(checker, *exceptionMaxLifetime))
+	webhook.HandleConversion(conversion.Path, policyv2.Convert)
+	if verificationCache != nil {
+		// callers who can delete the records can flush the cache
+		webhook.HandleAuthorized("/verification-cache/flush", verificationCache, kubeClientset, authorizationv1.ResourceAttributes{
+			Group: "portieris.cloud.ibm.com", Resource: "imageverifications", Verb: "deletecollection",
+		})
+	}
+	if *conversionNamespace != "" {
+		// The CA that signed the webhook certificate, when it is not found the CA injected into the CRDs is kept
+		caBundle, err := ioutil.ReadFile("/etc/certs/ca.crt")
+		if err != nil && !os.IsNotExist(err) {
+			glog.Fatal("Could not read /etc/certs/ca.crt", err)
+		}
+		apiExtensionsClient := kube.GetAPIExtensionsClient(kubeClientConfig)
+		if err := conversion.ConfigureWebhook(apiExtensionsClient.ApiextensionsV1(), *conversionNamespace, *conversionService, int32(*conversionPort), caBundle); err != nil {
+			glog.Error("Could not configure the policy conversion webhook, v2 policies can not be used: ", err)
+		}
+	}
 	webhook.Run()
 }
@@ -9,7 +9,7 @@ kubectl delete MutatingWebhookConfiguration image-admission-config --ignore-not-
 kubectl delete ValidatingWebhookConfiguration image-admission-config --ignore-not-found=true
 
 kubectl delete crd clusterimagepolicies.securityenforcement.admission.cloud.ibm.com imagepolicies.securityenforcement.admission.cloud.ibm.com --ignore-not-found=true
-kubectl delete crd clusterimagepolicies.portieris.cloud.ibm.com imagepolicies.portieris.cloud.ibm.com --ignore-not-found=true
+kubectl delete crd clusterimagepolicies.portieris.cloud.ibm.com imagepolicies.portieris.cloud.ibm.com imagepolicyprofiles.portieris.cloud.ibm.com imagepolicyexceptions.portieris.cloud.ibm.com imageverifications.portieris.cloud.ibm.com --ignore-not-found=true
 kubectl delete secret all-icr-io
 
 helm delete "${RELEASE_NAME}" --no-hooks --namespace "${NAMESPACE}"
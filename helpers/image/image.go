@@ -1,4 +1,4 @@
-// Copyright 2018, 2025 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -28,6 +28,7 @@ type Reference struct {
 	original string
 	name     string
 	tag      string
+	tagged   bool
 	digest   string
 	hostname string
 	port     string
@@ -68,6 +69,7 @@ func NewReference(name string) (*Reference, error) {
 		result.tag = "latest"
 	} else {
 		result.tag = ref.(reference.Tagged).Tag()
+		result.tagged = true
 	}
 
 	return result, nil
@@ -123,6 +125,12 @@ func (r Reference) GetTag() string {
 	return r.tag
 }
 
+// HasTag returns true if the image name includes a tag, otherwise false
+// and GetTag returns the implied `latest`.
+func (r Reference) HasTag() bool {
+	return r.tagged
+}
+
 // GetDigest returns the digest.
 func (r Reference) GetDigest() string {
 	return r.digest
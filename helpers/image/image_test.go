@@ -1,4 +1,4 @@
-// Copyright 2018, 2022 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -256,3 +256,24 @@ func TestReference(t *testing.T) {
 		})
 	}
 }
+
+func TestReference_HasTag(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{in: "test.com/namespace/name", want: false},
+		{in: "test.com:8080/namespace/name", want: false},
+		{in: "test.com/namespace/name@sha256:1234567890", want: false},
+		{in: "test.com/namespace/name:latest", want: true},
+		{in: "test.com:8080/namespace/name:v1@sha256:1234567890", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			image, err := NewReference(tt.in)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, image.HasTag())
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -22,6 +22,7 @@ import (
 	portierisclientset "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned"
 	"github.com/IBM/portieris/pkg/policy"
 	"github.com/golang/glog"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -75,12 +76,26 @@ func GetKubeClient(config *rest.Config) *kubernetes.Clientset {
 	return clientset
 }
 
-// GetPolicyClient creates a policy clientset
+// GetPolicyClient creates a policy client
 func GetPolicyClient(config *rest.Config) *policy.Client {
+	policyClient := policy.NewClient(GetPolicyClientset(config))
+	return policyClient
+}
+
+// GetPolicyClientset creates a policy clientset
+func GetPolicyClientset(config *rest.Config) *portierisclientset.Clientset {
 	clientset, err := portierisclientset.NewForConfig(config)
 	if err != nil {
 		glog.Fatal(err)
 	}
-	policyClient := policy.NewClient(clientset)
-	return policyClient
+	return clientset
+}
+
+// GetAPIExtensionsClient creates an apiextensions clientset
+func GetAPIExtensionsClient(config *rest.Config) *apiextensionsclientset.Clientset {
+	clientset, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	return clientset
 }
@@ -0,0 +1,152 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wildcard
+
+import (
+	"container/list"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// anyTag matches an optional tag and/or digest suffix, it never crosses a '/'
+// so that a registry port can not be mistaken for a tag.
+const anyTag = `(?:[:@][^/]*)?`
+
+// maxCompiled bounds the number of compiled patterns that are kept
+const maxCompiled = 1024
+
+// compiledPattern is the result of compiling a pattern, including an error for an invalid one
+type compiledPattern struct {
+	expr string
+	re   *regexp.Regexp
+	err  error
+}
+
+// patternCache keeps the most recently used compiled patterns, policies are evaluated for every
+// container so each pattern is compiled once while it is in use, and invalid patterns are not compiled again.
+type patternCache struct {
+	mutex   sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+var compiled = newPatternCache(maxCompiled)
+
+func newPatternCache(size int) *patternCache {
+	return &patternCache{size: size, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (c *patternCache) compile(expr string) (*regexp.Regexp, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if e, ok := c.entries[expr]; ok {
+		c.order.MoveToFront(e)
+		p := e.Value.(*compiledPattern)
+		return p.re, p.err
+	}
+	re, err := regexp.Compile(expr)
+	c.entries[expr] = c.order.PushFront(&compiledPattern{expr: expr, re: re, err: err})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*compiledPattern).expr)
+	}
+	return re, err
+}
+
+func compile(expr string) (*regexp.Regexp, error) {
+	return compiled.compile(expr)
+}
+
+// Compile compiles a regular expression through the bounded cache of compiled patterns, for expressions that are
+// read from policies and matched on every admission
+func Compile(expr string) (*regexp.Regexp, error) {
+	return compile(expr)
+}
+
+// PathToRegexp converts a path pattern into an anchored regular expression.
+// In a path pattern '*' matches one or more characters within a single '/' separated
+// segment and '**' matches any number of segments, so that "icr.io/team-a/**"
+// matches every repository under team-a but "icr.io/team-a/*" only those one
+// level down. A "/**/" may also match a single '/'. Patterns without a tag also
+// match any tag or digest.
+func PathToRegexp(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); {
+		switch {
+		case strings.HasPrefix(pattern[i:], "/**/"):
+			b.WriteString("/(?:.*/)?")
+			i += 4
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i += 2
+		case pattern[i] == '*':
+			b.WriteString("[^/]+")
+			i++
+		default:
+			j := i
+			for j < len(pattern) && pattern[j] != '*' && !strings.HasPrefix(pattern[j:], "/**/") {
+				j++
+			}
+			b.WriteString(regexp.QuoteMeta(pattern[i:j]))
+			i = j
+		}
+	}
+	b.WriteString(anyTag)
+	b.WriteString("$")
+	return b.String()
+}
+
+// ComparePath matches a path pattern, see PathToRegexp, against an image reference.
+func ComparePath(pattern, imageRef string) bool {
+	re, err := compile(PathToRegexp(pattern))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(imageRef)
+}
+
+// CompareRegexp matches a regular expression against the whole of an image
+// reference, a reference with a tag or digest also matches when the expression
+// matches the name alone. An invalid expression matches nothing.
+func CompareRegexp(expr, imageRef string) bool {
+	re, err := compileRegexp(expr)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(imageRef)
+}
+
+// ValidateRegexp returns the error that makes a regular expression pattern invalid, nil when it is valid
+func ValidateRegexp(expr string) error {
+	_, err := compileRegexp(expr)
+	return err
+}
+
+// compileRegexp compiles an expression anchored to the whole of an image reference. The expression must be valid
+// on its own, so that an unbalanced group such as "x)|(.*" can not close the group that anchors it.
+func compileRegexp(expr string) (*regexp.Regexp, error) {
+	if _, err := compile(expr); err != nil {
+		return nil, err
+	}
+	return compile(anchorRegexp(expr))
+}
+
+func anchorRegexp(expr string) string {
+	return "^(?:" + expr + ")" + anyTag + "$"
+}
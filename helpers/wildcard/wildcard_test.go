@@ -162,4 +162,74 @@ var _ = Describe("Main", func() {
 			})
 		})
 	})
+
+	Describe("ComparePath - path patterns with * and **", func() {
+		It("should match a single segment with *", func() {
+			Expect(ComparePath("icr.io/team-a/*", "icr.io/team-a/app:v1")).To(BeTrue())
+		})
+		It("should not match more than one segment with *", func() {
+			Expect(ComparePath("icr.io/team-a/*", "icr.io/team-a/sandbox/app:v1")).To(BeFalse())
+		})
+		It("should not match an empty segment with *", func() {
+			Expect(ComparePath("icr.io/team-a/*", "icr.io/team-a/")).To(BeFalse())
+			Expect(ComparePath("icr.io/*/app", "icr.io//app")).To(BeFalse())
+		})
+		It("should match any number of segments with **", func() {
+			Expect(ComparePath("icr.io/team-a/**", "icr.io/team-a/sandbox/app:v1")).To(BeTrue())
+		})
+		It("should match zero or more segments with /**/", func() {
+			Expect(ComparePath("icr.io/**/app", "icr.io/app:v1")).To(BeTrue())
+			Expect(ComparePath("icr.io/**/app", "icr.io/team-a/sandbox/app@sha256:abc")).To(BeTrue())
+		})
+		It("should not let a wildcard host match across the path", func() {
+			Expect(ComparePath("*.icr.io/**", "evil.io/x.icr.io/app")).To(BeFalse())
+		})
+		It("should not treat a registry port as a tag", func() {
+			Expect(ComparePath("localhost", "localhost:5000/app")).To(BeFalse())
+		})
+		It("should treat other characters literally", func() {
+			Expect(ComparePath("icr.io/team.a/*", "icr.io/teamXa/app")).To(BeFalse())
+		})
+	})
+
+	Describe("CompareRegexp - anchored regular expressions", func() {
+		It("should match the whole reference", func() {
+			Expect(CompareRegexp(`icr\.io/team-(a|b)/.+`, "icr.io/team-b/app:v1")).To(BeTrue())
+		})
+		It("should not match part of the reference", func() {
+			Expect(CompareRegexp(`icr\.io/team-a`, "evil.io/icr.io/team-a")).To(BeFalse())
+		})
+		It("should match a name without a tag against a tagged reference", func() {
+			Expect(CompareRegexp(`icr\.io/team-a/app`, "icr.io/team-a/app:v1")).To(BeTrue())
+		})
+		It("should not match with an invalid expression", func() {
+			Expect(CompareRegexp(`icr.io/(`, "icr.io/(")).To(BeFalse())
+		})
+		It("should not let an unbalanced group escape the anchors", func() {
+			Expect(CompareRegexp(`x)|(.*`, "evil.io/app:v1")).To(BeFalse())
+			Expect(ValidateRegexp(`x)|(.*`)).To(HaveOccurred())
+		})
+		It("should report an invalid expression", func() {
+			Expect(ValidateRegexp(`icr.io/(`)).To(HaveOccurred())
+			Expect(ValidateRegexp(`icr\.io/.+`)).To(Succeed())
+		})
+	})
+
+	Describe("compiled patterns", func() {
+		It("should keep only the most recently used patterns, including invalid ones", func() {
+			c := newPatternCache(2)
+			_, err := c.compile("a")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = c.compile("(")
+			Expect(err).To(HaveOccurred())
+			_, err = c.compile("(")
+			Expect(err).To(HaveOccurred())
+			c.compile("a")
+			c.compile("b")
+			Expect(c.entries).To(HaveLen(2))
+			Expect(c.entries).To(HaveKey("a"))
+			Expect(c.entries).To(HaveKey("b"))
+			Expect(c.entries).NotTo(HaveKey("("))
+		})
+	})
 })
@@ -40,6 +40,7 @@ type ClusterImagePoliciesGetter interface {
 type ClusterImagePolicyInterface interface {
 	Create(ctx context.Context, clusterImagePolicy *v1.ClusterImagePolicy, opts metav1.CreateOptions) (*v1.ClusterImagePolicy, error)
 	Update(ctx context.Context, clusterImagePolicy *v1.ClusterImagePolicy, opts metav1.UpdateOptions) (*v1.ClusterImagePolicy, error)
+	UpdateStatus(ctx context.Context, clusterImagePolicy *v1.ClusterImagePolicy, opts metav1.UpdateOptions) (*v1.ClusterImagePolicy, error)
 	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ClusterImagePolicy, error)
@@ -128,6 +129,21 @@ func (c *clusterImagePolicies) Update(ctx context.Context, clusterImagePolicy *v
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *clusterImagePolicies) UpdateStatus(ctx context.Context, clusterImagePolicy *v1.ClusterImagePolicy, opts metav1.UpdateOptions) (result *v1.ClusterImagePolicy, err error) {
+	result = &v1.ClusterImagePolicy{}
+	err = c.client.Put().
+		Resource("clusterimagepolicies").
+		Name(clusterImagePolicy.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(clusterImagePolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
 // Delete takes name of the clusterImagePolicy and deletes it. Returns an error if one occurs.
 func (c *clusterImagePolicies) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
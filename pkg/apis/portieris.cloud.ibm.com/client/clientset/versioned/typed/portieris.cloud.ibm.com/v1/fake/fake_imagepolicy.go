@@ -102,6 +102,18 @@ func (c *FakeImagePolicies) Update(ctx context.Context, imagePolicy *portieriscl
 	return obj.(*portieriscloudibmcomv1.ImagePolicy), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeImagePolicies) UpdateStatus(ctx context.Context, imagePolicy *portieriscloudibmcomv1.ImagePolicy, opts v1.UpdateOptions) (*portieriscloudibmcomv1.ImagePolicy, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(imagepoliciesResource, "status", c.ns, imagePolicy), &portieriscloudibmcomv1.ImagePolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*portieriscloudibmcomv1.ImagePolicy), err
+}
+
 // Delete takes name of the imagePolicy and deletes it. Returns an error if one occurs.
 func (c *FakeImagePolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
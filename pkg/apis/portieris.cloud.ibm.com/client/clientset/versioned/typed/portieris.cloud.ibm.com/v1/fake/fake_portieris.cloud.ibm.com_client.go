@@ -36,6 +36,18 @@ func (c *FakePortierisV1) ImagePolicies(namespace string) v1.ImagePolicyInterfac
 	return &FakeImagePolicies{c, namespace}
 }
 
+func (c *FakePortierisV1) ImagePolicyExceptions(namespace string) v1.ImagePolicyExceptionInterface {
+	return &FakeImagePolicyExceptions{c, namespace}
+}
+
+func (c *FakePortierisV1) ImagePolicyProfiles() v1.ImagePolicyProfileInterface {
+	return &FakeImagePolicyProfiles{c}
+}
+
+func (c *FakePortierisV1) ImageVerifications() v1.ImageVerificationInterface {
+	return &FakeImageVerifications{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakePortierisV1) RESTClient() rest.Interface {
@@ -21,3 +21,9 @@ package v1
 type ClusterImagePolicyExpansion interface{}
 
 type ImagePolicyExpansion interface{}
+
+type ImagePolicyExceptionExpansion interface{}
+
+type ImagePolicyProfileExpansion interface{}
+
+type ImageVerificationExpansion interface{}
@@ -40,6 +40,7 @@ type ImagePoliciesGetter interface {
 type ImagePolicyInterface interface {
 	Create(ctx context.Context, imagePolicy *v1.ImagePolicy, opts metav1.CreateOptions) (*v1.ImagePolicy, error)
 	Update(ctx context.Context, imagePolicy *v1.ImagePolicy, opts metav1.UpdateOptions) (*v1.ImagePolicy, error)
+	UpdateStatus(ctx context.Context, imagePolicy *v1.ImagePolicy, opts metav1.UpdateOptions) (*v1.ImagePolicy, error)
 	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ImagePolicy, error)
@@ -135,6 +136,22 @@ func (c *imagePolicies) Update(ctx context.Context, imagePolicy *v1.ImagePolicy,
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *imagePolicies) UpdateStatus(ctx context.Context, imagePolicy *v1.ImagePolicy, opts metav1.UpdateOptions) (result *v1.ImagePolicy, err error) {
+	result = &v1.ImagePolicy{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("imagepolicies").
+		Name(imagePolicy.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(imagePolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
 // Delete takes name of the imagePolicy and deletes it. Returns an error if one occurs.
 func (c *imagePolicies) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
@@ -30,6 +30,9 @@ type PortierisV1Interface interface {
 	RESTClient() rest.Interface
 	ClusterImagePoliciesGetter
 	ImagePoliciesGetter
+	ImagePolicyExceptionsGetter
+	ImagePolicyProfilesGetter
+	ImageVerificationsGetter
 }
 
 // PortierisV1Client is used to interact with features provided by the portieris.cloud.ibm.com group.
@@ -45,6 +48,18 @@ func (c *PortierisV1Client) ImagePolicies(namespace string) ImagePolicyInterface
 	return newImagePolicies(c, namespace)
 }
 
+func (c *PortierisV1Client) ImagePolicyExceptions(namespace string) ImagePolicyExceptionInterface {
+	return newImagePolicyExceptions(c, namespace)
+}
+
+func (c *PortierisV1Client) ImagePolicyProfiles() ImagePolicyProfileInterface {
+	return newImagePolicyProfiles(c)
+}
+
+func (c *PortierisV1Client) ImageVerifications() ImageVerificationInterface {
+	return newImageVerifications(c)
+}
+
 // NewForConfig creates a new PortierisV1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
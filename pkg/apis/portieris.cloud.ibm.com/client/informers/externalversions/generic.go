@@ -57,6 +57,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Portieris().V1().ClusterImagePolicies().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("imagepolicies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Portieris().V1().ImagePolicies().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("imagepolicyexceptions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Portieris().V1().ImagePolicyExceptions().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("imagepolicyprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Portieris().V1().ImagePolicyProfiles().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("imageverifications"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Portieris().V1().ImageVerifications().Informer()}, nil
 
 	}
 
@@ -28,6 +28,12 @@ type Interface interface {
 	ClusterImagePolicies() ClusterImagePolicyInformer
 	// ImagePolicies returns a ImagePolicyInformer.
 	ImagePolicies() ImagePolicyInformer
+	// ImagePolicyExceptions returns a ImagePolicyExceptionInformer.
+	ImagePolicyExceptions() ImagePolicyExceptionInformer
+	// ImagePolicyProfiles returns a ImagePolicyProfileInformer.
+	ImagePolicyProfiles() ImagePolicyProfileInformer
+	// ImageVerifications returns a ImageVerificationInformer.
+	ImageVerifications() ImageVerificationInformer
 }
 
 type version struct {
@@ -50,3 +56,18 @@ func (v *version) ClusterImagePolicies() ClusterImagePolicyInformer {
 func (v *version) ImagePolicies() ImagePolicyInformer {
 	return &imagePolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// ImagePolicyExceptions returns a ImagePolicyExceptionInformer.
+func (v *version) ImagePolicyExceptions() ImagePolicyExceptionInformer {
+	return &imagePolicyExceptionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ImagePolicyProfiles returns a ImagePolicyProfileInformer.
+func (v *version) ImagePolicyProfiles() ImagePolicyProfileInformer {
+	return &imagePolicyProfileInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ImageVerifications returns a ImageVerificationInformer.
+func (v *version) ImageVerifications() ImageVerificationInformer {
+	return &imageVerificationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -29,3 +29,19 @@ type ImagePolicyListerExpansion interface{}
 // ImagePolicyNamespaceListerExpansion allows custom methods to be added to
 // ImagePolicyNamespaceLister.
 type ImagePolicyNamespaceListerExpansion interface{}
+
+// ImagePolicyExceptionListerExpansion allows custom methods to be added to
+// ImagePolicyExceptionLister.
+type ImagePolicyExceptionListerExpansion interface{}
+
+// ImagePolicyExceptionNamespaceListerExpansion allows custom methods to be added to
+// ImagePolicyExceptionNamespaceLister.
+type ImagePolicyExceptionNamespaceListerExpansion interface{}
+
+// ImagePolicyProfileListerExpansion allows custom methods to be added to
+// ImagePolicyProfileLister.
+type ImagePolicyProfileListerExpansion interface{}
+
+// ImageVerificationListerExpansion allows custom methods to be added to
+// ImageVerificationLister.
+type ImageVerificationListerExpansion interface{}
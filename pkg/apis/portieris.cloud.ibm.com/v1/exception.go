@@ -0,0 +1,69 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"sort"
+	"time"
+
+	"github.com/IBM/portieris/helpers/wildcard"
+)
+
+// ExceptionAnnotation is added to the pod template of an admitted workload, it names the
+// ImagePolicyExceptions that allowed its images
+const ExceptionAnnotation = "portieris.cloud.ibm.com/image-policy-exceptions"
+
+// DefaultMaxExceptionLifetime is how long after it is created an exception can apply, unless it is configured
+const DefaultMaxExceptionLifetime = 72 * time.Hour
+
+// Applies reports whether the exception allows the image at the given time, an image name without
+// a tag allows any tag. An exception without an expiry, reason or approver never applies, nor does one
+// that expires more than maxLifetime after it was created, 0 is no limit.
+func (e ImagePolicyException) Applies(image string, now time.Time, maxLifetime time.Duration) bool {
+	if e.Spec.ExpiresAt.IsZero() || e.Spec.Reason == "" || e.Spec.Approver == "" {
+		return false
+	}
+	if !now.Before(e.Spec.ExpiresAt.Time) {
+		return false
+	}
+	if maxLifetime > 0 && e.Lifetime(now) > maxLifetime {
+		return false
+	}
+	return wildcard.CompareImageRef(e.Spec.Image, image)
+}
+
+// Lifetime is how long the exception applies for from when it was created, or from now when it has not been
+// created yet, the API server sets the creation time so it can not be backdated
+func (e ImagePolicyException) Lifetime(now time.Time) time.Duration {
+	created := e.CreationTimestamp.Time
+	if created.IsZero() {
+		created = now
+	}
+	return e.Spec.ExpiresAt.Sub(created)
+}
+
+// MatchImagePolicyException - Given an ImagePolicyExceptionList, find an exception that applies to the
+// image at the given time, exceptions are considered in name order. If there are none, return nil.
+func (el ImagePolicyExceptionList) MatchImagePolicyException(image string, now time.Time, maxLifetime time.Duration) *ImagePolicyException {
+	items := make([]ImagePolicyException, len(el.Items))
+	copy(items, el.Items)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	for i := range items {
+		if items[i].Applies(image, now, maxLifetime) {
+			return &items[i]
+		}
+	}
+	return nil
+}
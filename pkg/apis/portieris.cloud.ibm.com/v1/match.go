@@ -0,0 +1,312 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/IBM/portieris/helpers/wildcard"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Workload describes the pod being admitted, it is used to evaluate repositories
+// that are scoped by label selector or service account name.
+// +k8s:deepcopy-gen=false
+type Workload struct {
+	Labels             map[string]string
+	ServiceAccountName string
+}
+
+// PolicyMatch describes the repository that was selected for an image.
+// +k8s:deepcopy-gen=false
+type PolicyMatch struct {
+	// Policy is the policy of the selected repository
+	Policy *Policy
+	// Source identifies the resource holding the repository, namespace/name for an ImagePolicy
+	Source string
+	// Generation is the generation of the resource holding the repository
+	Generation int64
+	// Repository is the name of the selected repository
+	Repository string
+	// Profile is the ImagePolicyProfile referenced by the selected repository, Policy must be overlaid on it
+	Profile string
+	// Rego is the Rego module of the ClusterImagePolicy holding the selected repository
+	Rego *Rego
+	// Conflicts describes repositories in other resources that matched equally well with a different policy
+	Conflicts []string
+}
+
+// policySource is the spec of a policy resource and the name used to order and report it
+type policySource struct {
+	name       string
+	generation int64
+	spec       ImagePolicySpec
+	// rego is only set for a ClusterImagePolicy
+	rego *Rego
+}
+
+// FindImagePolicy - Given an ImagePolicyList, find the repository whose name
+// most closely matches the image name, and returns its policy.
+// If there are no matches, return a nil value.
+func (apl ImagePolicyList) FindImagePolicy(image string) *Policy {
+	return apl.FindImagePolicyForWorkload(image, Workload{})
+}
+
+// FindImagePolicyForWorkload - Given an ImagePolicyList, find the repository whose name
+// most closely matches the image name and whose scope includes the workload, and returns its policy.
+// If there are no matches, return a nil value.
+func (apl ImagePolicyList) FindImagePolicyForWorkload(image string, workload Workload) *Policy {
+	return apl.MatchImagePolicy(image, workload).Policy
+}
+
+// MatchImagePolicy - Given an ImagePolicyList, find the repository that best matches the image
+// and workload, and describe where it came from and any conflicting repositories.
+func (apl ImagePolicyList) MatchImagePolicy(image string, workload Workload) PolicyMatch {
+	sources := make([]policySource, 0, len(apl.Items))
+	for _, item := range apl.Items {
+		sources = append(sources, policySource{name: item.Namespace + "/" + item.Name, generation: item.Generation, spec: item.Spec})
+	}
+	match := findPolicy(sources, image, workload)
+	if match.Policy != nil {
+		policy := match.Policy.WithoutSecretNamespaces()
+		match.Policy = &policy
+	}
+	return match
+}
+
+// WithoutSecretNamespaces returns a copy of the policy without the namespaces of its signer, key and store
+// secrets, so that they are read from the namespace of the workload. Only a ClusterImagePolicy, or an
+// ImagePolicyProfile, can reference secrets in other namespaces.
+func (p Policy) WithoutSecretNamespaces() Policy {
+	result := *p.DeepCopy()
+	for i := range result.Trust.SignerSecrets {
+		result.Trust.SignerSecrets[i].Namespace = ""
+	}
+	for i := range result.Simple.Requirements {
+		result.Simple.Requirements[i].KeySecretNamespace = ""
+	}
+	result.Simple.StoreSecretNamespace = ""
+	return result
+}
+
+// FindClusterImagePolicy - Given an ClusterImagePolicyList, find the repository whose name
+// most closely matches the image name, and returns its policy.
+// If there are no matches, return a nil value.
+func (apl ClusterImagePolicyList) FindClusterImagePolicy(image string) *Policy {
+	return apl.FindClusterImagePolicyForWorkload(image, Workload{})
+}
+
+// FindClusterImagePolicyForWorkload - Given an ClusterImagePolicyList, find the repository whose name
+// most closely matches the image name and whose scope includes the workload, and returns its policy.
+// If there are no matches, return a nil value.
+func (apl ClusterImagePolicyList) FindClusterImagePolicyForWorkload(image string, workload Workload) *Policy {
+	return apl.MatchClusterImagePolicy(image, workload).Policy
+}
+
+// MatchClusterImagePolicy - Given an ClusterImagePolicyList, find the repository that best matches the image
+// and workload, and describe where it came from and any conflicting repositories.
+func (apl ClusterImagePolicyList) MatchClusterImagePolicy(image string, workload Workload) PolicyMatch {
+	sources := make([]policySource, 0, len(apl.Items))
+	for _, item := range apl.Items {
+		sources = append(sources, policySource{name: item.Name, generation: item.Generation, spec: item.Spec, rego: item.Spec.Rego})
+	}
+	return findPolicy(sources, image, workload)
+}
+
+// repositoryMatch records how well a repository matched an image
+type repositoryMatch struct {
+	priority int32
+	exact    bool
+	quality  int
+	scoped   bool
+}
+
+// compare orders two matches, it returns a positive value when m should be
+// preferred over other, negative when other is preferred and zero for a tie.
+// A higher priority wins, then an exact name beats a wildcard, a longer pattern
+// beats a shorter one and a repository scoped to the workload beats an unscoped one.
+func (m repositoryMatch) compare(other repositoryMatch) int {
+	switch {
+	case m.priority != other.priority:
+		return int(m.priority) - int(other.priority)
+	case m.exact != other.exact:
+		if m.exact {
+			return 1
+		}
+		return -1
+	case m.quality != other.quality:
+		return m.quality - other.quality
+	case m.scoped != other.scoped:
+		if m.scoped {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// candidate is a repository that matched the image
+type candidate struct {
+	match      repositoryMatch
+	source     string
+	generation int64
+	repo       Repository
+	rego       *Rego
+}
+
+// findPolicy finds the repository across all sources that best matches the image
+// name and whose scope includes the workload. Sources are considered in name order so that
+// equally good matches are resolved the same way regardless of the order they were listed in.
+func findPolicy(sources []policySource, image string, workload Workload) PolicyMatch {
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].name < sources[j].name })
+
+	var best []candidate
+	for _, source := range sources {
+		// iterate over the repositories
+		for _, repo := range source.spec.Repositories {
+			if !repo.MatchesWorkload(workload) {
+				continue
+			}
+
+			// Check if the image name matches the repository name
+			match, ok := repo.matchName(image)
+			if !ok {
+				continue
+			}
+			match.priority = repo.Priority
+			match.scoped = repo.IsScoped()
+
+			c := candidate{match: match, source: source.name, generation: source.generation, repo: repo, rego: source.rego}
+			switch {
+			case len(best) == 0 || match.compare(best[0].match) > 0:
+				best = []candidate{c}
+			case match.compare(best[0].match) == 0:
+				best = append(best, c)
+			}
+		}
+	}
+
+	if len(best) == 0 {
+		return PolicyMatch{}
+	}
+	selected := best[0]
+	policy := selected.repo.Policy
+	result := PolicyMatch{
+		Policy:     &policy,
+		Source:     selected.source,
+		Generation: selected.generation,
+		Repository: selected.repo.Name,
+		Profile:    selected.repo.Profile,
+		Rego:       selected.rego,
+	}
+	for _, c := range best[1:] {
+		if c.source != selected.source && (c.repo.Profile != selected.repo.Profile || !reflect.DeepEqual(c.rego, selected.rego) || !reflect.DeepEqual(c.repo.Policy, selected.repo.Policy)) {
+			result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s repository %q", c.source, c.repo.Name))
+		}
+	}
+	return result
+}
+
+// matchName reports whether the image matches the repository name and none of its excludes
+func (r Repository) matchName(image string) (repositoryMatch, bool) {
+	var match repositoryMatch
+	switch r.NameType {
+	case "", NameTypeWildcard:
+		if !strings.Contains(r.Name, "*") && r.Name == image {
+			match = repositoryMatch{quality: len(image), exact: true}
+		} else if wildcard.CompareImageRef(r.Name, image) {
+			match = repositoryMatch{quality: len(r.Name) - strings.Count(r.Name, "*")}
+		} else {
+			return match, false
+		}
+	case NameTypePath:
+		if !strings.Contains(r.Name, "*") && r.Name == image {
+			match = repositoryMatch{quality: len(image), exact: true}
+		} else if wildcard.ComparePath(r.Name, image) {
+			match = repositoryMatch{quality: len(r.Name) - strings.Count(r.Name, "*")}
+		} else {
+			return match, false
+		}
+	case NameTypeRegexp:
+		if !wildcard.CompareRegexp(r.Name, image) {
+			return match, false
+		}
+		// a regular expression is as specific as the literal text it must start with
+		prefix := ""
+		if re, err := wildcard.Compile(r.Name); err == nil {
+			prefix, _ = re.LiteralPrefix()
+		}
+		match = repositoryMatch{quality: len(prefix)}
+	default:
+		return match, false
+	}
+
+	for _, exclude := range r.Excludes {
+		if r.compareName(exclude, image) {
+			return match, false
+		}
+	}
+	return match, true
+}
+
+// compareName matches a pattern of the repository NameType against the image
+func (r Repository) compareName(pattern, image string) bool {
+	switch r.NameType {
+	case NameTypePath:
+		return wildcard.ComparePath(pattern, image)
+	case NameTypeRegexp:
+		return wildcard.CompareRegexp(pattern, image)
+	default:
+		return wildcard.CompareImageRef(pattern, image)
+	}
+}
+
+// IsScoped reports whether the repository is restricted to a subset of workloads
+func (r Repository) IsScoped() bool {
+	return r.Selector != nil || len(r.ServiceAccountNames) > 0
+}
+
+// MatchesWorkload reports whether the workload is within the scope of the repository.
+// Unscoped repositories match every workload, an invalid selector matches none.
+func (r Repository) MatchesWorkload(workload Workload) bool {
+	if r.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(r.Selector)
+		if err != nil || !selector.Matches(labels.Set(workload.Labels)) {
+			return false
+		}
+	}
+	if len(r.ServiceAccountNames) > 0 {
+		serviceAccountName := workload.ServiceAccountName
+		if serviceAccountName == "" {
+			serviceAccountName = "default"
+		}
+		found := false
+		for _, name := range r.ServiceAccountNames {
+			if name == serviceAccountName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
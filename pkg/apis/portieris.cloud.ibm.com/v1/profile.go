@@ -0,0 +1,35 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"reflect"
+)
+
+// Overlay returns a copy of the profile policy in which each part that is set in the
+// repository policy, for example simple or trust, replaces the same part of the profile.
+// Parts are replaced whole, so a repository that sets simple requirements replaces all of
+// the simple requirements of the profile.
+func (p Policy) Overlay(repository Policy) Policy {
+	result := *p.DeepCopy()
+	overlay := reflect.ValueOf(repository.DeepCopy()).Elem()
+	target := reflect.ValueOf(&result).Elem()
+	for i := 0; i < overlay.NumField(); i++ {
+		if !overlay.Field(i).IsZero() {
+			target.Field(i).Set(overlay.Field(i))
+		}
+	}
+	return result
+}
@@ -1,4 +1,4 @@
-// Copyright 2021 Portieris Authors.
+// Copyright 2021, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -48,6 +48,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ImagePolicyList{},
 		&ClusterImagePolicy{},
 		&ClusterImagePolicyList{},
+		&ImagePolicyException{},
+		&ImagePolicyExceptionList{},
+		&ImagePolicyProfile{},
+		&ImagePolicyProfileList{},
+		&ImageVerification{},
+		&ImageVerificationList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -15,10 +15,7 @@
 package v1
 
 import (
-	"strings"
-
-	"github.com/IBM/portieris/helpers/wildcard"
-
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -34,7 +31,6 @@ func boolPointer(boolean bool) *bool {
 }
 
 // +genclient
-// +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ImagePolicy is a specification for a ImagePolicy resource
@@ -42,7 +38,8 @@ type ImagePolicy struct {
 	metav1.TypeMeta
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ImagePolicySpec `json:"spec"`
+	Spec   ImagePolicySpec   `json:"spec"`
+	Status ImagePolicyStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -57,7 +54,6 @@ type ImagePolicyList struct {
 
 // +genclient
 // +genclient:nonNamespaced
-// +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ClusterImagePolicy is a specification for a ClusterImagePolicy resource
@@ -65,7 +61,8 @@ type ClusterImagePolicy struct {
 	metav1.TypeMeta
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ImagePolicySpec `json:"spec"`
+	Spec   ImagePolicySpec   `json:"spec"`
+	Status ImagePolicyStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -78,25 +75,204 @@ type ClusterImagePolicyList struct {
 	Items []ClusterImagePolicy `json:"items"`
 }
 
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImagePolicyException temporarily allows an image in its namespace without enforcing a policy
+type ImagePolicyException struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ImagePolicyExceptionSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImagePolicyExceptionList is a list of ImagePolicyException resources
+type ImagePolicyExceptionList struct {
+	metav1.TypeMeta
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ImagePolicyException `json:"items"`
+}
+
+// ImagePolicyExceptionSpec is the spec for a ImagePolicyException resource
+type ImagePolicyExceptionSpec struct {
+	// Image is the name of the image, or a repository wildcard, that is allowed
+	Image string `json:"image"`
+	// ExpiresAt is when the exception stops applying
+	ExpiresAt metav1.Time `json:"expiresAt"`
+	// Reason records why the exception is needed, for example an incident reference
+	Reason string `json:"reason"`
+	// Approver records who approved the exception
+	Approver string `json:"approver"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImagePolicyProfile is a reusable policy that repositories reference by name
+type ImagePolicyProfile struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ImagePolicyProfileSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImagePolicyProfileList is a list of ImagePolicyProfile resources
+type ImagePolicyProfileList struct {
+	metav1.TypeMeta
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ImagePolicyProfile `json:"items"`
+}
+
+// ImagePolicyProfileSpec is the spec for a ImagePolicyProfile resource
+type ImagePolicyProfileSpec struct {
+	Policy Policy `json:"policy"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageVerification records that an image digest was allowed by a policy, so that every Portieris replica
+// can reuse the verification until it expires. It is named by the verification that it records.
+type ImageVerification struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ImageVerificationSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageVerificationList is a list of ImageVerification resources
+type ImageVerificationList struct {
+	metav1.TypeMeta
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ImageVerification `json:"items"`
+}
+
+// ImageVerificationSpec is the spec for a ImageVerification resource
+type ImageVerificationSpec struct {
+	// Image is the name of the image that was verified
+	Image string `json:"image"`
+	// Digest is the digest that the image was verified at
+	Digest string `json:"digest"`
+	// Namespace is the namespace the image was verified for, it is empty for verifications that don't depend on it
+	Namespace string `json:"namespace,omitempty"`
+	// Policy identifies the resource holding the policy, namespace/name for an ImagePolicy
+	Policy string `json:"policy"`
+	// PolicyGeneration is the generation of the policy resource that the image was verified against
+	PolicyGeneration int64 `json:"policyGeneration"`
+	// Verifiers are the parts of the policy that allowed the image, for example trust or vulnerability
+	Verifiers []string `json:"verifiers"`
+	// ExpiresAt is when the verification stops being reused
+	ExpiresAt metav1.Time `json:"expiresAt"`
+	// Signature is an HMAC of the name and the other fields of the record, made with a key that only Portieris
+	// can read, records without a valid signature are not reused
+	Signature string `json:"signature,omitempty"`
+}
+
+// Condition types reported in ImagePolicyStatus
+const (
+	// ConditionValid is True when the policy can be enforced as written
+	ConditionValid = "Valid"
+	// ConditionSecretsResolved is True when the secrets referenced by the policy exist and have the expected data
+	ConditionSecretsResolved = "SecretsResolved"
+	// ConditionKeysParsed is True when the keys in the secrets referenced by the policy can be parsed
+	ConditionKeysParsed = "KeysParsed"
+)
+
+// ImagePolicyStatus is the status for a ImagePolicy or ClusterImagePolicy resource
+type ImagePolicyStatus struct {
+	// ObservedGeneration is the generation of the spec that the conditions describe
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report whether the policy is Valid, SecretsResolved and KeysParsed
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
 // ImagePolicySpec is the spec for a ImagePolicy or ClusterImagePolicy resource
 type ImagePolicySpec struct {
 	Repositories []Repository `json:"repositories"`
+	// Rego is only used in a ClusterImagePolicy, it decides whether images selected by its repositories are allowed
+	Rego *Rego `json:"rego,omitempty"`
+}
+
+// Rego is a Rego module that is evaluated once the policy of the selected repository allows the image
+type Rego struct {
+	// Module is the source of an inline Rego module
+	Module string `json:"module,omitempty"`
+	// ConfigMap reads the Rego module from a ConfigMap, instead of Module
+	ConfigMap *ConfigMapKeyReference `json:"configMap,omitempty"`
+	// Query produces the decision, an object with an allow boolean and messages, the default is data.portieris.decision
+	Query string `json:"query,omitempty"`
+}
+
+// ConfigMapKeyReference selects a key of a ConfigMap
+type ConfigMapKeyReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
 }
 
 // Repository .
 type Repository struct {
 	Name   string `json:"name,omitempty"` // Name may contain a * to signify one or more characters
 	Policy Policy `json:"policy,omitempty"`
+	// NameType selects how Name and Excludes are matched, one of the NameType constants, the default is wildcard
+	NameType string `json:"nameType,omitempty"`
+	// Excludes are patterns, of the same NameType as Name, for images that the repository does not apply to
+	Excludes []string `json:"excludes,omitempty"`
+	// Selector limits the repository to workloads whose pod template labels match
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// ServiceAccountNames limits the repository to workloads running as one of the named service accounts
+	ServiceAccountNames []string `json:"serviceAccountNames,omitempty"`
+	// Priority orders matching repositories ahead of name specificity, higher wins, the default is 0
+	Priority int32 `json:"priority,omitempty"`
+	// Profile names an ImagePolicyProfile whose policy is used, each part of Policy that is set replaces the same part of the profile
+	Profile string `json:"profile,omitempty"`
 }
 
+const (
+	// NameTypeWildcard matches names where * signifies any characters, including '/'
+	NameTypeWildcard = "wildcard"
+	// NameTypePath matches names where * signifies any characters within a path segment and ** any number of segments
+	NameTypePath = "path"
+	// NameTypeRegexp matches names with an anchored regular expression
+	NameTypeRegexp = "regexp"
+)
+
 // Policy .
 type Policy struct {
 	Trust         Trust         `json:"trust,omitempty"`
 	Simple        Simple        `json:"simple,omitempty"`
 	Vulnerability Vulnerability `json:"vulnerability,omitempty"`
+	Tags          Tags          `json:"tags,omitempty"`
+	Config        Config        `json:"config,omitempty"`
+	BaseImage     BaseImage     `json:"baseImage,omitempty"`
+	Platforms     Platforms     `json:"platforms,omitempty"`
+	Conditions    []Condition   `json:"conditions,omitempty"`
 	MutateImage   *bool         `json:"mutateImage,omitempty"`
 }
 
+// Condition is a CEL expression that must evaluate to true for the image to be allowed, it is evaluated
+// once the other verifiers have allowed the image
+type Condition struct {
+	// Expression is a CEL expression, see POLICIES.md for the variables it can use
+	Expression string `json:"expression"`
+	// Message is the reason given when the expression is false, it defaults to the expression
+	Message string `json:"message,omitempty"`
+}
+
 // Trust .
 type Trust struct {
 	Enabled       *bool         `json:"enabled,omitempty"`
@@ -107,6 +283,8 @@ type Trust struct {
 // TrustSigner .
 type TrustSigner struct {
 	Name string `json:"name"`
+	// Namespace of the secret, the default is the namespace of the workload
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // Simple .
@@ -114,8 +292,30 @@ type Simple struct {
 	Requirements []SimpleRequirement `json:"requirements"`
 	StoreURL     string              `json:"storeURL,omitempty"`
 	StoreSecret  string              `json:"storeSecret,omitempty"`
+	// StoreSecretNamespace is the namespace of StoreSecret, the default is the namespace of the workload
+	StoreSecretNamespace string `json:"storeSecretNamespace,omitempty"`
+	// MultiArch selects how an image index, or manifest list, is verified
+	MultiArch MultiArch `json:"multiArch,omitempty"`
+}
+
+// MultiArch selects how the signatures of a multi-architecture image are verified
+type MultiArch struct {
+	// Verify is one of the MultiArchVerify constants, the default is index
+	Verify string `json:"verify,omitempty"`
+	// MutateToIndex mutates the image to the index digest once the platform manifests are verified,
+	// otherwise the image is not mutated because no single platform digest suits every node
+	MutateToIndex *bool `json:"mutateToIndex,omitempty"`
 }
 
+const (
+	// MultiArchVerifyIndex verifies the signature of the index
+	MultiArchVerifyIndex = "index"
+	// MultiArchVerifyAll verifies the signature of every platform manifest in the index
+	MultiArchVerifyAll = "all"
+	// MultiArchVerifyNodes verifies the signatures of the platform manifests that match the cluster nodes
+	MultiArchVerifyNodes = "nodes"
+)
+
 // SimpleRequirement .
 type SimpleRequirement struct {
 	Type               string              `json:"type"`
@@ -133,6 +333,46 @@ type IdentityRequirement struct {
 	SignedPrefix     string `json:"signedPrefix,omitempty"`
 }
 
+// Tags policy, constrains how images are referenced
+type Tags struct {
+	// RequireDigest denies images that are not referenced by digest
+	RequireDigest *bool `json:"requireDigest,omitempty"`
+	// Deny lists wildcard patterns of tags that can not be used, for example latest
+	Deny []string `json:"deny,omitempty"`
+	// Pattern is a regular expression that tags must match, for example a semantic version
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// Config policy, constrains the image configuration and manifest retrieved from the registry
+type Config struct {
+	// MaxAge is the longest time since the image was created, for example 720h
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// MaxSize is the largest total compressed size of the image layers, for example 500Mi
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// MaxLayers is the largest number of layers in the image
+	MaxLayers *int32 `json:"maxLayers,omitempty"`
+	// DenyRootUser denies images that are configured to run as root, including when no user is set
+	DenyRootUser *bool `json:"denyRootUser,omitempty"`
+	// RequiredLabels are image configuration labels that must be present, with a value that matches the wildcard pattern
+	RequiredLabels map[string]string `json:"requiredLabels,omitempty"`
+	// RequiredAnnotations are manifest annotations that must be present, with a value that matches the wildcard pattern
+	RequiredAnnotations map[string]string `json:"requiredAnnotations,omitempty"`
+}
+
+// BaseImage policy, constrains the base image recorded in the OCI image manifest annotations
+type BaseImage struct {
+	// Allowed lists wildcard patterns of approved base images, a pattern with a digest only matches that digest
+	Allowed []string `json:"allowed,omitempty"`
+	// VerifyLayers checks that the layers of the recorded base image are the first layers of the image
+	VerifyLayers *bool `json:"verifyLayers,omitempty"`
+}
+
+// Platforms policy, constrains the platforms that a multi-architecture image provides
+type Platforms struct {
+	// Required lists os/architecture platforms, with an optional /variant, that the image must provide, for example linux/s390x
+	Required []string `json:"required,omitempty"`
+}
+
 // Vulnerability policy
 type Vulnerability struct {
 	ICCRVA ICCRVA `json:"ICCRVA,omitempty"`
@@ -143,99 +383,3 @@ type ICCRVA struct {
 	Enabled *bool  `json:"enabled,omitempty"`
 	Account string `json:"account,omitempty"`
 }
-
-// FindImagePolicy - Given an ImagePolicyList, find the repository whose name
-// most closely matches the image name, and returns its policy.
-// If there are no matches, return a nil value.
-func (apl ImagePolicyList) FindImagePolicy(image string) *Policy {
-	// Variables
-	bestMatchQuality := -1
-	bestMatchedPolicy := Policy{}
-
-	// Check if there are policies for the given image
-Exact:
-	for _, item := range apl.Items {
-
-		// iterate over the repositories
-		for _, repo := range item.Spec.Repositories {
-
-			// get the name for the current repository
-			repositoryName := repo.Name
-			hasWildcard := strings.Contains(repositoryName, "*")
-			// glog.Infof("repositoryName: %s", repositoryName)
-
-			// Check if the image name matches the repository name
-			match := false
-			matchQuality := -1
-			if !hasWildcard && repositoryName == image {
-				// glog.Info("Found exact match")
-				bestMatchQuality = len(image)
-				bestMatchedPolicy = repo.Policy
-				break Exact
-			} else {
-				if wildcard.CompareImageRef(repositoryName, image) {
-					match = true
-					matchQuality = len(repositoryName) - strings.Count(repositoryName, "*")
-				}
-			}
-			// glog.Infof("match: %t  matchQuality: %d", match, matchQuality)
-			if match && matchQuality > bestMatchQuality {
-				// glog.Info("Updating to this match")
-				bestMatchQuality = matchQuality
-				bestMatchedPolicy = repo.Policy
-			}
-		}
-	}
-	if bestMatchQuality > -1 {
-		return &bestMatchedPolicy
-	}
-	return nil
-}
-
-// FindClusterImagePolicy - Given an ClusterImagePolicyList, find the repository whose name
-// most closely matches the image name, and returns its policy.
-// If there are no matches, return a nil value.
-func (apl ClusterImagePolicyList) FindClusterImagePolicy(image string) *Policy {
-	// Variables
-	bestMatchQuality := -1
-	bestMatchedPolicy := Policy{}
-
-	// Check if there are policies for the given image
-Exact:
-	for _, item := range apl.Items {
-
-		// iterate over the repositories
-		for _, repo := range item.Spec.Repositories {
-
-			// get the name for the current repository
-			repositoryName := repo.Name
-			hasWildcard := strings.Contains(repositoryName, "*")
-			// glog.Infof("repositoryName: %s", repositoryName)
-
-			// Check if the image name matches the repository name
-			match := false
-			matchQuality := -1
-			if !hasWildcard && repositoryName == image {
-				// glog.Info("Found exact match")
-				bestMatchQuality = len(image)
-				bestMatchedPolicy = repo.Policy
-				break Exact
-			} else {
-				if wildcard.CompareImageRef(repositoryName, image) {
-					match = true
-					matchQuality = len(repositoryName) - strings.Count(repositoryName, "*")
-				}
-			}
-			// glog.Infof("match: %t  matchQuality: %d", match, matchQuality)
-			if match && matchQuality > bestMatchQuality {
-				// glog.Info("Updating to this match")
-				bestMatchQuality = matchQuality
-				bestMatchedPolicy = repo.Policy
-			}
-		}
-	}
-	if bestMatchQuality > -1 {
-		return &bestMatchedPolicy
-	}
-	return nil
-}
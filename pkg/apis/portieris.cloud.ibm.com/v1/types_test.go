@@ -15,10 +15,13 @@
 package v1_test
 
 import (
+	"time"
+
 	. "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("Types", func() {
@@ -491,6 +494,45 @@ var _ = Describe("Types", func() {
 		})
 	})
 
+	Describe("secret namespaces", func() {
+		policy := Policy{
+			Trust: Trust{
+				SignerSecrets: []TrustSigner{{Name: "signer", Namespace: "keys"}},
+			},
+			Simple: Simple{
+				Requirements:         []SimpleRequirement{{Type: "signedBy", KeySecret: "key", KeySecretNamespace: "keys"}},
+				StoreSecret:          "store",
+				StoreSecretNamespace: "keys",
+			},
+		}
+
+		It("Should be removed from an ImagePolicy", func() {
+			apl := ImagePolicyList{
+				Items: []ImagePolicy{
+					{Spec: ImagePolicySpec{Repositories: []Repository{{Name: "icr.io/*", Policy: policy}}}},
+				},
+			}
+			match := apl.MatchImagePolicy("icr.io/team-a/app:1", Workload{})
+			Expect(match.Policy).ToNot(BeNil())
+			Expect(match.Policy.Trust.SignerSecrets).To(Equal([]TrustSigner{{Name: "signer"}}))
+			Expect(match.Policy.Simple.Requirements).To(Equal([]SimpleRequirement{{Type: "signedBy", KeySecret: "key"}}))
+			Expect(match.Policy.Simple.StoreSecret).To(Equal("store"))
+			Expect(match.Policy.Simple.StoreSecretNamespace).To(BeEmpty())
+			Expect(apl.Items[0].Spec.Repositories[0].Policy.Trust.SignerSecrets[0].Namespace).To(Equal("keys"))
+		})
+
+		It("Should be kept in a ClusterImagePolicy", func() {
+			cpl := ClusterImagePolicyList{
+				Items: []ClusterImagePolicy{
+					{Spec: ImagePolicySpec{Repositories: []Repository{{Name: "icr.io/*", Policy: policy}}}},
+				},
+			}
+			match := cpl.MatchClusterImagePolicy("icr.io/team-a/app:1", Workload{})
+			Expect(match.Policy).ToNot(BeNil())
+			Expect(*match.Policy).To(Equal(policy))
+		})
+	})
+
 	Describe("unanchored wildcard bypass prevention", func() {
 		Context("FindImagePolicy must deny attacker-hosted images that embed trusted registry in path", func() {
 			attackerCases := []struct {
@@ -583,4 +625,255 @@ var _ = Describe("Types", func() {
 			}
 		})
 	})
+
+	Describe("when several repositories match", func() {
+		trustEnabled := Policy{Trust: Trust{Enabled: TruePointer}}
+		trustDisabled := Policy{Trust: Trust{Enabled: FalsePointer}}
+
+		It("should resolve equally specific matches by policy name regardless of list order and report the conflict", func() {
+			apl := ImagePolicyList{
+				Items: []ImagePolicy{
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "zebra"},
+						Spec:       ImagePolicySpec{Repositories: []Repository{{Name: "test.com/*", Policy: trustDisabled}}},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "aardvark", Generation: 3},
+						Spec:       ImagePolicySpec{Repositories: []Repository{{Name: "test.com/*", Policy: trustEnabled}}},
+					},
+				},
+			}
+			match := apl.MatchImagePolicy("test.com/hello", Workload{})
+			Expect(match.Policy).To(Equal(&trustEnabled))
+			Expect(match.Source).To(Equal("default/aardvark"))
+			Expect(match.Generation).To(Equal(int64(3)))
+			Expect(match.Conflicts).To(ConsistOf(`default/zebra repository "test.com/*"`))
+		})
+
+		It("should prefer the higher priority over the more specific name", func() {
+			apl := ClusterImagePolicyList{
+				Items: []ClusterImagePolicy{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "specific"},
+						Spec:       ImagePolicySpec{Repositories: []Repository{{Name: "test.com/hello", Policy: trustDisabled}}},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "priority", Generation: 2},
+						Spec:       ImagePolicySpec{Repositories: []Repository{{Name: "test.com/*", Policy: trustEnabled, Priority: 1}}},
+					},
+				},
+			}
+			match := apl.MatchClusterImagePolicy("test.com/hello", Workload{})
+			Expect(match.Policy).To(Equal(&trustEnabled))
+			Expect(match.Source).To(Equal("priority"))
+			Expect(match.Generation).To(Equal(int64(2)))
+			Expect(match.Conflicts).To(BeEmpty())
+		})
+
+		It("should prefer a repository scoped to the workload over an unscoped one", func() {
+			apl := ImagePolicyList{
+				Items: []ImagePolicy{
+					{
+						Spec: ImagePolicySpec{Repositories: []Repository{
+							{Name: "test.com/*", Policy: trustDisabled},
+							{Name: "test.com/*", Policy: trustEnabled, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"exposure": "internet"}}},
+						}},
+					},
+				},
+			}
+			Expect(apl.FindImagePolicyForWorkload("test.com/hello", Workload{Labels: map[string]string{"exposure": "internet"}})).To(Equal(&trustEnabled))
+			Expect(apl.FindImagePolicyForWorkload("test.com/hello", Workload{})).To(Equal(&trustDisabled))
+		})
+	})
+
+	Describe("when repositories use name types and excludes", func() {
+		find := func(repo Repository, image string) *Policy {
+			apl := ImagePolicyList{Items: []ImagePolicy{{Spec: ImagePolicySpec{Repositories: []Repository{repo}}}}}
+			return apl.FindImagePolicy(image)
+		}
+
+		It("should not match an excluded image", func() {
+			repo := Repository{Name: "icr.io/team-a/*", Excludes: []string{"icr.io/team-a/sandbox/*"}}
+			Expect(find(repo, "icr.io/team-a/app:v1")).ToNot(BeNil())
+			Expect(find(repo, "icr.io/team-a/sandbox/app:v1")).To(BeNil())
+		})
+
+		It("should match path patterns by segment", func() {
+			repo := Repository{Name: "icr.io/team-a/*", NameType: NameTypePath}
+			Expect(find(repo, "icr.io/team-a/app:v1")).ToNot(BeNil())
+			Expect(find(repo, "icr.io/team-a/sandbox/app:v1")).To(BeNil())
+			repo.Name = "icr.io/team-a/**"
+			Expect(find(repo, "icr.io/team-a/sandbox/app:v1")).ToNot(BeNil())
+		})
+
+		It("should apply excludes using the repository name type", func() {
+			repo := Repository{Name: `icr\.io/team-a/.+`, NameType: NameTypeRegexp, Excludes: []string{`icr\.io/team-a/sandbox/.+`}}
+			Expect(find(repo, "icr.io/team-a/app:v1")).ToNot(BeNil())
+			Expect(find(repo, "icr.io/team-a/sandbox/app:v1")).To(BeNil())
+		})
+
+		It("should not match an unknown name type", func() {
+			Expect(find(Repository{Name: "*", NameType: "unknown"}, "icr.io/team-a/app:v1")).To(BeNil())
+		})
+
+		It("should prefer a wildcard with more literal text over a regular expression", func() {
+			trustEnabled := Policy{Trust: Trust{Enabled: TruePointer}}
+			apl := ImagePolicyList{Items: []ImagePolicy{{Spec: ImagePolicySpec{Repositories: []Repository{
+				{Name: `icr\.io/.*`, NameType: NameTypeRegexp},
+				{Name: "icr.io/team-a/*", Policy: trustEnabled},
+			}}}}}
+			Expect(apl.FindImagePolicy("icr.io/team-a/app:v1")).To(Equal(&trustEnabled))
+		})
+	})
+
+	Describe("when a ClusterImagePolicy has a Rego module", func() {
+		rego := &Rego{Module: "package portieris"}
+
+		It("should record the Rego module of the selected cluster policy", func() {
+			cpl := ClusterImagePolicyList{Items: []ClusterImagePolicy{
+				{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: ImagePolicySpec{Repositories: []Repository{{Name: "icr.io/*"}}, Rego: rego}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "b"}, Spec: ImagePolicySpec{Repositories: []Repository{{Name: "icr.io/team-a/*"}}}},
+			}}
+			Expect(cpl.MatchClusterImagePolicy("icr.io/team-b/app:v1", Workload{}).Rego).To(Equal(rego))
+			Expect(cpl.MatchClusterImagePolicy("icr.io/team-a/app:v1", Workload{}).Rego).To(BeNil())
+		})
+
+		It("should ignore a Rego module in an ImagePolicy", func() {
+			apl := ImagePolicyList{Items: []ImagePolicy{{Spec: ImagePolicySpec{Repositories: []Repository{{Name: "icr.io/*"}}, Rego: rego}}}}
+			Expect(apl.MatchImagePolicy("icr.io/app:v1", Workload{}).Rego).To(BeNil())
+		})
+
+		It("should report a conflict between cluster policies with different Rego modules", func() {
+			cpl := ClusterImagePolicyList{Items: []ClusterImagePolicy{
+				{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: ImagePolicySpec{Repositories: []Repository{{Name: "icr.io/*"}}, Rego: rego}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "b"}, Spec: ImagePolicySpec{Repositories: []Repository{{Name: "icr.io/*"}}}},
+			}}
+			Expect(cpl.MatchClusterImagePolicy("icr.io/app:v1", Workload{}).Conflicts).To(Equal([]string{`b repository "icr.io/*"`}))
+		})
+	})
+
+	Describe("when a namespace has image policy exceptions", func() {
+		now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+		exception := func(name, image string, expiresAt time.Time) ImagePolicyException {
+			return ImagePolicyException{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Spec: ImagePolicyExceptionSpec{
+					Image:     image,
+					ExpiresAt: metav1.NewTime(expiresAt),
+					Reason:    "INC-1234",
+					Approver:  "oncall@example.com",
+				},
+			}
+		}
+
+		It("should apply to a matching image until it expires", func() {
+			e := exception("hotfix", "icr.io/team-a/*", now.Add(time.Hour))
+			Expect(e.Applies("icr.io/team-a/app:v1", now, DefaultMaxExceptionLifetime)).To(BeTrue())
+			Expect(e.Applies("icr.io/team-b/app:v1", now, DefaultMaxExceptionLifetime)).To(BeFalse())
+			Expect(e.Applies("icr.io/team-a/app:v1", now.Add(time.Hour), DefaultMaxExceptionLifetime)).To(BeFalse())
+		})
+
+		It("should allow any tag of a named image", func() {
+			e := exception("hotfix", "icr.io/team-a/app", now.Add(time.Hour))
+			Expect(e.Applies("icr.io/team-a/app:v1", now, DefaultMaxExceptionLifetime)).To(BeTrue())
+			Expect(e.Applies("icr.io/team-a/app", now, DefaultMaxExceptionLifetime)).To(BeTrue())
+			Expect(e.Applies("icr.io/team-a/app-debug:v1", now, DefaultMaxExceptionLifetime)).To(BeFalse())
+		})
+
+		It("should not apply without a reason or approver", func() {
+			e := exception("hotfix", "*", now.Add(time.Hour))
+			e.Spec.Reason = ""
+			Expect(e.Applies("icr.io/app:v1", now, DefaultMaxExceptionLifetime)).To(BeFalse())
+			e = exception("hotfix", "*", now.Add(time.Hour))
+			e.Spec.Approver = ""
+			Expect(e.Applies("icr.io/app:v1", now, DefaultMaxExceptionLifetime)).To(BeFalse())
+			e = exception("hotfix", "*", time.Time{})
+			Expect(e.Applies("icr.io/app:v1", now, DefaultMaxExceptionLifetime)).To(BeFalse())
+		})
+
+		It("should not apply when it expires more than the maximum lifetime after it was created", func() {
+			e := exception("hotfix", "*", now.Add(DefaultMaxExceptionLifetime))
+			Expect(e.Applies("icr.io/app:v1", now, DefaultMaxExceptionLifetime)).To(BeFalse())
+			Expect(e.Applies("icr.io/app:v1", now, 0)).To(BeTrue())
+			e = exception("hotfix", "*", now.Add(DefaultMaxExceptionLifetime-time.Hour))
+			Expect(e.Applies("icr.io/app:v1", now, DefaultMaxExceptionLifetime)).To(BeTrue())
+		})
+
+		It("should choose the first applicable exception by name", func() {
+			el := ImagePolicyExceptionList{Items: []ImagePolicyException{
+				exception("c", "icr.io/*", now.Add(time.Hour)),
+				exception("a", "icr.io/*", now.Add(-time.Hour)),
+				exception("b", "icr.io/*", now.Add(time.Hour)),
+			}}
+			Expect(el.MatchImagePolicyException("icr.io/app:v1", now, DefaultMaxExceptionLifetime).Name).To(Equal("b"))
+			Expect(el.MatchImagePolicyException("docker.io/app:v1", now, DefaultMaxExceptionLifetime)).To(BeNil())
+		})
+	})
+
+	Describe("when an image verification is recorded", func() {
+		now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+		verification := ImageVerification{Spec: ImageVerificationSpec{
+			Image:            "icr.io/team-a/app@sha256:1234",
+			Digest:           "1234",
+			Policy:           "ns/policy",
+			PolicyGeneration: 2,
+			Verifiers:        []string{"trust"},
+			ExpiresAt:        metav1.NewTime(now.Add(time.Hour)),
+		}}
+
+		It("should apply to the same generation of the policy until it expires", func() {
+			Expect(verification.Applies("ns/policy", 2, now)).To(BeTrue())
+			Expect(verification.Applies("ns/policy", 2, now.Add(time.Hour))).To(BeFalse())
+		})
+
+		It("should not apply to another policy or generation", func() {
+			Expect(verification.Applies("ns/other", 2, now)).To(BeFalse())
+			Expect(verification.Applies("ns/policy", 1, now)).To(BeFalse())
+			Expect(verification.Applies("ns/policy", 3, now)).To(BeFalse())
+		})
+
+		It("should not apply without an expiry", func() {
+			v := *verification.DeepCopy()
+			v.Spec.ExpiresAt = metav1.Time{}
+			Expect(v.Applies("ns/policy", 2, now)).To(BeFalse())
+		})
+	})
+
+	Describe("when repositories reference a profile", func() {
+		profile := Policy{
+			Trust:  Trust{Enabled: TruePointer},
+			Simple: Simple{Requirements: []SimpleRequirement{{Type: "signedBy", KeySecret: "golden"}}},
+		}
+
+		It("should record the profile of the selected repository", func() {
+			apl := ImagePolicyList{Items: []ImagePolicy{{Spec: ImagePolicySpec{Repositories: []Repository{
+				{Name: "icr.io/*", Profile: "signed"},
+			}}}}}
+			match := apl.MatchImagePolicy("icr.io/app:v1", Workload{})
+			Expect(match.Profile).To(Equal("signed"))
+			Expect(match.Policy).To(Equal(&Policy{}))
+		})
+
+		It("should report a conflict between repositories with different profiles", func() {
+			apl := ImagePolicyList{Items: []ImagePolicy{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "a"}, Spec: ImagePolicySpec{Repositories: []Repository{{Name: "icr.io/*", Profile: "signed"}}}},
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "b"}, Spec: ImagePolicySpec{Repositories: []Repository{{Name: "icr.io/*", Profile: "unsigned"}}}},
+			}}
+			match := apl.MatchImagePolicy("icr.io/app:v1", Workload{})
+			Expect(match.Profile).To(Equal("signed"))
+			Expect(match.Conflicts).To(Equal([]string{`ns/b repository "icr.io/*"`}))
+		})
+
+		It("should use the profile when the repository policy is empty", func() {
+			Expect(profile.Overlay(Policy{})).To(Equal(profile))
+		})
+
+		It("should replace the parts of the profile that the repository sets", func() {
+			overlaid := profile.Overlay(Policy{MutateImage: FalsePointer, Simple: Simple{Requirements: []SimpleRequirement{{Type: "reject"}}}})
+			Expect(overlaid.Trust).To(Equal(profile.Trust))
+			Expect(overlaid.Simple.Requirements).To(Equal([]SimpleRequirement{{Type: "reject"}}))
+			Expect(overlaid.MutateImage).To(Equal(FalsePointer))
+			Expect(profile.Simple.Requirements[0].Type).To(Equal("signedBy"))
+		})
+	})
 })
@@ -0,0 +1,29 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"time"
+)
+
+// Applies reports whether the verification can be reused at the given time for the policy resource at its current
+// generation. A verification that was made against another generation of the policy, even an earlier one with the
+// same content, never applies, nor does one without an expiry.
+func (v ImageVerification) Applies(policy string, generation int64, now time.Time) bool {
+	if v.Spec.ExpiresAt.IsZero() || !now.Before(v.Spec.ExpiresAt.Time) {
+		return false
+	}
+	return v.Spec.Policy == policy && v.Spec.PolicyGeneration == generation
+}
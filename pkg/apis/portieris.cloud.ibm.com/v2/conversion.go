@@ -0,0 +1,454 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// V1SpecAnnotation holds the v1 spec of a policy read as v2, when the v2 spec does not convert back to the same v1 spec
+	V1SpecAnnotation = "portieris.cloud.ibm.com/v1-spec"
+	// V2SpecAnnotation holds the v2 spec of a policy stored as v1, when the v1 spec does not convert back to the same v2 spec
+	V2SpecAnnotation = "portieris.cloud.ibm.com/v2-spec"
+)
+
+// Convert converts an ImagePolicy or ClusterImagePolicy, encoded as JSON, to desiredAPIVersion
+func Convert(object []byte, desiredAPIVersion string) ([]byte, error) {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(object, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return object, nil
+	}
+	fromV1 := typeMeta.APIVersion == policyv1.SchemeGroupVersion.String() && desiredAPIVersion == SchemeGroupVersion.String()
+	toV1 := typeMeta.APIVersion == SchemeGroupVersion.String() && desiredAPIVersion == policyv1.SchemeGroupVersion.String()
+	var out interface{}
+	var err error
+	switch {
+	case fromV1 && typeMeta.Kind == "ImagePolicy":
+		in := &policyv1.ImagePolicy{}
+		if err = json.Unmarshal(object, in); err == nil {
+			out, err = ImagePolicyFromV1(in)
+		}
+	case fromV1 && typeMeta.Kind == "ClusterImagePolicy":
+		in := &policyv1.ClusterImagePolicy{}
+		if err = json.Unmarshal(object, in); err == nil {
+			out, err = ClusterImagePolicyFromV1(in)
+		}
+	case toV1 && typeMeta.Kind == "ImagePolicy":
+		in := &ImagePolicy{}
+		if err = json.Unmarshal(object, in); err == nil {
+			out, err = ImagePolicyToV1(in)
+		}
+	case toV1 && typeMeta.Kind == "ClusterImagePolicy":
+		in := &ClusterImagePolicy{}
+		if err = json.Unmarshal(object, in); err == nil {
+			out, err = ClusterImagePolicyToV1(in)
+		}
+	default:
+		return nil, fmt.Errorf("can not convert %s %s to %s", typeMeta.Kind, typeMeta.APIVersion, desiredAPIVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// ImagePolicyFromV1 converts a v1 ImagePolicy to v2
+func ImagePolicyFromV1(in *policyv1.ImagePolicy) (*ImagePolicy, error) {
+	out := &ImagePolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "ImagePolicy"},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Status:     statusFromV1(in.Status),
+	}
+	out.Spec = convertFromV1(&out.ObjectMeta, in.Spec)
+	return out, nil
+}
+
+// ImagePolicyToV1 converts a v2 ImagePolicy to v1
+func ImagePolicyToV1(in *ImagePolicy) (*policyv1.ImagePolicy, error) {
+	out := &policyv1.ImagePolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: policyv1.SchemeGroupVersion.String(), Kind: "ImagePolicy"},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Status:     statusToV1(in.Status),
+	}
+	var err error
+	out.Spec, err = convertToV1(&out.ObjectMeta, in.Spec)
+	return out, err
+}
+
+// ClusterImagePolicyFromV1 converts a v1 ClusterImagePolicy to v2
+func ClusterImagePolicyFromV1(in *policyv1.ClusterImagePolicy) (*ClusterImagePolicy, error) {
+	out := &ClusterImagePolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "ClusterImagePolicy"},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Status:     statusFromV1(in.Status),
+	}
+	out.Spec = convertFromV1(&out.ObjectMeta, in.Spec)
+	return out, nil
+}
+
+// ClusterImagePolicyToV1 converts a v2 ClusterImagePolicy to v1
+func ClusterImagePolicyToV1(in *ClusterImagePolicy) (*policyv1.ClusterImagePolicy, error) {
+	out := &policyv1.ClusterImagePolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: policyv1.SchemeGroupVersion.String(), Kind: "ClusterImagePolicy"},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Status:     statusToV1(in.Status),
+	}
+	var err error
+	out.Spec, err = convertToV1(&out.ObjectMeta, in.Spec)
+	return out, err
+}
+
+// convertFromV1 converts the spec, restoring the v2 spec recorded when the object was stored if it
+// still matches, and records the v1 spec when the result does not convert back to it
+func convertFromV1(meta *metav1.ObjectMeta, in policyv1.ImagePolicySpec) ImagePolicySpec {
+	out := specFromV1(in)
+	delete(meta.Annotations, V1SpecAnnotation)
+	if stored, ok := meta.Annotations[V2SpecAnnotation]; ok {
+		delete(meta.Annotations, V2SpecAnnotation)
+		var spec ImagePolicySpec
+		if err := json.Unmarshal([]byte(stored), &spec); err == nil {
+			if back, err := specToV1(spec); err == nil && equality.Semantic.DeepEqual(back, in) {
+				out = spec
+			}
+		}
+	}
+	if back, err := specToV1(out); err != nil || !equality.Semantic.DeepEqual(back, in) {
+		annotate(meta, V1SpecAnnotation, in)
+	}
+	tidyAnnotations(meta)
+	return out
+}
+
+// convertToV1 converts the spec, restoring the v1 spec recorded when the object was read if it
+// still matches, and records the v2 spec when the result does not convert back to it
+func convertToV1(meta *metav1.ObjectMeta, in ImagePolicySpec) (policyv1.ImagePolicySpec, error) {
+	out, err := specToV1(in)
+	if err != nil {
+		return out, err
+	}
+	delete(meta.Annotations, V2SpecAnnotation)
+	if stored, ok := meta.Annotations[V1SpecAnnotation]; ok {
+		delete(meta.Annotations, V1SpecAnnotation)
+		var spec policyv1.ImagePolicySpec
+		if err := json.Unmarshal([]byte(stored), &spec); err == nil && equality.Semantic.DeepEqual(specFromV1(spec), in) {
+			out = spec
+		}
+	}
+	if !equality.Semantic.DeepEqual(specFromV1(out), in) {
+		annotate(meta, V2SpecAnnotation, in)
+	}
+	tidyAnnotations(meta)
+	return out, nil
+}
+
+func annotate(meta *metav1.ObjectMeta, key string, spec interface{}) {
+	value, _ := json.Marshal(spec)
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[key] = string(value)
+}
+
+func tidyAnnotations(meta *metav1.ObjectMeta) {
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+}
+
+func statusFromV1(in policyv1.ImagePolicyStatus) ImagePolicyStatus {
+	in = *in.DeepCopy()
+	return ImagePolicyStatus{ObservedGeneration: in.ObservedGeneration, Conditions: in.Conditions}
+}
+
+func statusToV1(in ImagePolicyStatus) policyv1.ImagePolicyStatus {
+	in = *in.DeepCopy()
+	return policyv1.ImagePolicyStatus{ObservedGeneration: in.ObservedGeneration, Conditions: in.Conditions}
+}
+
+func specFromV1(in policyv1.ImagePolicySpec) ImagePolicySpec {
+	in = *in.DeepCopy()
+	out := ImagePolicySpec{}
+	if in.Repositories != nil {
+		out.Repositories = make([]Repository, len(in.Repositories))
+	}
+	for i, repository := range in.Repositories {
+		out.Repositories[i] = repositoryFromV1(repository)
+	}
+	if in.Rego != nil {
+		out.Rego = &Rego{Module: in.Rego.Module, Query: in.Rego.Query}
+		if in.Rego.ConfigMap != nil {
+			configMap := ConfigMapKeyReference(*in.Rego.ConfigMap)
+			out.Rego.ConfigMap = &configMap
+		}
+	}
+	return out
+}
+
+func specToV1(in ImagePolicySpec) (policyv1.ImagePolicySpec, error) {
+	in = *in.DeepCopy()
+	out := policyv1.ImagePolicySpec{}
+	if in.Repositories != nil {
+		out.Repositories = make([]policyv1.Repository, len(in.Repositories))
+	}
+	for i, repository := range in.Repositories {
+		var err error
+		out.Repositories[i], err = repositoryToV1(repository)
+		if err != nil {
+			return out, fmt.Errorf("repository %d: %v", i, err)
+		}
+	}
+	if in.Rego != nil {
+		out.Rego = &policyv1.Rego{Module: in.Rego.Module, Query: in.Rego.Query}
+		if in.Rego.ConfigMap != nil {
+			configMap := policyv1.ConfigMapKeyReference(*in.Rego.ConfigMap)
+			out.Rego.ConfigMap = &configMap
+		}
+	}
+	return out, nil
+}
+
+// denyPolicy is the v1 policy of a repository with the Deny enforcement mode
+func denyPolicy() policyv1.Policy {
+	return policyv1.Policy{Simple: policyv1.Simple{Requirements: []policyv1.SimpleRequirement{{Type: "reject"}}}}
+}
+
+func repositoryFromV1(in policyv1.Repository) Repository {
+	out := Repository{
+		Name:                in.Name,
+		NameType:            in.NameType,
+		Excludes:            in.Excludes,
+		Selector:            in.Selector,
+		ServiceAccountNames: in.ServiceAccountNames,
+		Priority:            in.Priority,
+		Profile:             in.Profile,
+		Enforcement:         EnforcementVerify,
+		MutateImage:         in.Policy.MutateImage,
+	}
+	policy := in.Policy
+	policy.MutateImage = nil
+	switch {
+	case in.Profile == "" && equality.Semantic.DeepEqual(policy, policyv1.Policy{}):
+		out.Enforcement = EnforcementAllow
+	case in.Profile == "" && equality.Semantic.DeepEqual(policy, denyPolicy()):
+		out.Enforcement = EnforcementDeny
+	default:
+		out.Verifiers = verifiersFromV1(policy)
+	}
+	return out
+}
+
+func verifiersFromV1(policy policyv1.Policy) []Verifier {
+	var verifiers []Verifier
+	if policy.Trust.Enabled != nil && *policy.Trust.Enabled {
+		trust := &TrustVerifier{Server: policy.Trust.TrustServer}
+		for _, signer := range policy.Trust.SignerSecrets {
+			trust.Signers = append(trust.Signers, SecretReference{Name: signer.Name, Namespace: signer.Namespace})
+		}
+		verifiers = append(verifiers, Verifier{Type: VerifierTrust, Trust: trust})
+	}
+	if len(policy.Simple.Requirements) > 0 {
+		simple := &SimpleVerifier{MultiArch: MultiArch(policy.Simple.MultiArch)}
+		for _, requirement := range policy.Simple.Requirements {
+			out := SimpleRequirement{Type: requirement.Type}
+			if requirement.KeySecret != "" || requirement.KeySecretNamespace != "" {
+				out.Key = &SecretReference{Name: requirement.KeySecret, Namespace: requirement.KeySecretNamespace}
+			}
+			if requirement.SignedIdentity != (policyv1.IdentityRequirement{}) {
+				identity := IdentityRequirement(requirement.SignedIdentity)
+				out.SignedIdentity = &identity
+			}
+			simple.Requirements = append(simple.Requirements, out)
+		}
+		if policy.Simple.StoreURL != "" || policy.Simple.StoreSecret != "" || policy.Simple.StoreSecretNamespace != "" {
+			simple.Store = &SignatureStore{URL: policy.Simple.StoreURL}
+			if policy.Simple.StoreSecret != "" || policy.Simple.StoreSecretNamespace != "" {
+				simple.Store.Secret = &SecretReference{Name: policy.Simple.StoreSecret, Namespace: policy.Simple.StoreSecretNamespace}
+			}
+		}
+		verifiers = append(verifiers, Verifier{Type: VerifierSimple, Simple: simple})
+	}
+	if policy.Vulnerability.ICCRVA.Enabled != nil && *policy.Vulnerability.ICCRVA.Enabled {
+		vulnerability := &VulnerabilityVerifier{Provider: VulnerabilityProviderICCRVA, Account: policy.Vulnerability.ICCRVA.Account}
+		verifiers = append(verifiers, Verifier{Type: VerifierVulnerability, Vulnerability: vulnerability})
+	}
+	if !equality.Semantic.DeepEqual(policy.Tags, policyv1.Tags{}) {
+		tags := TagsVerifier(policy.Tags)
+		verifiers = append(verifiers, Verifier{Type: VerifierTags, Tags: &tags})
+	}
+	if !equality.Semantic.DeepEqual(policy.Config, policyv1.Config{}) {
+		config := ConfigVerifier(policy.Config)
+		verifiers = append(verifiers, Verifier{Type: VerifierConfig, Config: &config})
+	}
+	if !equality.Semantic.DeepEqual(policy.BaseImage, policyv1.BaseImage{}) {
+		baseImage := BaseImageVerifier(policy.BaseImage)
+		verifiers = append(verifiers, Verifier{Type: VerifierBaseImage, BaseImage: &baseImage})
+	}
+	if !equality.Semantic.DeepEqual(policy.Platforms, policyv1.Platforms{}) {
+		platforms := PlatformsVerifier(policy.Platforms)
+		verifiers = append(verifiers, Verifier{Type: VerifierPlatforms, Platforms: &platforms})
+	}
+	if len(policy.Conditions) > 0 {
+		conditions := &ConditionsVerifier{}
+		for _, condition := range policy.Conditions {
+			conditions.Rules = append(conditions.Rules, Condition(condition))
+		}
+		verifiers = append(verifiers, Verifier{Type: VerifierConditions, Conditions: conditions})
+	}
+	return verifiers
+}
+
+func repositoryToV1(in Repository) (policyv1.Repository, error) {
+	out := policyv1.Repository{
+		Name:                in.Name,
+		NameType:            in.NameType,
+		Excludes:            in.Excludes,
+		Selector:            in.Selector,
+		ServiceAccountNames: in.ServiceAccountNames,
+		Priority:            in.Priority,
+		Profile:             in.Profile,
+	}
+	if in.Enforcement != EnforcementVerify && in.Enforcement != "" && (len(in.Verifiers) > 0 || in.Profile != "") {
+		return out, fmt.Errorf("verifiers and profile can only be used when enforcement is %s", EnforcementVerify)
+	}
+	switch in.Enforcement {
+	case EnforcementAllow:
+	case EnforcementDeny:
+		out.Policy = denyPolicy()
+	case EnforcementVerify, "":
+		policy, err := verifiersToV1(in.Verifiers)
+		if err != nil {
+			return out, err
+		}
+		out.Policy = policy
+	default:
+		return out, fmt.Errorf("enforcement %q is not known", in.Enforcement)
+	}
+	out.Policy.MutateImage = in.MutateImage
+	return out, nil
+}
+
+func verifiersToV1(verifiers []Verifier) (policyv1.Policy, error) {
+	policy := policyv1.Policy{}
+	seen := map[VerifierType]bool{}
+	for _, verifier := range verifiers {
+		if seen[verifier.Type] {
+			return policy, fmt.Errorf("verifier type %q is repeated", verifier.Type)
+		}
+		seen[verifier.Type] = true
+		if member := verifier.otherMember(); member != "" {
+			return policy, fmt.Errorf("verifier type %q can not set the %s member", verifier.Type, member)
+		}
+		switch verifier.Type {
+		case VerifierTrust:
+			trust := TrustVerifier{}
+			if verifier.Trust != nil {
+				trust = *verifier.Trust
+			}
+			enabled := true
+			policy.Trust = policyv1.Trust{Enabled: &enabled, TrustServer: trust.Server}
+			for _, signer := range trust.Signers {
+				policy.Trust.SignerSecrets = append(policy.Trust.SignerSecrets, policyv1.TrustSigner{Name: signer.Name, Namespace: signer.Namespace})
+			}
+		case VerifierSimple:
+			simple := SimpleVerifier{}
+			if verifier.Simple != nil {
+				simple = *verifier.Simple
+			}
+			policy.Simple = policyv1.Simple{MultiArch: policyv1.MultiArch(simple.MultiArch)}
+			for _, requirement := range simple.Requirements {
+				out := policyv1.SimpleRequirement{Type: requirement.Type}
+				if requirement.Key != nil {
+					out.KeySecret = requirement.Key.Name
+					out.KeySecretNamespace = requirement.Key.Namespace
+				}
+				if requirement.SignedIdentity != nil {
+					out.SignedIdentity = policyv1.IdentityRequirement(*requirement.SignedIdentity)
+				}
+				policy.Simple.Requirements = append(policy.Simple.Requirements, out)
+			}
+			if simple.Store != nil {
+				policy.Simple.StoreURL = simple.Store.URL
+				if simple.Store.Secret != nil {
+					policy.Simple.StoreSecret = simple.Store.Secret.Name
+					policy.Simple.StoreSecretNamespace = simple.Store.Secret.Namespace
+				}
+			}
+		case VerifierVulnerability:
+			vulnerability := VulnerabilityVerifier{Provider: VulnerabilityProviderICCRVA}
+			if verifier.Vulnerability != nil {
+				vulnerability = *verifier.Vulnerability
+			}
+			if vulnerability.Provider != VulnerabilityProviderICCRVA {
+				return policy, fmt.Errorf("vulnerability provider %q is not known", vulnerability.Provider)
+			}
+			enabled := true
+			policy.Vulnerability.ICCRVA = policyv1.ICCRVA{Enabled: &enabled, Account: vulnerability.Account}
+		case VerifierTags:
+			if verifier.Tags != nil {
+				policy.Tags = policyv1.Tags(*verifier.Tags)
+			}
+		case VerifierConfig:
+			if verifier.Config != nil {
+				policy.Config = policyv1.Config(*verifier.Config)
+			}
+		case VerifierBaseImage:
+			if verifier.BaseImage != nil {
+				policy.BaseImage = policyv1.BaseImage(*verifier.BaseImage)
+			}
+		case VerifierPlatforms:
+			if verifier.Platforms != nil {
+				policy.Platforms = policyv1.Platforms(*verifier.Platforms)
+			}
+		case VerifierConditions:
+			if verifier.Conditions != nil {
+				for _, condition := range verifier.Conditions.Rules {
+					policy.Conditions = append(policy.Conditions, policyv1.Condition(condition))
+				}
+			}
+		default:
+			return policy, fmt.Errorf("verifier type %q is not known", verifier.Type)
+		}
+	}
+	return policy, nil
+}
+
+// otherMember returns the type of a member that is set but not named by the verifier type
+func (v Verifier) otherMember() VerifierType {
+	members := map[VerifierType]bool{
+		VerifierTrust:         v.Trust != nil,
+		VerifierSimple:        v.Simple != nil,
+		VerifierVulnerability: v.Vulnerability != nil,
+		VerifierTags:          v.Tags != nil,
+		VerifierConfig:        v.Config != nil,
+		VerifierBaseImage:     v.BaseImage != nil,
+		VerifierPlatforms:     v.Platforms != nil,
+		VerifierConditions:    v.Conditions != nil,
+	}
+	for _, member := range []VerifierType{VerifierTrust, VerifierSimple, VerifierVulnerability, VerifierTags, VerifierConfig, VerifierBaseImage, VerifierPlatforms, VerifierConditions} {
+		if members[member] && member != v.Type {
+			return member
+		}
+	}
+	return ""
+}
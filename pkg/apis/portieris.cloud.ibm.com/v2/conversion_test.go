@@ -0,0 +1,315 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"encoding/json"
+	"testing"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func v1Policy(repositories ...policyv1.Repository) *policyv1.ImagePolicy {
+	return &policyv1.ImagePolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "portieris.cloud.ibm.com/v1", Kind: "ImagePolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default", Labels: map[string]string{"team": "a"}},
+		Spec:       policyv1.ImagePolicySpec{Repositories: repositories},
+	}
+}
+
+func v2Policy(repositories ...Repository) *ImagePolicy {
+	return &ImagePolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "portieris.cloud.ibm.com/v2", Kind: "ImagePolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default", Labels: map[string]string{"team": "a"}},
+		Spec:       ImagePolicySpec{Repositories: repositories},
+	}
+}
+
+func TestConversion(t *testing.T) {
+	maxLayers := int32(10)
+	maxSize := resource.MustParse("500Mi")
+	tests := []struct {
+		name string
+		v1   policyv1.Repository
+		v2   Repository
+	}{
+		{
+			name: "empty policy allows",
+			v1:   policyv1.Repository{Name: "icr.io/*"},
+			v2:   Repository{Name: "icr.io/*", Enforcement: EnforcementAllow},
+		},
+		{
+			name: "reject requirement denies",
+			v1: policyv1.Repository{Name: "docker.io/*", Policy: policyv1.Policy{
+				Simple:      policyv1.Simple{Requirements: []policyv1.SimpleRequirement{{Type: "reject"}}},
+				MutateImage: policyv1.FalsePointer,
+			}},
+			v2: Repository{Name: "docker.io/*", Enforcement: EnforcementDeny, MutateImage: policyv1.FalsePointer},
+		},
+		{
+			name: "profile with no policy verifies",
+			v1:   policyv1.Repository{Name: "icr.io/*", Profile: "signed"},
+			v2:   Repository{Name: "icr.io/*", Profile: "signed", Enforcement: EnforcementVerify},
+		},
+		{
+			name: "matching fields are kept",
+			v1: policyv1.Repository{
+				Name:                "icr.io/team/**",
+				NameType:            policyv1.NameTypePath,
+				Excludes:            []string{"icr.io/team/test/**"},
+				Selector:            &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}},
+				ServiceAccountNames: []string{"deployer"},
+				Priority:            5,
+			},
+			v2: Repository{
+				Name:                "icr.io/team/**",
+				NameType:            policyv1.NameTypePath,
+				Excludes:            []string{"icr.io/team/test/**"},
+				Selector:            &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}},
+				ServiceAccountNames: []string{"deployer"},
+				Priority:            5,
+				Enforcement:         EnforcementAllow,
+			},
+		},
+		{
+			name: "every verifier",
+			v1: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{
+				Trust: policyv1.Trust{
+					Enabled:       policyv1.TruePointer,
+					TrustServer:   "https://notary.icr.io",
+					SignerSecrets: []policyv1.TrustSigner{{Name: "alice"}, {Name: "bob", Namespace: "keys"}},
+				},
+				Simple: policyv1.Simple{
+					Requirements: []policyv1.SimpleRequirement{{
+						Type:               "signedBy",
+						KeySecret:          "key",
+						KeySecretNamespace: "keys",
+						SignedIdentity:     policyv1.IdentityRequirement{Type: "matchRepository"},
+					}},
+					StoreURL:             "https://store.example.com",
+					StoreSecret:          "store",
+					StoreSecretNamespace: "keys",
+					MultiArch:            policyv1.MultiArch{Verify: policyv1.MultiArchVerifyAll},
+				},
+				Vulnerability: policyv1.Vulnerability{ICCRVA: policyv1.ICCRVA{Enabled: policyv1.TruePointer, Account: "123"}},
+				Tags:          policyv1.Tags{RequireDigest: policyv1.TruePointer, Deny: []string{"latest"}},
+				Config:        policyv1.Config{MaxLayers: &maxLayers, MaxSize: &maxSize},
+				BaseImage:     policyv1.BaseImage{Allowed: []string{"icr.io/base/*"}},
+				Platforms:     policyv1.Platforms{Required: []string{"linux/s390x"}},
+				Conditions:    []policyv1.Condition{{Expression: "image.tag != ''", Message: "tag required"}},
+			}},
+			v2: Repository{Name: "icr.io/*", Enforcement: EnforcementVerify, Verifiers: []Verifier{
+				{Type: VerifierTrust, Trust: &TrustVerifier{
+					Server:  "https://notary.icr.io",
+					Signers: []SecretReference{{Name: "alice"}, {Name: "bob", Namespace: "keys"}},
+				}},
+				{Type: VerifierSimple, Simple: &SimpleVerifier{
+					Requirements: []SimpleRequirement{{
+						Type:           "signedBy",
+						Key:            &SecretReference{Name: "key", Namespace: "keys"},
+						SignedIdentity: &IdentityRequirement{Type: "matchRepository"},
+					}},
+					Store:     &SignatureStore{URL: "https://store.example.com", Secret: &SecretReference{Name: "store", Namespace: "keys"}},
+					MultiArch: MultiArch{Verify: policyv1.MultiArchVerifyAll},
+				}},
+				{Type: VerifierVulnerability, Vulnerability: &VulnerabilityVerifier{Provider: VulnerabilityProviderICCRVA, Account: "123"}},
+				{Type: VerifierTags, Tags: &TagsVerifier{RequireDigest: policyv1.TruePointer, Deny: []string{"latest"}}},
+				{Type: VerifierConfig, Config: &ConfigVerifier{MaxLayers: &maxLayers, MaxSize: &maxSize}},
+				{Type: VerifierBaseImage, BaseImage: &BaseImageVerifier{Allowed: []string{"icr.io/base/*"}}},
+				{Type: VerifierPlatforms, Platforms: &PlatformsVerifier{Required: []string{"linux/s390x"}}},
+				{Type: VerifierConditions, Conditions: &ConditionsVerifier{Rules: []Condition{{Expression: "image.tag != ''", Message: "tag required"}}}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in1 := v1Policy(tt.v1)
+			out2, err := ImagePolicyFromV1(in1)
+			require.NoError(t, err)
+			assert.Equal(t, v2Policy(tt.v2), out2)
+
+			in2 := v2Policy(tt.v2)
+			out1, err := ImagePolicyToV1(in2)
+			require.NoError(t, err)
+			assert.Equal(t, in1, out1)
+		})
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	t.Run("v1 that v2 can not represent", func(t *testing.T) {
+		tests := []policyv1.Repository{
+			{Name: "icr.io/*", Policy: policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.FalsePointer, TrustServer: "https://notary.icr.io"}}},
+			{Name: "icr.io/*", Profile: "signed", Policy: policyv1.Policy{Vulnerability: policyv1.Vulnerability{ICCRVA: policyv1.ICCRVA{Enabled: policyv1.FalsePointer}}}},
+			{Name: "icr.io/*", Policy: policyv1.Policy{Simple: policyv1.Simple{StoreURL: "https://store.example.com"}}},
+		}
+		for _, repository := range tests {
+			in := v1Policy(repository)
+			out2, err := ImagePolicyFromV1(in)
+			require.NoError(t, err)
+			assert.Contains(t, out2.Annotations, V1SpecAnnotation)
+
+			out1, err := ImagePolicyToV1(out2)
+			require.NoError(t, err)
+			assert.Equal(t, in, out1)
+		}
+	})
+
+	t.Run("v2 that v1 can not represent", func(t *testing.T) {
+		tests := []Repository{
+			{Name: "icr.io/*"},
+			{Name: "icr.io/*", Enforcement: EnforcementVerify},
+			{Name: "icr.io/*", Enforcement: EnforcementVerify, Verifiers: []Verifier{{Type: VerifierTags, Tags: &TagsVerifier{}}}},
+			{Name: "icr.io/*", Enforcement: EnforcementVerify, Verifiers: []Verifier{
+				{Type: VerifierPlatforms, Platforms: &PlatformsVerifier{Required: []string{"linux/amd64"}}},
+				{Type: VerifierTrust},
+			}},
+		}
+		for _, repository := range tests {
+			in := v2Policy(repository)
+			out1, err := ImagePolicyToV1(in)
+			require.NoError(t, err)
+			assert.Contains(t, out1.Annotations, V2SpecAnnotation)
+
+			out2, err := ImagePolicyFromV1(out1)
+			require.NoError(t, err)
+			assert.Equal(t, in, out2)
+		}
+	})
+
+	t.Run("a changed v2 spec replaces the stored v1 spec", func(t *testing.T) {
+		in := v1Policy(policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.FalsePointer}}})
+		out2, err := ImagePolicyFromV1(in)
+		require.NoError(t, err)
+		out2.Spec.Repositories[0].Enforcement = EnforcementDeny
+
+		out1, err := ImagePolicyToV1(out2)
+		require.NoError(t, err)
+		assert.Nil(t, out1.Annotations)
+		assert.Equal(t, denyPolicy(), out1.Spec.Repositories[0].Policy)
+	})
+
+	t.Run("a changed v1 spec replaces the stored v2 spec", func(t *testing.T) {
+		in := v2Policy(Repository{Name: "icr.io/*", Enforcement: EnforcementVerify})
+		out1, err := ImagePolicyToV1(in)
+		require.NoError(t, err)
+		out1.Spec.Repositories[0].Name = "de.icr.io/*"
+
+		out2, err := ImagePolicyFromV1(out1)
+		require.NoError(t, err)
+		assert.Nil(t, out2.Annotations)
+		assert.Equal(t, Repository{Name: "de.icr.io/*", Enforcement: EnforcementAllow}, out2.Spec.Repositories[0])
+	})
+
+	t.Run("cluster policy keeps its status", func(t *testing.T) {
+		in := &policyv1.ClusterImagePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: policyv1.ImagePolicySpec{
+				Repositories: []policyv1.Repository{{Name: "*"}},
+				Rego:         &policyv1.Rego{ConfigMap: &policyv1.ConfigMapKeyReference{Name: "rego", Namespace: "portieris", Key: "policy.rego"}},
+			},
+			Status: policyv1.ImagePolicyStatus{ObservedGeneration: 2, Conditions: []metav1.Condition{{Type: policyv1.ConditionValid, Status: metav1.ConditionTrue}}},
+		}
+		out2, err := ClusterImagePolicyFromV1(in)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), out2.Status.ObservedGeneration)
+		assert.Equal(t, "rego", out2.Spec.Rego.ConfigMap.Name)
+
+		out1, err := ClusterImagePolicyToV1(out2)
+		require.NoError(t, err)
+		in.TypeMeta = metav1.TypeMeta{APIVersion: "portieris.cloud.ibm.com/v1", Kind: "ClusterImagePolicy"}
+		assert.Equal(t, in, out1)
+	})
+}
+
+func TestConversionErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository Repository
+		err        string
+	}{
+		{
+			name:       "unknown enforcement",
+			repository: Repository{Name: "*", Enforcement: "Audit"},
+			err:        `repository 0: enforcement "Audit" is not known`,
+		},
+		{
+			name:       "unknown verifier",
+			repository: Repository{Name: "*", Verifiers: []Verifier{{Type: "Cosign"}}},
+			err:        `repository 0: verifier type "Cosign" is not known`,
+		},
+		{
+			name:       "repeated verifier",
+			repository: Repository{Name: "*", Verifiers: []Verifier{{Type: VerifierTrust}, {Type: VerifierTrust}}},
+			err:        `repository 0: verifier type "Trust" is repeated`,
+		},
+		{
+			name:       "verifiers with allow",
+			repository: Repository{Name: "*", Enforcement: EnforcementAllow, Verifiers: []Verifier{{Type: VerifierTrust}}},
+			err:        "repository 0: verifiers and profile can only be used when enforcement is Verify",
+		},
+		{
+			name:       "profile with deny",
+			repository: Repository{Name: "*", Enforcement: EnforcementDeny, Profile: "signed"},
+			err:        "repository 0: verifiers and profile can only be used when enforcement is Verify",
+		},
+		{
+			name:       "member of another type",
+			repository: Repository{Name: "*", Verifiers: []Verifier{{Type: VerifierTrust, Tags: &TagsVerifier{Pattern: "v.*"}}}},
+			err:        `repository 0: verifier type "Trust" can not set the Tags member`,
+		},
+		{
+			name:       "unknown vulnerability provider",
+			repository: Repository{Name: "*", Verifiers: []Verifier{{Type: VerifierVulnerability, Vulnerability: &VulnerabilityVerifier{Provider: "other"}}}},
+			err:        `repository 0: vulnerability provider "other" is not known`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImagePolicyToV1(v2Policy(tt.repository))
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	v1JSON := `{"apiVersion":"portieris.cloud.ibm.com/v1","kind":"ImagePolicy","metadata":{"name":"policy","namespace":"default"},` +
+		`"spec":{"repositories":[{"name":"icr.io/*","policy":{"trust":{"enabled":true}}}]}}`
+
+	out, err := Convert([]byte(v1JSON), "portieris.cloud.ibm.com/v2")
+	require.NoError(t, err)
+	policy := &ImagePolicy{}
+	require.NoError(t, json.Unmarshal(out, policy))
+	assert.Equal(t, "portieris.cloud.ibm.com/v2", policy.APIVersion)
+	assert.Equal(t, []Verifier{{Type: VerifierTrust, Trust: &TrustVerifier{}}}, policy.Spec.Repositories[0].Verifiers)
+
+	back, err := Convert(out, "portieris.cloud.ibm.com/v1")
+	require.NoError(t, err)
+	expected, actual := &policyv1.ImagePolicy{}, &policyv1.ImagePolicy{}
+	require.NoError(t, json.Unmarshal([]byte(v1JSON), expected))
+	require.NoError(t, json.Unmarshal(back, actual))
+	assert.Equal(t, expected, actual)
+
+	same, err := Convert([]byte(v1JSON), "portieris.cloud.ibm.com/v1")
+	require.NoError(t, err)
+	assert.Equal(t, v1JSON, string(same))
+
+	_, err = Convert([]byte(`{"apiVersion":"portieris.cloud.ibm.com/v1","kind":"ImagePolicyProfile"}`), "portieris.cloud.ibm.com/v2")
+	assert.EqualError(t, err, "can not convert ImagePolicyProfile portieris.cloud.ibm.com/v1 to portieris.cloud.ibm.com/v2")
+}
@@ -0,0 +1,286 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImagePolicy is a specification for a ImagePolicy resource
+type ImagePolicy struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImagePolicySpec   `json:"spec"`
+	Status ImagePolicyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImagePolicyList is a list of ImagePolicy resources
+type ImagePolicyList struct {
+	metav1.TypeMeta
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ImagePolicy `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterImagePolicy is a specification for a ClusterImagePolicy resource
+type ClusterImagePolicy struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImagePolicySpec   `json:"spec"`
+	Status ImagePolicyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterImagePolicyList is a list of ClusterImagePolicy resources
+type ClusterImagePolicyList struct {
+	metav1.TypeMeta
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterImagePolicy `json:"items"`
+}
+
+// ImagePolicyStatus is the status for a ImagePolicy or ClusterImagePolicy resource
+type ImagePolicyStatus struct {
+	// ObservedGeneration is the generation of the spec that the conditions describe
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions report whether the policy is Valid, SecretsResolved and KeysParsed
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ImagePolicySpec is the spec for a ImagePolicy or ClusterImagePolicy resource
+type ImagePolicySpec struct {
+	Repositories []Repository `json:"repositories"`
+	// Rego is only used in a ClusterImagePolicy, it decides whether images selected by its repositories are allowed
+	Rego *Rego `json:"rego,omitempty"`
+}
+
+// Rego is a Rego module that is evaluated once the verifiers of the selected repository allow the image
+type Rego struct {
+	// Module is the source of an inline Rego module
+	Module string `json:"module,omitempty"`
+	// ConfigMap reads the Rego module from a ConfigMap, instead of Module
+	ConfigMap *ConfigMapKeyReference `json:"configMap,omitempty"`
+	// Query produces the decision, an object with an allow boolean and messages, the default is data.portieris.decision
+	Query string `json:"query,omitempty"`
+}
+
+// ConfigMapKeyReference selects a key of a ConfigMap
+type ConfigMapKeyReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+}
+
+// SecretReference names a secret
+type SecretReference struct {
+	Name string `json:"name"`
+	// Namespace of the secret, the default is the namespace of the workload
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// EnforcementMode is how images selected by a repository are admitted
+type EnforcementMode string
+
+const (
+	// EnforcementVerify allows images that satisfy every verifier, it is the default
+	EnforcementVerify EnforcementMode = "Verify"
+	// EnforcementAllow allows images without verifying them
+	EnforcementAllow EnforcementMode = "Allow"
+	// EnforcementDeny denies images
+	EnforcementDeny EnforcementMode = "Deny"
+)
+
+// Repository selects images and says how they are admitted
+type Repository struct {
+	// Name may contain a * to signify one or more characters
+	Name string `json:"name"`
+	// NameType selects how Name and Excludes are matched, one of wildcard, path or regexp, the default is wildcard
+	NameType string `json:"nameType,omitempty"`
+	// Excludes are patterns, of the same NameType as Name, for images that the repository does not apply to
+	Excludes []string `json:"excludes,omitempty"`
+	// Selector limits the repository to workloads whose pod template labels match
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// ServiceAccountNames limits the repository to workloads running as one of the named service accounts
+	ServiceAccountNames []string `json:"serviceAccountNames,omitempty"`
+	// Priority orders matching repositories ahead of name specificity, higher wins, the default is 0
+	Priority int32 `json:"priority,omitempty"`
+	// Profile names an ImagePolicyProfile whose policy is used, each verifier replaces the same part of the profile
+	Profile string `json:"profile,omitempty"`
+	// Enforcement is how selected images are admitted, the verifiers are only used when it is Verify
+	Enforcement EnforcementMode `json:"enforcement,omitempty"`
+	// Verifiers must all allow an image, each type can be listed once
+	Verifiers []Verifier `json:"verifiers,omitempty"`
+	// MutateImage replaces the image reference with the verified digest, the default is true
+	MutateImage *bool `json:"mutateImage,omitempty"`
+}
+
+// VerifierType selects the member of a Verifier that is set
+type VerifierType string
+
+const (
+	// VerifierTrust verifies Notary content trust signatures
+	VerifierTrust VerifierType = "Trust"
+	// VerifierSimple verifies simple signing signatures
+	VerifierSimple VerifierType = "Simple"
+	// VerifierVulnerability denies images with vulnerabilities
+	VerifierVulnerability VerifierType = "Vulnerability"
+	// VerifierTags constrains how images are referenced
+	VerifierTags VerifierType = "Tags"
+	// VerifierConfig constrains the image configuration and manifest
+	VerifierConfig VerifierType = "Config"
+	// VerifierBaseImage constrains the base image
+	VerifierBaseImage VerifierType = "BaseImage"
+	// VerifierPlatforms constrains the platforms that a multi-architecture image provides
+	VerifierPlatforms VerifierType = "Platforms"
+	// VerifierConditions evaluates CEL expressions
+	VerifierConditions VerifierType = "Conditions"
+)
+
+// Verifier is a discriminated union, Type names the one member that is set
+type Verifier struct {
+	Type          VerifierType           `json:"type"`
+	Trust         *TrustVerifier         `json:"trust,omitempty"`
+	Simple        *SimpleVerifier        `json:"simple,omitempty"`
+	Vulnerability *VulnerabilityVerifier `json:"vulnerability,omitempty"`
+	Tags          *TagsVerifier          `json:"tags,omitempty"`
+	Config        *ConfigVerifier        `json:"config,omitempty"`
+	BaseImage     *BaseImageVerifier     `json:"baseImage,omitempty"`
+	Platforms     *PlatformsVerifier     `json:"platforms,omitempty"`
+	Conditions    *ConditionsVerifier    `json:"conditions,omitempty"`
+}
+
+// TrustVerifier verifies Notary content trust signatures
+type TrustVerifier struct {
+	// Server is the URL of the Notary server, the default is derived from the registry
+	Server string `json:"server,omitempty"`
+	// Signers are secrets holding the name and publicKey of signers that must have signed the image
+	Signers []SecretReference `json:"signers,omitempty"`
+}
+
+// SimpleVerifier verifies simple signing signatures
+type SimpleVerifier struct {
+	Requirements []SimpleRequirement `json:"requirements"`
+	// Store is a signature store, the default is the registry
+	Store *SignatureStore `json:"store,omitempty"`
+	// MultiArch selects how an image index, or manifest list, is verified
+	MultiArch MultiArch `json:"multiArch,omitempty"`
+}
+
+// SignatureStore is a lookaside store of simple signing signatures
+type SignatureStore struct {
+	URL string `json:"url"`
+	// Secret holds the username and password for the store
+	Secret *SecretReference `json:"secret,omitempty"`
+}
+
+// MultiArch selects how the signatures of a multi-architecture image are verified
+type MultiArch struct {
+	// Verify is one of index, all or nodes, the default is index
+	Verify string `json:"verify,omitempty"`
+	// MutateToIndex mutates the image to the index digest once the platform manifests are verified
+	MutateToIndex *bool `json:"mutateToIndex,omitempty"`
+}
+
+// SimpleRequirement is one of signedBy, insecureAcceptAnything or reject
+type SimpleRequirement struct {
+	Type string `json:"type"`
+	// Key is a secret holding the public key, it is required by signedBy
+	Key *SecretReference `json:"key,omitempty"`
+	// SignedIdentity constrains the identity in the signature, the default is matchRepoDigestOrExact
+	SignedIdentity *IdentityRequirement `json:"signedIdentity,omitempty"`
+}
+
+// IdentityRequirement constrains the identity in a simple signing signature
+type IdentityRequirement struct {
+	Type             string `json:"type"`
+	DockerReference  string `json:"dockerReference,omitempty"`
+	DockerRepository string `json:"dockerRepository,omitempty"`
+	Prefix           string `json:"prefix,omitempty"`
+	SignedPrefix     string `json:"signedPrefix,omitempty"`
+}
+
+// VulnerabilityVerifier denies images with vulnerabilities reported by a scanner
+type VulnerabilityVerifier struct {
+	// Provider is the scanner, ICCRVA is IBM Cloud Container Registry Vulnerability Advisor
+	Provider string `json:"provider"`
+	// Account is the IBM Cloud account whose exemptions apply
+	Account string `json:"account,omitempty"`
+}
+
+// VulnerabilityProviderICCRVA is IBM Cloud Container Registry Vulnerability Advisor
+const VulnerabilityProviderICCRVA = "ICCRVA"
+
+// TagsVerifier constrains how images are referenced
+type TagsVerifier struct {
+	// RequireDigest denies images that are not referenced by digest
+	RequireDigest *bool `json:"requireDigest,omitempty"`
+	// Deny lists wildcard patterns of tags that can not be used, for example latest
+	Deny []string `json:"deny,omitempty"`
+	// Pattern is a regular expression that tags must match, for example a semantic version
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// ConfigVerifier constrains the image configuration and manifest retrieved from the registry
+type ConfigVerifier struct {
+	// MaxAge is the longest time since the image was created, for example 720h
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// MaxSize is the largest total compressed size of the image layers, for example 500Mi
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// MaxLayers is the largest number of layers in the image
+	MaxLayers *int32 `json:"maxLayers,omitempty"`
+	// DenyRootUser denies images that are configured to run as root, including when no user is set
+	DenyRootUser *bool `json:"denyRootUser,omitempty"`
+	// RequiredLabels are image configuration labels that must be present, with a value that matches the wildcard pattern
+	RequiredLabels map[string]string `json:"requiredLabels,omitempty"`
+	// RequiredAnnotations are manifest annotations that must be present, with a value that matches the wildcard pattern
+	RequiredAnnotations map[string]string `json:"requiredAnnotations,omitempty"`
+}
+
+// BaseImageVerifier constrains the base image recorded in the OCI image manifest annotations
+type BaseImageVerifier struct {
+	// Allowed lists wildcard patterns of approved base images, a pattern with a digest only matches that digest
+	Allowed []string `json:"allowed,omitempty"`
+	// VerifyLayers checks that the layers of the recorded base image are the first layers of the image
+	VerifyLayers *bool `json:"verifyLayers,omitempty"`
+}
+
+// PlatformsVerifier constrains the platforms that a multi-architecture image provides
+type PlatformsVerifier struct {
+	// Required lists os/architecture platforms, with an optional /variant, that the image must provide
+	Required []string `json:"required,omitempty"`
+}
+
+// ConditionsVerifier evaluates CEL expressions once the other verifiers have allowed the image
+type ConditionsVerifier struct {
+	Rules []Condition `json:"rules"`
+}
+
+// Condition is a CEL expression that must evaluate to true for the image to be allowed
+type Condition struct {
+	// Expression is a CEL expression, see POLICIES.md for the variables it can use
+	Expression string `json:"expression"`
+	// Message is the reason given when the expression is false, it defaults to the expression
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breakglass
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IBM/portieris/helpers/wildcard"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotation on a workload gives the reason for admitting it despite being denied by policy
+const Annotation = "portieris.cloud.ibm.com/break-glass"
+
+// Config selects the namespaces where the break-glass annotation can be used and the users and groups
+// that can use it, an empty Config allows nobody
+type Config struct {
+	// Namespaces are names, which may contain a * wildcard, of the namespaces where break-glass can be used
+	Namespaces []string
+	// Users are names, which may contain a * wildcard, of the users that can break glass
+	Users []string
+	// Groups are names, which may contain a * wildcard, of the groups whose members can break glass
+	Groups []string
+}
+
+// NewConfig creates a Config from comma separated lists, as given on the command line
+func NewConfig(namespaces, users, groups string) Config {
+	return Config{
+		Namespaces: splitList(namespaces),
+		Users:      splitList(users),
+		Groups:     splitList(groups),
+	}
+}
+
+func splitList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Allows reports whether the user can break glass in the namespace
+func (c Config) Allows(namespace string, user authenticationv1.UserInfo) bool {
+	if !matchAny(c.Namespaces, namespace) {
+		return false
+	}
+	if matchAny(c.Users, user.Username) {
+		return true
+	}
+	for _, group := range user.Groups {
+		if matchAny(c.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if wildcard.Compare(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditRecord describes a workload that was admitted by breaking glass
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	UID       string    `json:"uid"`
+	Operation string    `json:"operation"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	User      string    `json:"user"`
+	Groups    []string  `json:"groups,omitempty"`
+	Reason    string    `json:"reason"`
+	// Denials are the reasons the workload would otherwise have been denied
+	Denials []string `json:"denials"`
+}
+
+// NewAuditRecord records the admission request of the workload with the given metadata
+func NewAuditRecord(request *admissionv1.AdmissionRequest, object metav1.ObjectMeta, denials []string, now time.Time) AuditRecord {
+	name := object.Name
+	if name == "" {
+		name = request.Name
+	}
+	return AuditRecord{
+		Time:      now.UTC(),
+		UID:       string(request.UID),
+		Operation: string(request.Operation),
+		Kind:      request.Kind.Kind,
+		Namespace: request.Namespace,
+		Name:      name,
+		User:      request.UserInfo.Username,
+		Groups:    request.UserInfo.Groups,
+		Reason:    object.Annotations[Annotation],
+		Denials:   denials,
+	}
+}
+
+// String returns the record as JSON, for the log
+func (r AuditRecord) String() string {
+	out, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Sprintf("%+v", struct{ AuditRecord }{r})
+	}
+	return string(out)
+}
+
+// Event returns a Warning Event about the workload for the record
+func (r AuditRecord) Event(request *admissionv1.AdmissionRequest, object metav1.ObjectMeta) *corev1.Event {
+	now := metav1.NewTime(r.Time)
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "portieris-break-glass-",
+			Namespace:    r.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: metav1.GroupVersion{Group: request.Kind.Group, Version: request.Kind.Version}.String(),
+			Kind:       r.Kind,
+			Namespace:  r.Namespace,
+			Name:       r.Name,
+			UID:        object.UID,
+		},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BreakGlass",
+		Message:        fmt.Sprintf("Admitted despite policy denial by %s breaking glass: %s", r.User, r.Reason),
+		Source:         corev1.EventSource{Component: "portieris"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breakglass
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig("default, prod-*", "", "system:masters,")
+	assert.Equal(t, Config{
+		Namespaces: []string{"default", "prod-*"},
+		Groups:     []string{"system:masters"},
+	}, config)
+}
+
+func TestConfig_Allows(t *testing.T) {
+	config := Config{
+		Namespaces: []string{"default", "prod-*"},
+		Users:      []string{"alice", "system:serviceaccount:ops:*"},
+		Groups:     []string{"sre"},
+	}
+	tests := []struct {
+		name      string
+		config    Config
+		namespace string
+		user      authenticationv1.UserInfo
+		want      bool
+	}{
+		{
+			name:      "empty config allows nobody",
+			namespace: "default",
+			user:      authenticationv1.UserInfo{Username: "alice"},
+		},
+		{
+			name:      "allowed user in allowed namespace",
+			config:    config,
+			namespace: "default",
+			user:      authenticationv1.UserInfo{Username: "alice"},
+			want:      true,
+		},
+		{
+			name:      "allowed user matches a wildcard",
+			config:    config,
+			namespace: "prod-eu",
+			user:      authenticationv1.UserInfo{Username: "system:serviceaccount:ops:deployer"},
+			want:      true,
+		},
+		{
+			name:      "member of an allowed group",
+			config:    config,
+			namespace: "prod-us",
+			user:      authenticationv1.UserInfo{Username: "bob", Groups: []string{"dev", "sre"}},
+			want:      true,
+		},
+		{
+			name:      "allowed user in another namespace",
+			config:    config,
+			namespace: "kube-system",
+			user:      authenticationv1.UserInfo{Username: "alice"},
+		},
+		{
+			name:      "user not allowed",
+			config:    config,
+			namespace: "default",
+			user:      authenticationv1.UserInfo{Username: "bob", Groups: []string{"dev"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.config.Allows(tt.namespace, tt.user))
+		})
+	}
+}
+
+func TestAuditRecord(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	request := &admissionv1.AdmissionRequest{
+		UID:       "request-uid",
+		Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Namespace: "default",
+		Operation: admissionv1.Create,
+		UserInfo:  authenticationv1.UserInfo{Username: "alice", Groups: []string{"sre"}},
+	}
+	object := metav1.ObjectMeta{
+		Name:        "web",
+		UID:         "object-uid",
+		Annotations: map[string]string{Annotation: "INC-42 registry outage"},
+	}
+
+	record := NewAuditRecord(request, object, []string{"denied"}, now)
+	assert.Equal(t, AuditRecord{
+		Time:      now,
+		UID:       "request-uid",
+		Operation: "CREATE",
+		Kind:      "Deployment",
+		Namespace: "default",
+		Name:      "web",
+		User:      "alice",
+		Groups:    []string{"sre"},
+		Reason:    "INC-42 registry outage",
+		Denials:   []string{"denied"},
+	}, record)
+
+	var decoded AuditRecord
+	assert.NoError(t, json.Unmarshal([]byte(record.String()), &decoded))
+	assert.Equal(t, record, decoded)
+
+	event := record.Event(request, object)
+	assert.Equal(t, "default", event.Namespace)
+	assert.Equal(t, corev1.EventTypeWarning, event.Type)
+	assert.Equal(t, "BreakGlass", event.Reason)
+	assert.Equal(t, corev1.ObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Namespace:  "default",
+		Name:       "web",
+		UID:        "object-uid",
+	}, event.InvolvedObject)
+	assert.Equal(t, "Admitted despite policy denial by alice breaking glass: INC-42 registry outage", event.Message)
+}
@@ -1,4 +1,4 @@
-// Copyright 2018,2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -14,9 +14,14 @@
 
 package controller
 
-import admissionv1 "k8s.io/api/admission/v1"
+import (
+	"context"
 
-// Interface are the methods required to implement a controller for the webhook package
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// Interface are the methods required to implement a controller for the webhook package,
+// the context is cancelled when the API server abandons the admission request
 type Interface interface {
-	Admit(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+	Admit(context.Context, *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
 }
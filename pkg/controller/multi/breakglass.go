@@ -0,0 +1,64 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IBM/portieris/pkg/breakglass"
+	"github.com/IBM/portieris/pkg/webhook"
+	"github.com/golang/glog"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// breakGlass overrides the denial of a workload that has the break-glass annotation, when the user is allowed
+// to break glass in the namespace. The denials become a warning and an audit record is logged, with an Event
+// and a metric. It returns whether the denial was overridden.
+func (c *Controller) breakGlass(a *webhook.AdmissionResponder, namespace string, admissionRequest *admissionv1.AdmissionRequest) bool {
+	if admissionRequest == nil || len(admissionRequest.Object.Raw) == 0 {
+		return false
+	}
+	var object metav1.PartialObjectMetadata
+	if err := json.Unmarshal(admissionRequest.Object.Raw, &object); err != nil {
+		glog.Errorf("Unable to read metadata for break-glass: %v", err)
+		return false
+	}
+	reason, ok := object.Annotations[breakglass.Annotation]
+	if !ok {
+		return false
+	}
+	if strings.TrimSpace(reason) == "" {
+		a.StringToAdmissionResponse(fmt.Sprintf("break-glass: annotation %s must give a reason", breakglass.Annotation))
+		return false
+	}
+	if !c.breakGlassConfig.Allows(namespace, admissionRequest.UserInfo) {
+		a.StringToAdmissionResponse(fmt.Sprintf("break-glass: user %q is not allowed to break glass in namespace %q", admissionRequest.UserInfo.Username, namespace))
+		return false
+	}
+
+	record := breakglass.NewAuditRecord(admissionRequest, object.ObjectMeta, a.Errors(), time.Now())
+	a.ClearErrors()
+	a.AddWarning(fmt.Sprintf("admitted by break-glass despite policy denial: %s", strings.Join(record.Denials, "; ")))
+	glog.Warningf("Break-glass audit: %s", record)
+	if err := c.kubeClientsetWrapper.CreateEvent(record.Event(admissionRequest, object.ObjectMeta)); err != nil {
+		glog.Errorf("Unable to create break-glass event: %v", err)
+	}
+	c.PMetrics.BreakGlassCount.Inc()
+	return true
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"testing"
+
+	"github.com/IBM/portieris/pkg/breakglass"
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/IBM/portieris/pkg/webhook"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestController_breakGlass(t *testing.T) {
+	config := breakglass.Config{Namespaces: []string{"default"}, Groups: []string{"sre"}}
+	sre := authenticationv1.UserInfo{Username: "alice", Groups: []string{"sre"}}
+	tests := []struct {
+		name       string
+		object     string
+		user       authenticationv1.UserInfo
+		want       bool
+		wantErrors []string
+	}{
+		{
+			name:       "no annotation",
+			object:     `{"metadata":{"name":"web"}}`,
+			user:       sre,
+			wantErrors: []string{"denied"},
+		},
+		{
+			name:   "allowed user breaks glass",
+			object: `{"metadata":{"name":"web","annotations":{"portieris.cloud.ibm.com/break-glass":"INC-42"}}}`,
+			user:   sre,
+			want:   true,
+		},
+		{
+			name:       "annotation without a reason",
+			object:     `{"metadata":{"name":"web","annotations":{"portieris.cloud.ibm.com/break-glass":" "}}}`,
+			user:       sre,
+			wantErrors: []string{"denied", "break-glass: annotation portieris.cloud.ibm.com/break-glass must give a reason"},
+		},
+		{
+			name:       "user not allowed",
+			object:     `{"metadata":{"name":"web","annotations":{"portieris.cloud.ibm.com/break-glass":"INC-42"}}}`,
+			user:       authenticationv1.UserInfo{Username: "bob"},
+			wantErrors: []string{"denied", `break-glass: user "bob" is not allowed to break glass in namespace "default"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeWrapper := &mockKubeWrapper{}
+			kubeWrapper.On("CreateEvent", mock.AnythingOfType("*v1.Event")).Return(nil)
+			c := &Controller{
+				kubeClientsetWrapper: kubeWrapper,
+				PMetrics:             metrics.NewMetrics(),
+				breakGlassConfig:     config,
+			}
+			defer c.PMetrics.UnregisterAll()
+			a := &webhook.AdmissionResponder{}
+			a.StringToAdmissionResponse("denied")
+			a.SetAllowed()
+			request := &admissionv1.AdmissionRequest{
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: []byte(tt.object)},
+				UserInfo:  tt.user,
+			}
+
+			got := c.breakGlass(a, "default", request)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErrors, a.Errors())
+			resp := a.Flush()
+			assert.Equal(t, tt.want, resp.Allowed)
+			if tt.want {
+				assert.Equal(t, []string{"admitted by break-glass despite policy denial: denied"}, resp.Warnings)
+				assert.Equal(t, float64(1), testutil.ToFloat64(c.PMetrics.BreakGlassCount))
+				kubeWrapper.AssertCalled(t, "CreateEvent", mock.MatchedBy(func(event *corev1.Event) bool {
+					return event.Reason == "BreakGlass" && event.InvolvedObject.Name == "web"
+				}))
+			} else {
+				kubeWrapper.AssertNotCalled(t, "CreateEvent", mock.Anything)
+			}
+		})
+	}
+}
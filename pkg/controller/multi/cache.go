@@ -0,0 +1,373 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/IBM/portieris/helpers/credential"
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/IBM/portieris/pkg/registry"
+	"github.com/IBM/portieris/pkg/transport"
+	"github.com/IBM/portieris/pkg/verifier/imageconfig"
+	"github.com/IBM/portieris/pkg/verifier/vulnerability"
+	"github.com/golang/glog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerificationCache holds the results of verifying images against policies, keyed by the digest that the image
+// resolves to, the policy and the credentials used, so that repeated admissions of the same image skip signature
+// verification and vulnerability scans until the result expires. Results that deny an image expire after negativeTTL.
+type VerificationCache struct {
+	ttl         time.Duration
+	negativeTTL time.Duration
+	pm          *metrics.PortierisMetrics
+	// resolve returns the hex digest that the image refers to in its registry
+	resolve func(ctx context.Context, img *image.Reference, credentials credential.Credentials) (string, error)
+	now     func() time.Time
+	// records shares the results that allow images with other replicas when it is not nil
+	records *VerificationRecords
+
+	mutex     sync.Mutex
+	entries   map[string]cacheEntry
+	lastPrune time.Time
+}
+
+type cacheEntry struct {
+	digest  *bytes.Buffer
+	deny    error
+	scan    vulnerability.ScanResponse
+	expires time.Time
+}
+
+// NewVerificationCache creates a verification cache, a zero ttl or negativeTTL disables caching of results that allow or deny images
+func NewVerificationCache(ttl, negativeTTL time.Duration, pm *metrics.PortierisMetrics) *VerificationCache {
+	return &VerificationCache{
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		pm:          pm,
+		resolve:     resolveDigest(nil),
+		now:         time.Now,
+		entries:     map[string]cacheEntry{},
+	}
+}
+
+// WithTransport resolves digests with the proxy and trusted CAs of t, it must be called before the cache is used
+func (vc *VerificationCache) WithTransport(t *transport.Transport) *VerificationCache {
+	vc.resolve = resolveDigest(t)
+	return vc
+}
+
+func resolveDigest(t *transport.Transport) func(ctx context.Context, img *image.Reference, credentials credential.Credentials) (string, error) {
+	return func(ctx context.Context, img *image.Reference, credentials credential.Credentials) (string, error) {
+		return registry.GetDigest(ctx, img.String(), credentials, registry.NewSystemContext(t))
+	}
+}
+
+// ShareWith shares the results that allow images through records, so that other replicas, and replicas that start
+// later, reuse them. A result is dropped from the cache when its record is deleted. It must be called before the cache
+// is used, and before the records are started.
+func (vc *VerificationCache) ShareWith(records *VerificationRecords) {
+	vc.records = records
+	records.onDelete(func(key string) {
+		vc.mutex.Lock()
+		defer vc.mutex.Unlock()
+		delete(vc.entries, key)
+	})
+}
+
+// Flush discards all cached results, and deletes the records of results that allow images so that other replicas
+// discard them too
+func (vc *VerificationCache) Flush(ctx context.Context) error {
+	vc.mutex.Lock()
+	vc.entries = map[string]cacheEntry{}
+	vc.mutex.Unlock()
+	glog.Info("Verification cache flushed")
+	if vc.records != nil {
+		return vc.records.flush(ctx)
+	}
+	return nil
+}
+
+// ServeHTTP flushes the cache on a POST request
+func (vc *VerificationCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := vc.Flush(r.Context()); err != nil {
+		glog.Errorf("Unable to delete ImageVerifications: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// key identifies a verification of the image by its resolved digest, which it also returns, and the versions of the
+// secrets the policy reads, it returns false when the digest can't be resolved
+func (vc *VerificationCache) key(ctx context.Context, kind, namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy, secrets []string) (string, string, bool) {
+	digest := img.GetDigest()
+	if digest == "" {
+		var err error
+		if digest, err = vc.resolve(ctx, img, credentials); err != nil {
+			glog.Warningf("Verification cache not used for image %s: %v", img.String(), err)
+			return "", "", false
+		}
+	}
+	key, err := verificationKey(kind, namespace, img, digest, credentials, policy, secrets)
+	if err != nil {
+		return "", "", false
+	}
+	return key, digest, true
+}
+
+// verificationKey identifies a verification of the image, at the digest when it is known, with the credentials, policy
+// and versions of the secrets that the policy reads
+func verificationKey(kind, namespace string, img *image.Reference, digest string, credentials credential.Credentials, policy *policyv1.Policy, secrets []string) (string, error) {
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	// the namespace is part of the key because secrets named by the policy are read from it,
+	// and the credentials are hashed so that they are not held in the key
+	h := sha256.New()
+	for _, field := range []string{kind, namespace, img.String(), digest, string(policyJSON)} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	for _, c := range credentials {
+		h.Write([]byte(c.Username))
+		h.Write([]byte{0})
+		h.Write([]byte(c.Password))
+		h.Write([]byte{0})
+	}
+	for _, secret := range secrets {
+		h.Write([]byte(secret))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// secretVersions returns the namespace, name and resource version of each signer, key and store secret that the
+// policy reads for a workload in namespace, so that a result is not reused once one of them changes
+func secretVersions(kubeWrapper kubernetes.WrapperInterface, namespace string, policy *policyv1.Policy) ([]string, error) {
+	var versions []string
+	add := func(secretNamespace, name string) error {
+		if secretNamespace == "" {
+			secretNamespace = namespace
+		}
+		secret, err := kubeWrapper.GetSecret(secretNamespace, name)
+		if err != nil {
+			return err
+		}
+		versions = append(versions, secretNamespace+"/"+name+"@"+secret.ResourceVersion)
+		return nil
+	}
+	if policy.Trust.Enabled != nil && *policy.Trust.Enabled {
+		for _, signer := range policy.Trust.SignerSecrets {
+			if err := add(signer.Namespace, signer.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, requirement := range policy.Simple.Requirements {
+		if requirement.KeySecret != "" {
+			if err := add(requirement.KeySecretNamespace, requirement.KeySecret); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(policy.Simple.Requirements) > 0 && policy.Simple.StoreSecret != "" {
+		if err := add(policy.Simple.StoreSecretNamespace, policy.Simple.StoreSecret); err != nil {
+			return nil, err
+		}
+	}
+	return versions, nil
+}
+
+// freshnessKey is the context key of the margin set by withFreshness
+type freshnessKey struct{}
+
+// withFreshness marks verifications that refresh the cache in the background: results that expire within margin
+// are verified again, so that they are still cached margin later, and lookups are not counted as admission hits or misses
+func withFreshness(ctx context.Context, margin time.Duration) context.Context {
+	return context.WithValue(ctx, freshnessKey{}, margin)
+}
+
+// get returns the result cached for the key, or recorded by another replica, unless it expires within the freshness
+// margin of the context
+func (vc *VerificationCache) get(ctx context.Context, key string) (cacheEntry, bool) {
+	margin, refresh := ctx.Value(freshnessKey{}).(time.Duration)
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+	entry, ok := vc.entries[key]
+	if ok && !vc.now().Before(entry.expires) {
+		delete(vc.entries, key)
+		ok = false
+	}
+	if !ok && vc.records != nil {
+		if entry, ok = vc.records.get(ctx, key); ok {
+			vc.entries[key] = entry
+		}
+	}
+	if refresh {
+		return entry, ok && vc.now().Add(margin).Before(entry.expires)
+	}
+	if ok {
+		vc.pm.VerificationCacheHitCount.Inc()
+	} else {
+		vc.pm.VerificationCacheMissCount.Inc()
+	}
+	return entry, ok
+}
+
+func (vc *VerificationCache) set(key string, entry cacheEntry, denied bool) {
+	ttl := vc.ttl
+	if denied {
+		ttl = vc.negativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+	now := vc.now()
+	// expired entries that are not looked up again are removed at most once per the longer ttl
+	interval := vc.ttl
+	if vc.negativeTTL > interval {
+		interval = vc.negativeTTL
+	}
+	if now.Sub(vc.lastPrune) > interval {
+		for k, e := range vc.entries {
+			if !now.Before(e.expires) {
+				delete(vc.entries, k)
+			}
+		}
+		vc.lastPrune = now
+	}
+	entry.expires = now.Add(ttl)
+	vc.entries[key] = entry
+}
+
+// record shares a result that allows an image, for as long as it is cached
+func (vc *VerificationCache) record(ctx context.Context, key string, spec policyv1.ImageVerificationSpec) {
+	if vc.records == nil || vc.ttl <= 0 {
+		return
+	}
+	spec.ExpiresAt = metav1.NewTime(vc.now().Add(vc.ttl))
+	vc.records.put(ctx, key, spec)
+}
+
+// cachingEnforcer is an Enforcer that reuses the results of the Enforcer it wraps from a verification cache
+type cachingEnforcer struct {
+	Enforcer
+	cache *VerificationCache
+	// kubeWrapper reads the secrets named by policies, whose versions are part of the cache key
+	kubeWrapper kubernetes.WrapperInterface
+}
+
+// verifies reports whether the policy needs images to be verified against their registry, other policies are
+// cheap to enforce and are not cached, because resolving the digest would cost more than enforcing them
+func verifies(policy *policyv1.Policy) bool {
+	return len(policy.Simple.Requirements) > 0 || (policy.Trust.Enabled != nil && *policy.Trust.Enabled) || imageconfig.Enabled(policy)
+}
+
+func scans(policy *policyv1.Policy) bool {
+	return policy.Vulnerability.ICCRVA.Enabled != nil && *policy.Vulnerability.ICCRVA.Enabled
+}
+
+// verifiers names the verifiers that a policy needs images to be verified by
+func verifiers(policy *policyv1.Policy) []string {
+	var names []string
+	if policy.Trust.Enabled != nil && *policy.Trust.Enabled {
+		names = append(names, "trust")
+	}
+	if len(policy.Simple.Requirements) > 0 {
+		names = append(names, "simple")
+	}
+	if imageconfig.Enabled(policy) {
+		names = append(names, "imageconfig")
+	}
+	return names
+}
+
+func (e *cachingEnforcer) DigestByPolicy(ctx context.Context, namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
+	if policy == nil || !verifies(policy) {
+		return e.Enforcer.DigestByPolicy(ctx, namespace, img, credentials, policy)
+	}
+	secrets, err := secretVersions(e.kubeWrapper, namespace, policy)
+	if err != nil {
+		glog.Warningf("Verification cache not used for image %s: %v", img.String(), err)
+		return e.Enforcer.DigestByPolicy(ctx, namespace, img, credentials, policy)
+	}
+	key, _, ok := e.cache.key(ctx, "digest", namespace, img, credentials, policy, secrets)
+	if !ok {
+		return e.Enforcer.DigestByPolicy(ctx, namespace, img, credentials, policy)
+	}
+	if entry, ok := e.cache.get(ctx, key); ok {
+		glog.Infof("Verification of image %s found in the cache", img.String())
+		return copyDigest(entry.digest), entry.deny, nil
+	}
+	digest, deny, err := e.Enforcer.DigestByPolicy(ctx, namespace, img, credentials, policy)
+	// a verification that was cut short by the caller is not a result
+	if err == nil && ctx.Err() == nil {
+		e.cache.set(key, cacheEntry{digest: copyDigest(digest), deny: deny}, deny != nil)
+		if deny == nil && digest != nil {
+			e.cache.record(ctx, key, policyv1.ImageVerificationSpec{Image: img.String(), Digest: digest.String(), Namespace: namespace, Verifiers: verifiers(policy)})
+		}
+	}
+	return digest, deny, err
+}
+
+func (e *cachingEnforcer) VulnerabilityPolicy(ctx context.Context, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) vulnerability.ScanResponse {
+	if policy == nil || !scans(policy) {
+		return e.Enforcer.VulnerabilityPolicy(ctx, img, credentials, policy)
+	}
+	key, resolved, ok := e.cache.key(ctx, "vulnerability", "", img, credentials, policy, nil)
+	if !ok {
+		return e.Enforcer.VulnerabilityPolicy(ctx, img, credentials, policy)
+	}
+	if entry, ok := e.cache.get(ctx, key); ok {
+		glog.Infof("Vulnerability scan of image %s found in the cache", img.String())
+		return entry.scan
+	}
+	scan := e.Enforcer.VulnerabilityPolicy(ctx, img, credentials, policy)
+	if ctx.Err() == nil {
+		e.cache.set(key, cacheEntry{scan: scan}, !scan.CanDeploy)
+		if scan.CanDeploy {
+			e.cache.record(ctx, key, policyv1.ImageVerificationSpec{Image: img.String(), Digest: resolved, Verifiers: []string{"vulnerability"}})
+		}
+	}
+	return scan
+}
+
+// copyDigest copies a digest so that a cached digest is not consumed by its readers
+func copyDigest(digest *bytes.Buffer) *bytes.Buffer {
+	if digest == nil {
+		return nil
+	}
+	return bytes.NewBufferString(digest.String())
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IBM/portieris/helpers/credential"
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/IBM/portieris/pkg/verifier/vulnerability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// setupCache creates a caching enforcer around a mock enforcer, with a clock that tests can move and
+// tags that resolve to the digests in the map it returns
+func setupCache(t *testing.T) (*cachingEnforcer, *mockEnforcer, *time.Time, map[string]string) {
+	pm := metrics.NewMetrics()
+	t.Cleanup(pm.UnregisterAll)
+	now := time.Now()
+	digests := map[string]string{}
+	cache := NewVerificationCache(time.Minute, 10*time.Second, pm)
+	cache.now = func() time.Time { return now }
+	cache.resolve = func(ctx context.Context, img *image.Reference, credentials credential.Credentials) (string, error) {
+		if digest, ok := digests[img.String()]; ok {
+			return digest, nil
+		}
+		return "", errors.New("manifest unknown")
+	}
+	me := &mockEnforcer{}
+	kubeWrapper := kubernetes.NewKubeClientsetWrapper(k8sfake.NewSimpleClientset())
+	return &cachingEnforcer{Enforcer: me, cache: cache, kubeWrapper: kubeWrapper}, me, &now, digests
+}
+
+func TestCachingEnforcer_DigestByPolicy(t *testing.T) {
+	trust := &policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.TruePointer}}
+	otherTrust := &policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.TruePointer, TrustServer: "https://notary.example.com"}}
+	creds := credential.Credentials{{Username: "user", Password: "password"}}
+	otherCreds := credential.Credentials{{Username: "user", Password: "other"}}
+	pinned, _ := image.NewReference("icr.io/hello/world@sha256:1234")
+	tagged, _ := image.NewReference("icr.io/hello/world:latest")
+
+	t.Run("reuses a verification of the same digest, policy, namespace and credentials", func(t *testing.T) {
+		e, me, _, _ := setupCache(t)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		for i := 0; i < 3; i++ {
+			digest, deny, err := e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+			assert.NoError(t, err)
+			assert.NoError(t, deny)
+			assert.Equal(t, "1234", digest.String())
+		}
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+
+		e.DigestByPolicy(context.Background(), "other", pinned, creds, trust)
+		e.DigestByPolicy(context.Background(), "default", pinned, otherCreds, trust)
+		e.DigestByPolicy(context.Background(), "default", pinned, creds, otherTrust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 4)
+		assert.Equal(t, 2.0, testutil.ToFloat64(e.cache.pm.VerificationCacheHitCount))
+		assert.Equal(t, 4.0, testutil.ToFloat64(e.cache.pm.VerificationCacheMissCount))
+	})
+
+	t.Run("keys a tag by the digest it resolves to", func(t *testing.T) {
+		e, me, _, digests := setupCache(t)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		digests[tagged.String()] = "1234"
+		e.DigestByPolicy(context.Background(), "default", tagged, creds, trust)
+		e.DigestByPolicy(context.Background(), "default", tagged, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+
+		digests[tagged.String()] = "5678"
+		e.DigestByPolicy(context.Background(), "default", tagged, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+
+		delete(digests, tagged.String())
+		e.DigestByPolicy(context.Background(), "default", tagged, creds, trust)
+		e.DigestByPolicy(context.Background(), "default", tagged, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 4)
+	})
+
+	t.Run("expires results that allow after the ttl", func(t *testing.T) {
+		e, me, now, _ := setupCache(t)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		*now = now.Add(59 * time.Second)
+		e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+		*now = now.Add(time.Second)
+		e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+	})
+
+	t.Run("expires results that deny after the negative ttl", func(t *testing.T) {
+		e, me, now, _ := setupCache(t)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return((*bytes.Buffer)(nil), errors.New("trust: policy denied the request"), nil)
+
+		_, deny, _ := e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		assert.EqualError(t, deny, "trust: policy denied the request")
+		_, deny, _ = e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		assert.EqualError(t, deny, "trust: policy denied the request")
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+		*now = now.Add(10 * time.Second)
+		e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		e, me, _, _ := setupCache(t)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return((*bytes.Buffer)(nil), nil, errors.New("notary unavailable"))
+
+		e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		_, _, err := e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		assert.EqualError(t, err, "notary unavailable")
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+	})
+
+	t.Run("does not cache verifications that were cancelled", func(t *testing.T) {
+		e, me, _, _ := setupCache(t)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		e.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+	})
+
+	t.Run("refreshes results that expire within the freshness margin", func(t *testing.T) {
+		e, me, now, _ := setupCache(t)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		*now = now.Add(30 * time.Second)
+		e.DigestByPolicy(withFreshness(context.Background(), 20*time.Second), "default", pinned, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+		e.DigestByPolicy(withFreshness(context.Background(), 40*time.Second), "default", pinned, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+		*now = now.Add(40 * time.Second)
+		e.DigestByPolicy(context.Background(), "default", pinned, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+		assert.Equal(t, 1.0, testutil.ToFloat64(e.cache.pm.VerificationCacheHitCount))
+		assert.Equal(t, 1.0, testutil.ToFloat64(e.cache.pm.VerificationCacheMissCount))
+	})
+
+	t.Run("does not resolve or cache policies without verification", func(t *testing.T) {
+		e, me, _, _ := setupCache(t)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return((*bytes.Buffer)(nil), nil, nil)
+
+		e.DigestByPolicy(context.Background(), "default", tagged, creds, &policyv1.Policy{})
+		e.DigestByPolicy(context.Background(), "default", tagged, creds, &policyv1.Policy{})
+		e.DigestByPolicy(context.Background(), "default", tagged, creds, nil)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 3)
+		assert.Equal(t, 0.0, testutil.ToFloat64(e.cache.pm.VerificationCacheMissCount))
+	})
+}
+
+func TestCachingEnforcer_VulnerabilityPolicy(t *testing.T) {
+	va := &policyv1.Policy{Vulnerability: policyv1.Vulnerability{ICCRVA: policyv1.ICCRVA{Enabled: policyv1.TruePointer}}}
+	pinned, _ := image.NewReference("icr.io/hello/world@sha256:1234")
+
+	e, me, now, _ := setupCache(t)
+	me.On("VulnerabilityPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(vulnerability.ScanResponse{CanDeploy: false, DenyReason: "vulnerable"})
+
+	assert.Equal(t, "vulnerable", e.VulnerabilityPolicy(context.Background(), pinned, nil, va).DenyReason)
+	assert.Equal(t, "vulnerable", e.VulnerabilityPolicy(context.Background(), pinned, nil, va).DenyReason)
+	me.AssertNumberOfCalls(t, "VulnerabilityPolicy", 1)
+	*now = now.Add(10 * time.Second)
+	e.VulnerabilityPolicy(context.Background(), pinned, nil, va)
+	me.AssertNumberOfCalls(t, "VulnerabilityPolicy", 2)
+
+	e.VulnerabilityPolicy(context.Background(), pinned, nil, &policyv1.Policy{})
+	e.VulnerabilityPolicy(context.Background(), pinned, nil, &policyv1.Policy{})
+	me.AssertNumberOfCalls(t, "VulnerabilityPolicy", 4)
+}
+
+func TestCachingEnforcer_DigestByPolicySecrets(t *testing.T) {
+	signed := &policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.TruePointer, SignerSecrets: []policyv1.TrustSigner{{Name: "signer"}}}}
+	pinned, _ := image.NewReference("icr.io/hello/world@sha256:1234")
+	e, me, _, _ := setupCache(t)
+	me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+	// the cache is not used when a secret can't be read
+	e.DigestByPolicy(context.Background(), "default", pinned, nil, signed)
+	e.DigestByPolicy(context.Background(), "default", pinned, nil, signed)
+	me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "signer", ResourceVersion: "1"}}
+	_, err := e.kubeWrapper.CoreV1().Secrets("default").Create(context.Background(), secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+	e.DigestByPolicy(context.Background(), "default", pinned, nil, signed)
+	e.DigestByPolicy(context.Background(), "default", pinned, nil, signed)
+	me.AssertNumberOfCalls(t, "DigestByPolicy", 3)
+
+	// a changed signer secret is verified again
+	secret.ResourceVersion = "2"
+	_, err = e.kubeWrapper.CoreV1().Secrets("default").Update(context.Background(), secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	e.DigestByPolicy(context.Background(), "default", pinned, nil, signed)
+	e.DigestByPolicy(context.Background(), "default", pinned, nil, signed)
+	me.AssertNumberOfCalls(t, "DigestByPolicy", 4)
+}
+
+func TestVerificationCache_ServeHTTP(t *testing.T) {
+	trust := &policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.TruePointer}}
+	pinned, _ := image.NewReference("icr.io/hello/world@sha256:1234")
+	e, me, _, _ := setupCache(t)
+	me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+	e.DigestByPolicy(context.Background(), "default", pinned, nil, trust)
+	rr := httptest.NewRecorder()
+	e.cache.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/verification-cache/flush", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	e.DigestByPolicy(context.Background(), "default", pinned, nil, trust)
+	me.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+
+	rr = httptest.NewRecorder()
+	e.cache.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/verification-cache/flush", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	e.DigestByPolicy(context.Background(), "default", pinned, nil, trust)
+	me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+}
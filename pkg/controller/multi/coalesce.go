@@ -0,0 +1,190 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/portieris/helpers/credential"
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/IBM/portieris/pkg/verifier/vulnerability"
+	"github.com/golang/glog"
+)
+
+// flightGroup runs a call once for the callers with the same key that arrive while it is in progress
+type flightGroup struct {
+	// timeout limits each call, zero is no limit
+	timeout time.Duration
+
+	mutex   sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	value   interface{}
+}
+
+// do returns the result of calling fn, or of the call in progress for the key, and whether the result is shared with
+// another caller. A caller returns the error of its context when it is done, and the call is cancelled when every
+// caller has returned, so that the call is not cancelled by the first caller giving up while others wait for it.
+// The call is made with a context detached from the callers, which carries only the values that are part of the key.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) interface{}) (interface{}, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	g.mutex.Lock()
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+	f, shared := g.flights[key]
+	if !shared {
+		flightCtx := detach(ctx)
+		var cancel context.CancelFunc
+		if g.timeout > 0 {
+			flightCtx, cancel = context.WithTimeout(flightCtx, g.timeout)
+		} else {
+			flightCtx, cancel = context.WithCancel(flightCtx)
+		}
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+		go func() {
+			defer close(f.done)
+			defer cancel()
+			f.value = fn(flightCtx)
+			g.forget(key, f)
+		}()
+	}
+	f.waiters++
+	g.mutex.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, shared, nil
+	case <-ctx.Done():
+		g.mutex.Lock()
+		f.waiters--
+		last := f.waiters == 0
+		g.mutex.Unlock()
+		if last {
+			// callers that arrive later start a new call rather than waiting for one that is cancelled
+			g.forget(key, f)
+			f.cancel()
+		}
+		return nil, shared, ctx.Err()
+	}
+}
+
+func (g *flightGroup) forget(key string, f *flight) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
+
+// detach returns a context with the values of ctx that verifications read, the policy source and freshness margin,
+// without its deadline, cancellation or other values
+func detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if source, ok := ctx.Value(policySourceKey{}).(policySource); ok {
+		detached = context.WithValue(detached, policySourceKey{}, source)
+	}
+	if margin, ok := ctx.Value(freshnessKey{}).(time.Duration); ok {
+		detached = withFreshness(detached, margin)
+	}
+	return detached
+}
+
+// flightKey extends the key of a verification with the context values that detach keeps, so that verifications are
+// only shared by callers that would make them with the same values, for example an admission and a background
+// verification of the same image are not shared
+func flightKey(ctx context.Context, key string) string {
+	if source, ok := ctx.Value(policySourceKey{}).(policySource); ok {
+		key += fmt.Sprintf("/%s/%d", source.name, source.generation)
+	}
+	if margin, ok := ctx.Value(freshnessKey{}).(time.Duration); ok {
+		key += "/fresh/" + margin.String()
+	}
+	return key
+}
+
+// coalescingEnforcer is an Enforcer that shares the results of identical verifications that are in progress, so
+// that the admissions of the pods of a rolling deployment make one set of requests to registries, notary and
+// vulnerability scanners for the same image, policy and credentials
+type coalescingEnforcer struct {
+	Enforcer
+	flights flightGroup
+	pm      *metrics.PortierisMetrics
+}
+
+type digestResult struct {
+	digest *bytes.Buffer
+	deny   error
+	err    error
+}
+
+func (e *coalescingEnforcer) DigestByPolicy(ctx context.Context, namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
+	if policy == nil || !verifies(policy) {
+		return e.Enforcer.DigestByPolicy(ctx, namespace, img, credentials, policy)
+	}
+	key, err := verificationKey("digest", namespace, img, img.GetDigest(), credentials, policy, nil)
+	if err != nil {
+		return e.Enforcer.DigestByPolicy(ctx, namespace, img, credentials, policy)
+	}
+	value, shared, err := e.flights.do(ctx, flightKey(ctx, key), func(ctx context.Context) interface{} {
+		digest, deny, err := e.Enforcer.DigestByPolicy(ctx, namespace, img, credentials, policy)
+		return digestResult{digest: digest, deny: deny, err: err}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if shared {
+		glog.Infof("Verification of image %s shared with an identical verification in progress", img.String())
+		e.pm.VerificationCoalescedCount.Inc()
+	}
+	result := value.(digestResult)
+	// each caller reads its own copy of the digest
+	return copyDigest(result.digest), result.deny, result.err
+}
+
+func (e *coalescingEnforcer) VulnerabilityPolicy(ctx context.Context, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) vulnerability.ScanResponse {
+	if policy == nil || !scans(policy) {
+		return e.Enforcer.VulnerabilityPolicy(ctx, img, credentials, policy)
+	}
+	key, err := verificationKey("vulnerability", "", img, img.GetDigest(), credentials, policy, nil)
+	if err != nil {
+		return e.Enforcer.VulnerabilityPolicy(ctx, img, credentials, policy)
+	}
+	value, shared, err := e.flights.do(ctx, flightKey(ctx, key), func(ctx context.Context) interface{} {
+		return e.Enforcer.VulnerabilityPolicy(ctx, img, credentials, policy)
+	})
+	if err != nil {
+		return vulnerability.ScanResponse{CanDeploy: false, DenyReason: err.Error()}
+	}
+	if shared {
+		glog.Infof("Vulnerability scan of image %s shared with an identical scan in progress", img.String())
+		e.pm.VerificationCoalescedCount.Inc()
+	}
+	return value.(vulnerability.ScanResponse)
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/portieris/helpers/credential"
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/IBM/portieris/pkg/verifier/vulnerability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCoalescing(t *testing.T) (*coalescingEnforcer, *mockEnforcer) {
+	pm := metrics.NewMetrics()
+	t.Cleanup(pm.UnregisterAll)
+	me := &mockEnforcer{}
+	me.Test(t)
+	return &coalescingEnforcer{Enforcer: me, pm: pm}, me
+}
+
+// waitForWaiters waits until n callers are waiting for the calls in progress
+func waitForWaiters(t *testing.T, e *coalescingEnforcer, n int) {
+	assert.Eventually(t, func() bool {
+		e.flights.mutex.Lock()
+		defer e.flights.mutex.Unlock()
+		waiters := 0
+		for _, f := range e.flights.flights {
+			waiters += f.waiters
+		}
+		return waiters == n
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestCoalescingEnforcer_DigestByPolicy(t *testing.T) {
+	trust := &policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.TruePointer}}
+	creds := credential.Credentials{{Username: "user", Password: "password"}}
+	img, _ := image.NewReference("docker.io/library/busybox:1")
+
+	t.Run("shares an identical verification in progress", func(t *testing.T) {
+		e, me := setupCoalescing(t)
+		release := make(chan struct{})
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) { <-release }).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		var wg sync.WaitGroup
+		digests := make([]*bytes.Buffer, 5)
+		for i := range digests {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				digests[i], _, _ = e.DigestByPolicy(context.Background(), "default", img, creds, trust)
+			}(i)
+		}
+		waitForWaiters(t, e, 5)
+		close(release)
+		wg.Wait()
+
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+		assert.Equal(t, 4.0, testutil.ToFloat64(e.pm.VerificationCoalescedCount))
+		for _, digest := range digests {
+			assert.Equal(t, "1234", digest.String())
+		}
+		// a verification that starts after the one in progress has finished is not shared
+		e.DigestByPolicy(context.Background(), "default", img, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+	})
+
+	t.Run("does not share verifications with other credentials or namespaces", func(t *testing.T) {
+		e, me := setupCoalescing(t)
+		release := make(chan struct{})
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) { <-release }).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		var wg sync.WaitGroup
+		for _, call := range []struct {
+			namespace   string
+			credentials credential.Credentials
+		}{{"default", creds}, {"default", nil}, {"other", creds}} {
+			wg.Add(1)
+			go func(namespace string, credentials credential.Credentials) {
+				defer wg.Done()
+				e.DigestByPolicy(context.Background(), namespace, img, credentials, trust)
+			}(call.namespace, call.credentials)
+		}
+		waitForWaiters(t, e, 3)
+		close(release)
+		wg.Wait()
+
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 3)
+		assert.Equal(t, 0.0, testutil.ToFloat64(e.pm.VerificationCoalescedCount))
+	})
+
+	t.Run("does not share verifications of other policy generations or background verifications", func(t *testing.T) {
+		e, me := setupCoalescing(t)
+		release := make(chan struct{})
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) { <-release }).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		admission := withPolicySource(context.Background(), &policyv1.PolicyMatch{Source: "default/signed", Generation: 1})
+		var wg sync.WaitGroup
+		for _, ctx := range []context.Context{
+			admission,
+			withPolicySource(context.Background(), &policyv1.PolicyMatch{Source: "default/signed", Generation: 2}),
+			withPolicySource(context.Background(), &policyv1.PolicyMatch{Source: "signed", Generation: 1}),
+			withFreshness(admission, time.Minute),
+		} {
+			wg.Add(1)
+			go func(ctx context.Context) {
+				defer wg.Done()
+				e.DigestByPolicy(ctx, "default", img, creds, trust)
+			}(ctx)
+		}
+		waitForWaiters(t, e, 4)
+		close(release)
+		wg.Wait()
+
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 4)
+		assert.Equal(t, 0.0, testutil.ToFloat64(e.pm.VerificationCoalescedCount))
+	})
+
+	t.Run("verifies with a detached context that has its own timeout", func(t *testing.T) {
+		e, me := setupCoalescing(t)
+		e.flights.timeout = time.Minute
+		verificationCtx := make(chan context.Context, 1)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			verificationCtx <- args.Get(0).(context.Context)
+		}).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		type callerKey struct{}
+		ctx := withPolicySource(context.WithValue(context.Background(), callerKey{}, "admission"), &policyv1.PolicyMatch{Source: "signed", Generation: 3})
+		ctx, cancel := context.WithTimeout(ctx, time.Hour)
+		defer cancel()
+		_, _, err := e.DigestByPolicy(ctx, "default", img, creds, trust)
+		assert.NoError(t, err)
+
+		got := <-verificationCtx
+		assert.Nil(t, got.Value(callerKey{}))
+		assert.Equal(t, policySource{name: "signed", generation: 3}, got.Value(policySourceKey{}))
+		deadline, ok := got.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 10*time.Second)
+	})
+
+	t.Run("cancels the verification when every caller has given up", func(t *testing.T) {
+		e, me := setupCoalescing(t)
+		verificationCtx := make(chan context.Context, 1)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			verificationCtx <- ctx
+			<-ctx.Done()
+		}).Return((*bytes.Buffer)(nil), nil, context.Canceled)
+
+		first, cancelFirst := context.WithCancel(context.Background())
+		second, cancelSecond := context.WithCancel(context.Background())
+		errs := make(chan error, 2)
+		for _, ctx := range []context.Context{first, second} {
+			go func(ctx context.Context) {
+				_, _, err := e.DigestByPolicy(ctx, "default", img, creds, trust)
+				errs <- err
+			}(ctx)
+		}
+		waitForWaiters(t, e, 2)
+		ctx := <-verificationCtx
+
+		cancelFirst()
+		assert.Equal(t, context.Canceled, <-errs)
+		assert.NoError(t, ctx.Err(), "the verification continues for the caller that is waiting")
+
+		cancelSecond()
+		assert.Equal(t, context.Canceled, <-errs)
+		<-ctx.Done()
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+	})
+
+	t.Run("does not share policies without verification", func(t *testing.T) {
+		e, me := setupCoalescing(t)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return((*bytes.Buffer)(nil), nil, nil)
+
+		e.DigestByPolicy(context.Background(), "default", img, creds, &policyv1.Policy{})
+		e.DigestByPolicy(context.Background(), "default", img, creds, nil)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+		assert.Empty(t, e.flights.flights)
+	})
+}
+
+func TestCoalescingEnforcer_VulnerabilityPolicy(t *testing.T) {
+	va := &policyv1.Policy{Vulnerability: policyv1.Vulnerability{ICCRVA: policyv1.ICCRVA{Enabled: policyv1.TruePointer}}}
+	img, _ := image.NewReference("icr.io/hello/world:1")
+	e, me := setupCoalescing(t)
+	release := make(chan struct{})
+	me.On("VulnerabilityPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) { <-release }).Return(vulnerability.ScanResponse{CanDeploy: false, DenyReason: "vulnerable"})
+
+	var wg sync.WaitGroup
+	responses := make([]vulnerability.ScanResponse, 3)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = e.VulnerabilityPolicy(context.Background(), img, nil, va)
+		}(i)
+	}
+	waitForWaiters(t, e, 3)
+	close(release)
+	wg.Wait()
+
+	me.AssertNumberOfCalls(t, "VulnerabilityPolicy", 1)
+	for _, response := range responses {
+		assert.Equal(t, "vulnerable", response.DenyReason)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, vulnerability.ScanResponse{CanDeploy: false, DenyReason: "context canceled"}, e.VulnerabilityPolicy(ctx, img, nil, va))
+	me.AssertNumberOfCalls(t, "VulnerabilityPolicy", 1)
+}
@@ -15,12 +15,16 @@
 package multi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/IBM/portieris/helpers/credential"
 	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/breakglass"
 	"github.com/IBM/portieris/pkg/kubernetes"
 	"github.com/IBM/portieris/pkg/metrics"
 	"github.com/IBM/portieris/pkg/policy"
@@ -30,6 +34,7 @@ import (
 	"github.com/golang/glog"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 )
@@ -46,24 +51,35 @@ type Controller struct {
 	Enforcer
 	// PMetrics is used to provide scrapable metrics for prometheus
 	PMetrics *metrics.PortierisMetrics
+	// breakGlassConfig selects who can admit a denied workload with the break-glass annotation
+	breakGlassConfig breakglass.Config
+	// verificationConfig controls how the images of a pod are verified
+	verificationConfig VerificationConfig
 }
 
 // NewController creates a new controller object from the various clients passed in
-func NewController(kubeWrapper kubernetes.WrapperInterface, policyClient policy.Interface, nv *notaryverifier.Verifier, pm *metrics.PortierisMetrics) *Controller {
-	enforcer := NewEnforcer(kubeWrapper, nv)
+func NewController(kubeWrapper kubernetes.WrapperInterface, policyClient policy.Interface, nv *notaryverifier.Verifier, pm *metrics.PortierisMetrics, breakGlassConfig breakglass.Config, verificationConfig VerificationConfig) *Controller {
+	enforcer := NewEnforcer(kubeWrapper, nv, verificationConfig.ScannerClient, verificationConfig.RegistryTransport)
+	if verificationConfig.Cache != nil {
+		enforcer = &cachingEnforcer{Enforcer: enforcer, cache: verificationConfig.Cache, kubeWrapper: kubeWrapper}
+	}
+	// identical verifications are coalesced before the cache, so that they resolve the digest of the image once
+	enforcer = &coalescingEnforcer{Enforcer: enforcer, pm: pm, flights: flightGroup{timeout: verificationConfig.Timeout}}
 	return &Controller{
 		kubeClientsetWrapper: kubeWrapper,
 		policyClient:         policyClient,
 		Enforcer:             enforcer,
 		PMetrics:             pm,
+		breakGlassConfig:     breakGlassConfig,
+		verificationConfig:   verificationConfig,
 	}
 }
 
 // Admit is the admissionRequest handler
-func (c *Controller) Admit(admissionRequest *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+func (c *Controller) Admit(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
 	glog.Infof("Processing admission request for %s on %s", admissionRequest.Operation, admissionRequest.Name)
 
-	podSpecLocation, ps, err := c.kubeClientsetWrapper.GetPodSpec(admissionRequest)
+	podSpecLocation, pt, err := c.kubeClientsetWrapper.GetPodTemplate(admissionRequest)
 	switch err {
 	case nil:
 		break
@@ -77,13 +93,25 @@ func (c *Controller) Admit(admissionRequest *admissionv1.AdmissionRequest) *admi
 		return a.Flush()
 	}
 
-	return c.admitPod(admissionRequest.Namespace, podSpecLocation, *ps)
+	return c.admitPod(ctx, admissionRequest.Namespace, podSpecLocation, pt.ObjectMeta, pt.Spec, admissionRequest)
 }
 
-func (c *Controller) admitPod(namespace, specPath string, pod corev1.PodSpec) *admissionv1.AdmissionResponse {
+func (c *Controller) admitPod(ctx context.Context, namespace, specPath string, podMeta metav1.ObjectMeta, pod corev1.PodSpec, admissionRequest *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
 	a := &webhook.AdmissionResponder{}
+	workload := policyv1.Workload{
+		Labels:             podMeta.Labels,
+		ServiceAccountName: pod.ServiceAccountName,
+	}
 	patches := []types.JSONPatch{}
 	decisions := map[string][]string{}
+	var exceptions []string
+
+	// images are verified once for the pod, concurrently, before the patches for each container type are made
+	var images []string
+	for _, container := range append(append([]corev1.Container{}, pod.InitContainers...), pod.Containers...) {
+		images = append(images, container.Image)
+	}
+	verifications := c.verifyImages(ctx, namespace, podMeta, workload, pod, images, admissionRequest)
 
 	// for each container image subtype
 	for _, containerType := range []string{"initContainers", "containers"} {
@@ -98,13 +126,17 @@ func (c *Controller) admitPod(namespace, specPath string, pod corev1.PodSpec) *a
 			return a.Flush()
 		}
 
-		newPatches, denials, err := c.getPatchesForContainers(containerType, namespace, specPath, pod, containers)
+		newPatches, denials, warnings, newExceptions, err := c.getPatchesForContainers(containerType, namespace, specPath, containers, verifications)
 		a.MapStringsToAdmissionResponse(denials)
+		for _, warning := range warnings {
+			a.AddWarning(warning)
+		}
 		if err != nil {
 			a.ToAdmissionResponse(err)
 			a.Flush()
 		}
 		patches = append(patches, newPatches...)
+		exceptions = append(exceptions, newExceptions...)
 		for key, value := range denials {
 			if _, ok := decisions[key]; !ok {
 				decisions[key] = value
@@ -114,7 +146,7 @@ func (c *Controller) admitPod(namespace, specPath string, pod corev1.PodSpec) *a
 		}
 	}
 
-	if a.HasErrors() {
+	if a.HasErrors() && !c.breakGlass(a, namespace, admissionRequest) {
 		c.PMetrics.DenyDecisionCount.Inc()
 		denyStr := "Deny for images: "
 		for key, msgs := range decisions {
@@ -126,6 +158,10 @@ func (c *Controller) admitPod(namespace, specPath string, pod corev1.PodSpec) *a
 		return a.Flush()
 	}
 
+	if len(exceptions) > 0 {
+		patches = append(patches, exceptionPatch(specPath, podMeta, exceptions))
+	}
+
 	// apply patches
 	if len(patches) > 0 {
 		jsonPatch, err := json.Marshal(patches)
@@ -146,9 +182,11 @@ func (c *Controller) admitPod(namespace, specPath string, pod corev1.PodSpec) *a
 	return a.Flush()
 }
 
-func (c *Controller) getPatchesForContainers(containerType, namespace, specPath string, pod corev1.PodSpec, containers []corev1.Container) ([]types.JSONPatch, map[string][]string, error) {
+func (c *Controller) getPatchesForContainers(containerType, namespace, specPath string, containers []corev1.Container, verifications map[string]*imageVerification) ([]types.JSONPatch, map[string][]string, []string, []string, error) {
 	patches := []types.JSONPatch{}
 	denials := map[string][]string{}
+	var warnings []string
+	var exceptions []string
 
 	// for each container of this type
 	for containerIndex, container := range containers {
@@ -157,81 +195,37 @@ func (c *Controller) getPatchesForContainers(containerType, namespace, specPath
 			continue
 		}
 
-		// parse image
-		img, err := image.NewReference(container.Image)
-		if err != nil {
-			glog.Error(err)
+		v := verifications[container.Image]
+		if v.img == nil {
 			denials["invalidimagename"] = []string{fmt.Sprintf("Deny %q, invalid image name", container.Image)}
 			continue
 		}
-		// If digest isn't available use tag
-		var key string
-		if img.GetDigest() == "" {
-			key = img.NameWithTag()
-		} else {
-			key = fmt.Sprintf("%s:%s", img.NameWithoutTag(), img.GetDigest())
-		}
-		denials[key] = []string{}
-
-		glog.Infof("Getting policy for container image: %s   namespace: %s", img.String(), namespace)
-		containerPolicy, err := c.policyClient.GetPolicyToEnforce(namespace, img.String())
-		if err != nil {
-			if _, ok := denials[key]; !ok {
-				denials[key] = []string{err.Error()}
-			} else {
-				denials[key] = append(denials[key], err.Error())
-			}
-			continue
-		}
-
-		credentialCandidates := c.getPodCredentials(namespace, img, pod)
-
-		scanResponse := c.Enforcer.VulnerabilityPolicy(img, credentialCandidates, containerPolicy)
-		if !scanResponse.CanDeploy {
-			if _, ok := denials[key]; !ok {
-				denials[key] = []string{scanResponse.DenyReason}
-			} else {
-				denials[key] = append(denials[key], scanResponse.DenyReason)
-			}
-		}
-
-		digest, deny, err := c.Enforcer.DigestByPolicy(namespace, img, credentialCandidates, containerPolicy)
-		if err != nil {
-			return patches, denials, err
+		if _, ok := denials[v.key]; !ok {
+			denials[v.key] = []string{}
 		}
-		// Update map key from image:tag to image:digest
-		if digest != nil {
-			oldkey := key
-			key = fmt.Sprintf("%s:%s", img.NameWithoutTag(), digest.String())
-			if _, ok := denials[oldkey]; !ok {
-				denials[key] = []string{}
-			} else {
-				denials[key] = denials[oldkey]
-			}
-			delete(denials, oldkey)
+		denials[v.key] = append(denials[v.key], v.denials...)
+		warnings = append(warnings, v.warnings...)
+		if v.exception != "" {
+			exceptions = append(exceptions, v.exception)
 		}
-		if deny != nil {
-			if _, ok := denials[key]; !ok {
-				denials[key] = []string{deny.Error()}
-			} else {
-				denials[key] = append(denials[key], deny.Error())
-			}
-			continue
+		if v.err != nil {
+			return patches, denials, warnings, exceptions, v.err
 		}
 
-		if digest != nil {
+		if v.digest != nil {
+			img := v.img
 			// ISSUE: https://github.com/IBM/portieris/issues/244
 			// unset -> mutate
-			if containerPolicy.MutateImage == nil || *containerPolicy.MutateImage == true {
+			if v.mutate {
 				// convert digest to patch
 				glog.Infof("Mutation #: %s %d  Image name: %s", containerType, containerIndex, img.String())
 				if strings.Contains(container.Image, img.String()) {
 					// ISSUE: https://github.com/IBM/portieris/issues/90
-					glog.Warningf("Image %s mutated to: %s@sha256:%s", img.String(), img.NameWithoutTag(), digest.String())
+					glog.Warningf("Image %s mutated to: %s@sha256:%s", img.String(), img.NameWithoutTag(), v.digest.String())
 					patch := types.JSONPatch{
 						Op:    "replace",
 						Path:  fmt.Sprintf("%s/%s/%d/image", specPath, containerType, containerIndex),
-						Value: fmt.Sprintf("%s@sha256:%s", img.NameWithoutTag(), digest.String()),
+						Value: fmt.Sprintf("%s@sha256:%s", img.NameWithoutTag(), v.digest.String()),
 					}
 					glog.Infof("Patch: %v", patch)
 					patches = append(patches, patch)
@@ -242,7 +236,36 @@ func (c *Controller) getPatchesForContainers(containerType, namespace, specPath
 		}
 	}
 
-	return patches, denials, nil
+	return patches, denials, warnings, exceptions, nil
+}
+
+// exceptionPatch annotates the pod template with the names of the exceptions that allowed its images
+func exceptionPatch(specPath string, podMeta metav1.ObjectMeta, exceptions []string) types.JSONPatch {
+	seen := map[string]bool{}
+	var names []string
+	for _, name := range exceptions {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	value := strings.Join(names, ",")
+
+	annotationsPath := strings.TrimSuffix(specPath, "/spec") + "/metadata/annotations"
+	if podMeta.Annotations == nil {
+		return types.JSONPatch{
+			Op:    "add",
+			Path:  annotationsPath,
+			Value: map[string]string{policyv1.ExceptionAnnotation: value},
+		}
+	}
+	escaped := strings.NewReplacer("~", "~0", "/", "~1").Replace(policyv1.ExceptionAnnotation)
+	return types.JSONPatch{
+		Op:    "add",
+		Path:  annotationsPath + "/" + escaped,
+		Value: value,
+	}
 }
 
 func (c *Controller) getPodCredentials(namespace string, img *image.Reference, pod corev1.PodSpec) credential.Credentials {
@@ -1,4 +1,4 @@
-// Copyright 2020, 2021 Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -16,13 +16,18 @@ package multi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/IBM/portieris/helpers/credential"
 	"github.com/IBM/portieris/helpers/image"
 	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/breakglass"
 	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/IBM/portieris/pkg/verifier/imageconfig"
 	"github.com/IBM/portieris/pkg/verifier/simple"
 	notaryverifier "github.com/IBM/portieris/pkg/verifier/trust"
 	"github.com/IBM/portieris/pkg/verifier/vulnerability"
@@ -32,6 +37,7 @@ import (
 	"github.com/stretchr/testify/require"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -39,9 +45,14 @@ type mockPolicyClient struct {
 	mock.Mock
 }
 
-func (mpc *mockPolicyClient) GetPolicyToEnforce(namespace, image string) (*policyv1.Policy, error) {
+func (mpc *mockPolicyClient) GetPolicyToEnforce(namespace, image string, workload policyv1.Workload) (*policyv1.PolicyMatch, error) {
+	args := mpc.Called(namespace, image, workload)
+	return args.Get(0).(*policyv1.PolicyMatch), args.Error(1)
+}
+
+func (mpc *mockPolicyClient) GetImagePolicyException(namespace, image string) (*policyv1.ImagePolicyException, error) {
 	args := mpc.Called(namespace, image)
-	return args.Get(0).(*policyv1.Policy), args.Error(1)
+	return args.Get(0).(*policyv1.ImagePolicyException), args.Error(1)
 }
 
 type mockKubeWrapper struct {
@@ -54,6 +65,26 @@ func (mkw *mockKubeWrapper) GetPodSpec(req *admissionv1.AdmissionRequest) (strin
 	return args.String(0), args.Get(1).(*corev1.PodSpec), args.Error(2)
 }
 
+func (mkw *mockKubeWrapper) GetPodTemplate(req *admissionv1.AdmissionRequest) (string, *corev1.PodTemplateSpec, error) {
+	args := mkw.Called(req)
+	return args.String(0), args.Get(1).(*corev1.PodTemplateSpec), args.Error(2)
+}
+
+func (mkw *mockKubeWrapper) GetSecret(namespace, name string) (*corev1.Secret, error) {
+	args := mkw.Called(namespace, name)
+	return args.Get(0).(*corev1.Secret), args.Error(1)
+}
+
+func (mkw *mockKubeWrapper) GetServiceAccount(namespace, name string) (*corev1.ServiceAccount, error) {
+	args := mkw.Called(namespace, name)
+	return args.Get(0).(*corev1.ServiceAccount), args.Error(1)
+}
+
+func (mkw *mockKubeWrapper) AddServiceAccountPullSecrets(namespace string, ps *corev1.PodSpec) error {
+	args := mkw.Called(namespace, ps)
+	return args.Error(0)
+}
+
 func (mkw *mockKubeWrapper) GetSecretToken(namespace, secretName, registry string) (string, string, error) {
 	args := mkw.Called(namespace, secretName, registry)
 	return args.String(0), args.String(1), args.Error(2)
@@ -69,17 +100,37 @@ func (mkw *mockKubeWrapper) GetBasicCredentials(namespace, secretName string) (s
 	return args.String(0), args.String(1), args.Error(2)
 }
 
+func (mkw *mockKubeWrapper) GetNodePlatforms(ctx context.Context) ([]string, error) {
+	args := mkw.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (mkw *mockKubeWrapper) GetNamespaceLabels(namespace string) (map[string]string, error) {
+	args := mkw.Called(namespace)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+func (mkw *mockKubeWrapper) GetConfigMapData(namespace, name, key string) (string, error) {
+	args := mkw.Called(namespace, name, key)
+	return args.String(0), args.Error(1)
+}
+
+func (mkw *mockKubeWrapper) CreateEvent(event *corev1.Event) error {
+	args := mkw.Called(event)
+	return args.Error(0)
+}
+
 type mockEnforcer struct {
 	mock.Mock
 }
 
-func (me *mockEnforcer) DigestByPolicy(namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
-	args := me.Called(namespace, img, credentials, policy)
+func (me *mockEnforcer) DigestByPolicy(ctx context.Context, namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
+	args := me.Called(ctx, namespace, img, credentials, policy)
 	return args.Get(0).(*bytes.Buffer), args.Error(1), args.Error(2)
 }
 
-func (me *mockEnforcer) VulnerabilityPolicy(img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) vulnerability.ScanResponse {
-	args := me.Called(img, credentials, policy)
+func (me *mockEnforcer) VulnerabilityPolicy(ctx context.Context, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) vulnerability.ScanResponse {
+	args := me.Called(ctx, img, credentials, policy)
 	return args.Get(0).(vulnerability.ScanResponse)
 }
 
@@ -87,12 +138,13 @@ func TestNewController(t *testing.T) {
 	wantKubeWrapper := &mockKubeWrapper{}
 	wantPolicyClient := &mockPolicyClient{}
 	wantNV := &notaryverifier.Verifier{}
-	wantScannerFactory := vulnerability.NewScannerFactory()
+	wantScannerFactory := vulnerability.NewScannerFactory(nil)
 	wantEnforcer := &enforcer{
 		kubeClientsetWrapper: wantKubeWrapper,
 		nv:                   wantNV,
 		scannerFactory:       &wantScannerFactory,
-		sv:                   simple.NewVerifier(),
+		sv:                   simple.NewVerifier(nil),
+		cv:                   imageconfig.NewVerifier(nil),
 	}
 	wantMetrics := metrics.NewMetrics()
 	defer wantMetrics.UnregisterAll()
@@ -100,19 +152,31 @@ func TestNewController(t *testing.T) {
 	wantController := Controller{
 		kubeClientsetWrapper: wantKubeWrapper,
 		policyClient:         wantPolicyClient,
-		Enforcer:             wantEnforcer,
+		Enforcer:             &coalescingEnforcer{Enforcer: wantEnforcer, pm: wantMetrics},
 		PMetrics:             wantMetrics,
+		breakGlassConfig:     breakglass.Config{Namespaces: []string{"default"}},
 	}
 
-	gotController := NewController(wantKubeWrapper, wantPolicyClient, wantNV, wantMetrics)
+	gotController := NewController(wantKubeWrapper, wantPolicyClient, wantNV, wantMetrics, breakglass.Config{Namespaces: []string{"default"}}, VerificationConfig{})
 
 	assert.Equal(t, wantController, *gotController)
+
+	cache := NewVerificationCache(time.Minute, time.Second, wantMetrics)
+	gotController = NewController(wantKubeWrapper, wantPolicyClient, wantNV, wantMetrics, breakglass.Config{}, VerificationConfig{Cache: cache})
+	assert.Equal(t, &coalescingEnforcer{Enforcer: &cachingEnforcer{Enforcer: wantEnforcer, cache: cache, kubeWrapper: wantKubeWrapper}, pm: wantMetrics}, gotController.Enforcer)
+
+	scannerClient := &http.Client{}
+	gotController = NewController(wantKubeWrapper, wantPolicyClient, wantNV, wantMetrics, breakglass.Config{}, VerificationConfig{ScannerClient: scannerClient})
+	wantScannerFactory = vulnerability.NewScannerFactory(scannerClient)
+	assert.Equal(t, &wantScannerFactory, gotController.Enforcer.(*coalescingEnforcer).Enforcer.(*enforcer).scannerFactory)
 }
 
 func TestController_getPatchesForContainers(t *testing.T) {
 	type getPolicyToEnforceMock struct {
-		outPolicy *policyv1.Policy
-		outErr    error
+		outPolicy    *policyv1.Policy
+		outConflicts []string
+		outRego      *policyv1.Rego
+		outErr       error
 	}
 	type enforcerVulnerabilityPolicyMock struct {
 		outScanResponse vulnerability.ScanResponse
@@ -123,6 +187,7 @@ func TestController_getPatchesForContainers(t *testing.T) {
 		outErr    error
 	}
 	type mocks struct {
+		exception                   *policyv1.ImagePolicyException
 		getPolicyToEnforce          *getPolicyToEnforceMock
 		inImage                     string
 		credentials                 credential.Credentials
@@ -134,11 +199,16 @@ func TestController_getPatchesForContainers(t *testing.T) {
 		containerType    string
 		namespace        string
 		specPath         string
+		podMeta          metav1.ObjectMeta
+		namespaceLabels  map[string]string
+		workload         policyv1.Workload
 		imagePullSecrets []corev1.LocalObjectReference
 		containers       []corev1.Container
 		mocks            []mocks
 		wantPatches      []types.JSONPatch
 		wantDenials      map[string][]string
+		wantWarnings     []string
+		wantExceptions   []string
 		wantErr          error
 	}{
 		{
@@ -189,6 +259,30 @@ func TestController_getPatchesForContainers(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name:      "Workload is used to get policy",
+			namespace: "some-namespace",
+			workload: policyv1.Workload{
+				Labels:             map[string]string{"exposure": "internet"},
+				ServiceAccountName: "web",
+			},
+			containers: []corev1.Container{
+				{Image: "icr.io/some-namespace/image:tag"},
+			},
+			mocks: []mocks{
+				{
+					inImage: "icr.io/some-namespace/image:tag",
+					getPolicyToEnforce: &getPolicyToEnforceMock{
+						outErr: fmt.Errorf("not for internet facing workloads"),
+					},
+				},
+			},
+			wantPatches: []types.JSONPatch{},
+			wantDenials: map[string][]string{
+				"icr.io/some-namespace/image:tag": {"not for internet facing workloads"},
+			},
+			wantErr: nil,
+		},
 		{
 			name:      "Fail to get policy, deny, multiple containers",
 			namespace: "some-namespace",
@@ -256,6 +350,34 @@ func TestController_getPatchesForContainers(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name:      "Conflicting policies are reported as warnings",
+			namespace: "some-namespace",
+			containers: []corev1.Container{
+				{Image: "icr.io/some-namespace/image:tag"},
+			},
+			mocks: []mocks{
+				{
+					inImage: "icr.io/some-namespace/image:tag",
+					getPolicyToEnforce: &getPolicyToEnforceMock{
+						outPolicy:    &policyv1.Policy{},
+						outConflicts: []string{`some-namespace/other-policy repository "icr.io/*"`},
+					},
+					enforcerVulnerabilityPolicy: &enforcerVulnerabilityPolicyMock{
+						outScanResponse: vulnerability.ScanResponse{
+							CanDeploy: true,
+						},
+					},
+					enforceDigestByPolicy: &enforceDigestByPolicyMock{},
+				},
+			},
+			wantPatches: []types.JSONPatch{},
+			wantDenials: map[string][]string{
+				"icr.io/some-namespace/image:tag": {},
+			},
+			wantWarnings: []string{`image "icr.io/some-namespace/image:tag": policy from some-namespace/some-policy repository "icr.io/*" was used, some-namespace/other-policy repository "icr.io/*" matches equally well with a different policy`},
+			wantErr:      nil,
+		},
 		{
 			name:      "digest by policy errors",
 			namespace: "some-namespace",
@@ -405,6 +527,123 @@ func TestController_getPatchesForContainers(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name:            "allowed by signing and conditions",
+			namespace:       "some-namespace",
+			podMeta:         metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+			namespaceLabels: map[string]string{"env": "prod"},
+			containers: []corev1.Container{
+				{Image: "icr.io/some-namespace/image:tag"},
+			},
+			mocks: []mocks{
+				{
+					inImage: "icr.io/some-namespace/image:tag",
+					getPolicyToEnforce: &getPolicyToEnforceMock{
+						outPolicy: &policyv1.Policy{Conditions: []policyv1.Condition{
+							{Expression: "namespaceObject.labels.env == 'prod' && pod.labels.app == 'web' && digest == 'sha256:somedigest'"},
+						}},
+					},
+					enforcerVulnerabilityPolicy: &enforcerVulnerabilityPolicyMock{
+						outScanResponse: vulnerability.ScanResponse{
+							CanDeploy: true,
+						},
+					},
+					enforceDigestByPolicy: &enforceDigestByPolicyMock{
+						outDigest: "somedigest",
+					},
+				},
+			},
+			wantDenials: map[string][]string{
+				"icr.io/some-namespace/image:somedigest": {},
+			},
+			wantErr: nil,
+		},
+		{
+			name:            "denied by conditions",
+			namespace:       "some-namespace",
+			namespaceLabels: map[string]string{"env": "prod"},
+			containers: []corev1.Container{
+				{Image: "icr.io/some-namespace/image:tag"},
+			},
+			mocks: []mocks{
+				{
+					inImage: "icr.io/some-namespace/image:tag",
+					getPolicyToEnforce: &getPolicyToEnforceMock{
+						outPolicy: &policyv1.Policy{Conditions: []policyv1.Condition{
+							{Expression: "namespaceObject.labels.env != 'prod' || results.trust", Message: "prod namespaces require trust"},
+						}},
+					},
+					enforcerVulnerabilityPolicy: &enforcerVulnerabilityPolicyMock{
+						outScanResponse: vulnerability.ScanResponse{
+							CanDeploy: true,
+						},
+					},
+					enforceDigestByPolicy: &enforceDigestByPolicyMock{},
+				},
+			},
+			wantPatches: []types.JSONPatch{},
+			wantDenials: map[string][]string{
+				"icr.io/some-namespace/image:tag": {"conditions: policy denied the request: prod namespaces require trust"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:      "allowed by an exception without enforcing the policy",
+			namespace: "some-namespace",
+			containers: []corev1.Container{
+				{Image: "icr.io/some-namespace/image:tag"},
+			},
+			mocks: []mocks{
+				{
+					inImage: "icr.io/some-namespace/image:tag",
+					exception: &policyv1.ImagePolicyException{
+						ObjectMeta: metav1.ObjectMeta{Name: "incident-1234", Namespace: "some-namespace"},
+						Spec: policyv1.ImagePolicyExceptionSpec{
+							Image:     "icr.io/some-namespace/*",
+							ExpiresAt: metav1.NewTime(time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)),
+							Reason:    "INC-1234",
+							Approver:  "oncall@example.com",
+						},
+					},
+				},
+			},
+			wantPatches: []types.JSONPatch{},
+			wantDenials: map[string][]string{
+				"icr.io/some-namespace/image:tag": {},
+			},
+			wantWarnings:   []string{`image "icr.io/some-namespace/image:tag": allowed without a policy by ImagePolicyException "incident-1234" until 2026-06-01T12:00:00Z`},
+			wantExceptions: []string{"incident-1234"},
+			wantErr:        nil,
+		},
+		{
+			name:            "denied by Rego",
+			namespace:       "some-namespace",
+			namespaceLabels: map[string]string{},
+			containers: []corev1.Container{
+				{Image: "icr.io/some-namespace/image:tag"},
+			},
+			mocks: []mocks{
+				{
+					inImage: "icr.io/some-namespace/image:tag",
+					getPolicyToEnforce: &getPolicyToEnforceMock{
+						outPolicy: &policyv1.Policy{},
+						outRego: &policyv1.Rego{Module: `package portieris
+decision := {"allow": false, "messages": [sprintf("not in %s", [input.request.namespace])]}`},
+					},
+					enforcerVulnerabilityPolicy: &enforcerVulnerabilityPolicyMock{
+						outScanResponse: vulnerability.ScanResponse{
+							CanDeploy: true,
+						},
+					},
+					enforceDigestByPolicy: &enforceDigestByPolicyMock{},
+				},
+			},
+			wantPatches: []types.JSONPatch{},
+			wantDenials: map[string][]string{
+				"icr.io/some-namespace/image:tag": {"rego: policy denied the request: not in some-namespace"},
+			},
+			wantErr: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -432,12 +671,26 @@ func TestController_getPatchesForContainers(t *testing.T) {
 					img, err = image.NewReference(imageName)
 					require.NoError(t, err)
 				}
-				policy := m.getPolicyToEnforce.outPolicy
 				namespace := tt.namespace
+				if img != nil {
+					policyClient.On("GetImagePolicyException", namespace, img.String()).Return(m.exception, nil).Once()
+				}
+				var policy *policyv1.Policy
 				if m.getPolicyToEnforce != nil {
+					policy = m.getPolicyToEnforce.outPolicy
+					var match *policyv1.PolicyMatch
 					err := m.getPolicyToEnforce.outErr
+					if err == nil {
+						match = &policyv1.PolicyMatch{
+							Policy:     policy,
+							Source:     "some-namespace/some-policy",
+							Repository: "icr.io/*",
+							Conflicts:  m.getPolicyToEnforce.outConflicts,
+							Rego:       m.getPolicyToEnforce.outRego,
+						}
+					}
 					policyClient.
-						On("GetPolicyToEnforce", namespace, img.String()).Return(policy, err).Once()
+						On("GetPolicyToEnforce", namespace, img.String(), tt.workload).Return(match, err).Once()
 				}
 
 				creds := m.credentials
@@ -449,7 +702,7 @@ func TestController_getPatchesForContainers(t *testing.T) {
 
 				if m.enforcerVulnerabilityPolicy != nil {
 					response := m.enforcerVulnerabilityPolicy.outScanResponse
-					enforcer.On("VulnerabilityPolicy", img, creds, policy).Return(response).Once()
+					enforcer.On("VulnerabilityPolicy", mock.Anything, img, creds, policy).Return(response).Once()
 				}
 
 				if m.enforceDigestByPolicy != nil {
@@ -466,10 +719,14 @@ func TestController_getPatchesForContainers(t *testing.T) {
 					}
 					deny := m.enforceDigestByPolicy.outDeny
 					err := m.enforceDigestByPolicy.outErr
-					enforcer.On("DigestByPolicy", namespace, img, creds, policy).Return(digest, deny, err).Once()
+					enforcer.On("DigestByPolicy", mock.Anything, namespace, img, creds, policy).Return(digest, deny, err).Once()
 				}
 			}
 
+			if tt.namespaceLabels != nil {
+				kubeWrapper.On("GetNamespaceLabels", tt.namespace).Return(tt.namespaceLabels, nil).Once()
+			}
+
 			c := &Controller{
 				policyClient:         &policyClient,
 				Enforcer:             &enforcer,
@@ -478,11 +735,55 @@ func TestController_getPatchesForContainers(t *testing.T) {
 			}
 			defer c.PMetrics.UnregisterAll()
 
-			gotPatches, gotDenials, gotErr := c.getPatchesForContainers(tt.containerType, tt.namespace, tt.specPath, podSpec, tt.containers)
+			var images []string
+			for _, container := range tt.containers {
+				images = append(images, container.Image)
+			}
+			verifications := c.verifyImages(context.Background(), tt.namespace, tt.podMeta, tt.workload, podSpec, images, &admissionv1.AdmissionRequest{Namespace: tt.namespace})
+			gotPatches, gotDenials, gotWarnings, gotExceptions, gotErr := c.getPatchesForContainers(tt.containerType, tt.namespace, tt.specPath, tt.containers, verifications)
 
 			assert.Equal(t, tt.wantPatches, gotPatches)
 			assert.Equal(t, tt.wantDenials, gotDenials)
+			assert.Equal(t, tt.wantWarnings, gotWarnings)
+			assert.Equal(t, tt.wantExceptions, gotExceptions)
 			assert.Equal(t, tt.wantErr, gotErr)
 		})
 	}
 }
+
+func TestExceptionPatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		specPath   string
+		podMeta    metav1.ObjectMeta
+		exceptions []string
+		wantPatch  types.JSONPatch
+	}{
+		{
+			name:       "pod without annotations",
+			specPath:   "/spec",
+			exceptions: []string{"b", "a", "b"},
+			wantPatch: types.JSONPatch{
+				Op:    "add",
+				Path:  "/metadata/annotations",
+				Value: map[string]string{"portieris.cloud.ibm.com/image-policy-exceptions": "a,b"},
+			},
+		},
+		{
+			name:       "deployment template with annotations",
+			specPath:   "/spec/template/spec",
+			podMeta:    metav1.ObjectMeta{Annotations: map[string]string{"team": "a"}},
+			exceptions: []string{"incident-1234"},
+			wantPatch: types.JSONPatch{
+				Op:    "add",
+				Path:  "/spec/template/metadata/annotations/portieris.cloud.ibm.com~1image-policy-exceptions",
+				Value: "incident-1234",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPatch, exceptionPatch(tt.specPath, tt.podMeta, tt.exceptions))
+		})
+	}
+}
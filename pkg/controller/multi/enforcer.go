@@ -1,4 +1,4 @@
-// Copyright 2020, 2021 Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -16,13 +16,18 @@ package multi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/IBM/portieris/helpers/credential"
 	"github.com/IBM/portieris/helpers/image"
 	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
 	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/transport"
+	"github.com/IBM/portieris/pkg/verifier/imageconfig"
 	"github.com/IBM/portieris/pkg/verifier/simple"
+	"github.com/IBM/portieris/pkg/verifier/tags"
 	notaryverifier "github.com/IBM/portieris/pkg/verifier/trust"
 	"github.com/IBM/portieris/pkg/verifier/vulnerability"
 	"github.com/golang/glog"
@@ -30,8 +35,8 @@ import (
 
 // Enforcer is an interface that enforces pod admission based on a configured policy
 type Enforcer interface {
-	DigestByPolicy(string, *image.Reference, credential.Credentials, *policyv1.Policy) (*bytes.Buffer, error, error)
-	VulnerabilityPolicy(*image.Reference, credential.Credentials, *policyv1.Policy) vulnerability.ScanResponse
+	DigestByPolicy(context.Context, string, *image.Reference, credential.Credentials, *policyv1.Policy) (*bytes.Buffer, error, error)
+	VulnerabilityPolicy(context.Context, *image.Reference, credential.Credentials, *policyv1.Policy) vulnerability.ScanResponse
 }
 
 type enforcer struct {
@@ -41,36 +46,56 @@ type enforcer struct {
 	nv notaryverifier.Interface
 	// simple signing verifier
 	sv simple.Verifier
+	// image manifest and configuration verifier
+	cv imageconfig.Verifier
 	// scannerFactory creates new vulnerabilities scanners according to the policy
 	scannerFactory vulnerability.ScannerFactory
 }
 
-// NewEnforcer returns an enforce that wraps the kubenetes interface and a notary verifier
-func NewEnforcer(kubeClientsetWrapper kubernetes.WrapperInterface, nv *notaryverifier.Verifier) Enforcer {
-	scannerFactory := vulnerability.NewScannerFactory()
+// NewEnforcer returns an enforce that wraps the kubenetes interface and a notary verifier, vulnerability scanners
+// send requests with scannerClient, and registry requests of the simple and image configuration verifiers use the
+// proxy and trusted CAs of registryTransport, which may be nil
+func NewEnforcer(kubeClientsetWrapper kubernetes.WrapperInterface, nv *notaryverifier.Verifier, scannerClient *http.Client, registryTransport *transport.Transport) Enforcer {
+	scannerFactory := vulnerability.NewScannerFactory(scannerClient)
 	return &enforcer{
 		kubeClientsetWrapper: kubeClientsetWrapper,
 		nv:                   nv,
-		sv:                   simple.NewVerifier(),
+		sv:                   simple.NewVerifier(registryTransport),
+		cv:                   imageconfig.NewVerifier(registryTransport),
 		scannerFactory:       &scannerFactory,
 	}
 }
 
-func (e enforcer) DigestByPolicy(namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
+func (e enforcer) DigestByPolicy(ctx context.Context, namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
 	// no policy indicates admission should be allowed, without mutation
 	if policy == nil {
 		return nil, nil, nil
 	}
 
+	if deny := tags.VerifyByPolicy(img, policy.Tags); deny != nil {
+		return nil, fmt.Errorf("tags: policy denied the request: %v", deny), nil
+	}
+
 	var digest *bytes.Buffer
 	var deny, err error
+	var platforms []string
 	if len(policy.Simple.Requirements) > 0 {
 		glog.Infof("policy.Simple %v", policy.Simple)
 		simplePolicy, err := e.sv.TransformPolicies(e.kubeClientsetWrapper, namespace, policy.Simple.Requirements)
 		if err != nil {
 			return nil, nil, err
 		}
-		storeUser, storePassword, err := e.kubeClientsetWrapper.GetBasicCredentials(namespace, policy.Simple.StoreSecret)
+		if policy.Simple.MultiArch.Verify == policyv1.MultiArchVerifyNodes {
+			platforms, err = e.kubeClientsetWrapper.GetNodePlatforms(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		storeNamespace := namespace
+		if policy.Simple.StoreSecretNamespace != "" {
+			storeNamespace = policy.Simple.StoreSecretNamespace
+		}
+		storeUser, storePassword, err := e.kubeClientsetWrapper.GetBasicCredentials(storeNamespace, policy.Simple.StoreSecret)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -78,7 +103,7 @@ func (e enforcer) DigestByPolicy(namespace string, img *image.Reference, credent
 		if err != nil {
 			return nil, nil, err
 		}
-		digest, deny, err = e.sv.VerifyByPolicy(img.String(), credentials, storeConfigDir, simplePolicy)
+		digest, deny, err = e.sv.VerifyByPolicy(ctx, img.String(), credentials, storeConfigDir, simplePolicy, policy.Simple.MultiArch, platforms)
 		if err != nil {
 			return nil, nil, fmt.Errorf("simple: %v", err)
 		}
@@ -94,7 +119,7 @@ func (e enforcer) DigestByPolicy(namespace string, img *image.Reference, credent
 	if policy.Trust.Enabled != nil && *policy.Trust.Enabled {
 		glog.Infof("policy.Trust %v", policy.Trust)
 		var notaryDigest *bytes.Buffer
-		notaryDigest, deny, err = e.nv.VerifyByPolicy(namespace, img, credentials, policy)
+		notaryDigest, deny, err = e.nv.VerifyByPolicy(ctx, namespace, img, credentials, policy)
 		if err != nil {
 			return nil, nil, fmt.Errorf("trust: %v", err)
 		}
@@ -110,10 +135,32 @@ func (e enforcer) DigestByPolicy(namespace string, img *image.Reference, credent
 		}
 	}
 
+	if imageconfig.Enabled(policy) {
+		glog.Infof("policy.Config %v", policy.Config)
+		if platforms == nil && imageconfig.InspectsInstances(policy) {
+			platforms, err = e.kubeClientsetWrapper.GetNodePlatforms(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		configDigest, deny, err := e.cv.VerifyByPolicy(ctx, img.String(), credentials, policy, platforms)
+		if err != nil {
+			return nil, nil, fmt.Errorf("config: %v", err)
+		}
+		if deny != nil {
+			return nil, fmt.Errorf("config: policy denied the request: %v", deny), nil
+		}
+		if digest == nil {
+			digest = configDigest
+		} else if configDigest.String() != digest.String() {
+			return nil, fmt.Errorf("Inspected image digest %v conflicts with verified digest %v", configDigest, digest), nil
+		}
+	}
+
 	return digest, nil, nil
 }
 
-func (e *enforcer) VulnerabilityPolicy(img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) vulnerability.ScanResponse {
+func (e *enforcer) VulnerabilityPolicy(ctx context.Context, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) vulnerability.ScanResponse {
 	if policy == nil {
 		glog.Warningf("vulnerability: No policy for image %q so allow", img.String())
 		return vulnerability.ScanResponse{CanDeploy: true}
@@ -123,7 +170,7 @@ func (e *enforcer) VulnerabilityPolicy(img *image.Reference, credentials credent
 	// Loop round all scanners and check if the image can be deployed
 	// If any scanner returns either an error, or a CanDeploy=false, the pod will not be admitted
 	for _, scanner := range scanners {
-		response, err := scanner.CanImageDeployBasedOnVulnerabilities(*img)
+		response, err := scanner.CanImageDeployBasedOnVulnerabilities(ctx, *img)
 		if err != nil {
 			return vulnerability.ScanResponse{CanDeploy: false, DenyReason: err.Error()}
 		}
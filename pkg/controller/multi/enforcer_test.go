@@ -15,6 +15,7 @@ package multi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 
@@ -42,8 +43,8 @@ type mockScanner struct {
 	mock.Mock
 }
 
-func (ms *mockScanner) CanImageDeployBasedOnVulnerabilities(img image.Reference) (vulnerability.ScanResponse, error) {
-	args := ms.Called(img)
+func (ms *mockScanner) CanImageDeployBasedOnVulnerabilities(ctx context.Context, img image.Reference) (vulnerability.ScanResponse, error) {
+	args := ms.Called(ctx, img)
 	return args.Get(0).(vulnerability.ScanResponse), args.Error(1)
 }
 
@@ -51,8 +52,8 @@ type mockNotaryVerifier struct {
 	mock.Mock
 }
 
-func (mnv *mockNotaryVerifier) VerifyByPolicy(namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
-	args := mnv.Called(namespace, img, credentials, policy)
+func (mnv *mockNotaryVerifier) VerifyByPolicy(ctx context.Context, namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
+	args := mnv.Called(ctx, namespace, img, credentials, policy)
 	return args.Get(0).(*bytes.Buffer), args.Error(1), args.Error(2)
 }
 
@@ -75,8 +76,17 @@ func (msv *mockSimpleVerifier) RemoveRegistryDir(dirName string) error {
 	return args.Error(0)
 }
 
-func (msv *mockSimpleVerifier) VerifyByPolicy(imageToVerify string, credentials credential.Credentials, registriesConfigDir string, simplePolicy *signature.Policy) (*bytes.Buffer, error, error) {
-	args := msv.Called(imageToVerify, credentials, registriesConfigDir, simplePolicy)
+func (msv *mockSimpleVerifier) VerifyByPolicy(ctx context.Context, imageToVerify string, credentials credential.Credentials, registriesConfigDir string, simplePolicy *signature.Policy, multiArch policyv1.MultiArch, platforms []string) (*bytes.Buffer, error, error) {
+	args := msv.Called(ctx, imageToVerify, credentials, registriesConfigDir, simplePolicy, multiArch, platforms)
+	return args.Get(0).(*bytes.Buffer), args.Error(1), args.Error(2)
+}
+
+type mockImageConfigVerifier struct {
+	mock.Mock
+}
+
+func (mcv *mockImageConfigVerifier) VerifyByPolicy(ctx context.Context, imageToVerify string, credentials credential.Credentials, policy *policyv1.Policy, platforms []string) (*bytes.Buffer, error, error) {
+	args := mcv.Called(ctx, imageToVerify, credentials, policy, platforms)
 	return args.Get(0).(*bytes.Buffer), args.Error(1), args.Error(2)
 }
 
@@ -85,6 +95,10 @@ func Test_enforcer_DigestByPolicy(t *testing.T) {
 		policy *signature.Policy
 		err    error
 	}
+	type getNodePlatformsMock struct {
+		platforms []string
+		err       error
+	}
 	type getBasicCredentialsMock struct {
 		storeUser     string
 		storePassword string
@@ -102,6 +116,12 @@ func Test_enforcer_DigestByPolicy(t *testing.T) {
 	type removeRegistryDirMock struct {
 		err error
 	}
+	type configVerifyByPolicyMock struct {
+		digest string
+		deny   error
+		err    error
+	}
+	maxLayers := int32(10)
 	tests := []struct {
 		name                 string
 		namespace            string
@@ -109,10 +129,12 @@ func Test_enforcer_DigestByPolicy(t *testing.T) {
 		credentials          credential.Credentials
 		policy               *policyv1.Policy
 		transformPolicies    *transformPoliciesMock
+		getNodePlatforms     *getNodePlatformsMock
 		getBasicCredentials  *getBasicCredentialsMock
 		createRegistryDir    *createRegistryDirMock
 		simpleVerifyByPolicy *simpleVerifyByPolicyMock
 		removeRegistryDir    *removeRegistryDirMock
+		configVerifyByPolicy *configVerifyByPolicyMock
 		wantDigest           string
 		wantDeny             error
 		wantErr              error
@@ -126,6 +148,27 @@ func Test_enforcer_DigestByPolicy(t *testing.T) {
 			wantDeny:   nil,
 			wantErr:    nil,
 		},
+		{
+			name:      "If the tags policy denies, deny before any verification",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:latest",
+			policy: &policyv1.Policy{
+				Tags: policyv1.Tags{
+					Deny: []string{"latest"},
+				},
+				Simple: policyv1.Simple{
+					Requirements: []policyv1.SimpleRequirement{
+						{
+							Type:      "test",
+							KeySecret: "noOneCares",
+						},
+					},
+				},
+			},
+			wantDigest: "",
+			wantDeny:   fmt.Errorf(`tags: policy denied the request: tag "latest" of image "icr.io/wibble/some:latest" is denied by "latest"`),
+			wantErr:    nil,
+		},
 		{
 			name:      "If TransformPolicies errors, return error",
 			namespace: "wibble",
@@ -173,6 +216,31 @@ func Test_enforcer_DigestByPolicy(t *testing.T) {
 			wantDeny:   nil,
 			wantErr:    fmt.Errorf("also broken"),
 		},
+		{
+			name:      "GetBasicCredentials reads the store secret from its namespace",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:tag",
+			policy: &policyv1.Policy{
+				Simple: policyv1.Simple{
+					Requirements: []policyv1.SimpleRequirement{
+						{
+							Type:      "test",
+							KeySecret: "noOneCares",
+						},
+					},
+					StoreURL:             "some.url.com",
+					StoreSecret:          "someSecret1234",
+					StoreSecretNamespace: "secrets",
+				},
+			},
+			transformPolicies: &transformPoliciesMock{},
+			getBasicCredentials: &getBasicCredentialsMock{
+				err: fmt.Errorf("also broken"),
+			},
+			wantDigest: "",
+			wantDeny:   nil,
+			wantErr:    fmt.Errorf("also broken"),
+		},
 		{
 			name:      "If CreateRegistryDir errors, return error",
 			namespace: "wibble",
@@ -330,6 +398,169 @@ func Test_enforcer_DigestByPolicy(t *testing.T) {
 			wantDeny:   nil,
 			wantErr:    nil,
 		},
+		{
+			name:      "Verify the platforms of the cluster nodes if the multiArch policy is nodes",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:tag",
+			policy: &policyv1.Policy{
+				Simple: policyv1.Simple{
+					Requirements: []policyv1.SimpleRequirement{
+						{
+							Type:      "test",
+							KeySecret: "noOneCares",
+						},
+					},
+					MultiArch: policyv1.MultiArch{
+						Verify: policyv1.MultiArchVerifyNodes,
+					},
+				},
+			},
+			transformPolicies: &transformPoliciesMock{},
+			getNodePlatforms: &getNodePlatformsMock{
+				platforms: []string{"linux/amd64", "linux/s390x"},
+			},
+			getBasicCredentials: &getBasicCredentialsMock{},
+			createRegistryDir:   &createRegistryDirMock{},
+			simpleVerifyByPolicy: &simpleVerifyByPolicyMock{
+				digest: "sha256@sdfghjkj",
+			},
+			removeRegistryDir: &removeRegistryDirMock{},
+			wantDigest:        "sha256@sdfghjkj",
+		},
+		{
+			name:      "If the cluster nodes can not be listed, return the error",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:tag",
+			policy: &policyv1.Policy{
+				Simple: policyv1.Simple{
+					Requirements: []policyv1.SimpleRequirement{
+						{
+							Type:      "test",
+							KeySecret: "noOneCares",
+						},
+					},
+					MultiArch: policyv1.MultiArch{
+						Verify: policyv1.MultiArchVerifyNodes,
+					},
+				},
+			},
+			transformPolicies: &transformPoliciesMock{},
+			getNodePlatforms: &getNodePlatformsMock{
+				err: fmt.Errorf("forbidden"),
+			},
+			wantErr: fmt.Errorf("forbidden"),
+		},
+		{
+			name:      "If the config policy denies, deny access",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:tag",
+			policy: &policyv1.Policy{
+				Config: policyv1.Config{
+					MaxLayers: &maxLayers,
+				},
+			},
+			getNodePlatforms: &getNodePlatformsMock{
+				platforms: []string{"linux/amd64", "linux/arm64"},
+			},
+			configVerifyByPolicy: &configVerifyByPolicyMock{
+				deny: fmt.Errorf("image has 12 layers, maximum is 10"),
+			},
+			wantDeny: fmt.Errorf("config: policy denied the request: image has 12 layers, maximum is 10"),
+		},
+		{
+			name:      "If the config policy errors, return the error",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:tag",
+			policy: &policyv1.Policy{
+				Config: policyv1.Config{
+					MaxLayers: &maxLayers,
+				},
+			},
+			getNodePlatforms: &getNodePlatformsMock{
+				platforms: []string{"linux/amd64", "linux/arm64"},
+			},
+			configVerifyByPolicy: &configVerifyByPolicyMock{
+				err: fmt.Errorf("registry unavailable"),
+			},
+			wantErr: fmt.Errorf("config: registry unavailable"),
+		},
+		{
+			name:      "Allow access with the inspected digest if the config policy allows",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:tag",
+			policy: &policyv1.Policy{
+				Config: policyv1.Config{
+					MaxLayers: &maxLayers,
+				},
+			},
+			getNodePlatforms: &getNodePlatformsMock{
+				platforms: []string{"linux/amd64", "linux/arm64"},
+			},
+			configVerifyByPolicy: &configVerifyByPolicyMock{
+				digest: "inspected",
+			},
+			wantDigest: "inspected",
+		},
+		{
+			name:      "If the inspected digest differs from the signed digest, deny access",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:tag",
+			policy: &policyv1.Policy{
+				Simple: policyv1.Simple{
+					Requirements: []policyv1.SimpleRequirement{
+						{
+							Type:      "test",
+							KeySecret: "noOneCares",
+						},
+					},
+				},
+				Config: policyv1.Config{
+					MaxLayers: &maxLayers,
+				},
+			},
+			transformPolicies:   &transformPoliciesMock{},
+			getBasicCredentials: &getBasicCredentialsMock{},
+			createRegistryDir:   &createRegistryDirMock{},
+			simpleVerifyByPolicy: &simpleVerifyByPolicyMock{
+				digest: "signed",
+			},
+			removeRegistryDir: &removeRegistryDirMock{},
+			getNodePlatforms: &getNodePlatformsMock{
+				platforms: []string{"linux/amd64", "linux/arm64"},
+			},
+			configVerifyByPolicy: &configVerifyByPolicyMock{
+				digest: "inspected",
+			},
+			wantDeny: fmt.Errorf("Inspected image digest inspected conflicts with verified digest signed"),
+		},
+		{
+			name:      "If the node platforms for the config policy can't be read, return the error",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:tag",
+			policy: &policyv1.Policy{
+				Config: policyv1.Config{
+					MaxLayers: &maxLayers,
+				},
+			},
+			getNodePlatforms: &getNodePlatformsMock{
+				err: fmt.Errorf("forbidden"),
+			},
+			wantErr: fmt.Errorf("forbidden"),
+		},
+		{
+			name:      "The node platforms are not read for a platforms only policy",
+			namespace: "wibble",
+			imageName: "icr.io/wibble/some:tag",
+			policy: &policyv1.Policy{
+				Platforms: policyv1.Platforms{
+					Required: []string{"linux/amd64"},
+				},
+			},
+			configVerifyByPolicy: &configVerifyByPolicyMock{
+				digest: "inspected",
+			},
+			wantDigest: "inspected",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -339,10 +570,20 @@ func Test_enforcer_DigestByPolicy(t *testing.T) {
 			kubeWrapper := mockKubeWrapper{}
 			kubeWrapper.Test(t)
 			defer kubeWrapper.AssertExpectations(t)
+			if tt.getNodePlatforms != nil {
+				kubeWrapper.
+					On("GetNodePlatforms").
+					Return(tt.getNodePlatforms.platforms, tt.getNodePlatforms.err).
+					Once()
+			}
 			if tt.getBasicCredentials != nil {
 				require.NotNil(t, tt.policy)
+				storeNamespace := tt.namespace
+				if tt.policy.Simple.StoreSecretNamespace != "" {
+					storeNamespace = tt.policy.Simple.StoreSecretNamespace
+				}
 				kubeWrapper.
-					On("GetBasicCredentials", tt.namespace, tt.policy.Simple.StoreSecret).
+					On("GetBasicCredentials", storeNamespace, tt.policy.Simple.StoreSecret).
 					Return(tt.getBasicCredentials.storeUser, tt.getBasicCredentials.storePassword, tt.getBasicCredentials.err).
 					Once()
 			}
@@ -376,9 +617,13 @@ func Test_enforcer_DigestByPolicy(t *testing.T) {
 				inPolicy := tt.transformPolicies.policy
 				require.NotNil(t, tt.createRegistryDir)
 				inConfigDir := tt.createRegistryDir.storeConfigDir
+				var platforms []string
+				if tt.policy.Simple.MultiArch.Verify == policyv1.MultiArchVerifyNodes {
+					platforms = tt.getNodePlatforms.platforms
+				}
 				digest := bytes.NewBuffer([]byte(tt.simpleVerifyByPolicy.digest))
 				simpleVerifier.
-					On("VerifyByPolicy", tt.imageName, tt.credentials, inConfigDir, inPolicy).
+					On("VerifyByPolicy", mock.Anything, tt.imageName, tt.credentials, inConfigDir, inPolicy, tt.policy.Simple.MultiArch, platforms).
 					Return(digest, tt.simpleVerifyByPolicy.deny, tt.simpleVerifyByPolicy.err).
 					Once()
 			}
@@ -390,16 +635,32 @@ func Test_enforcer_DigestByPolicy(t *testing.T) {
 					Once()
 			}
 
+			configVerifier := mockImageConfigVerifier{}
+			configVerifier.Test(t)
+			defer configVerifier.AssertExpectations(t)
+			if tt.configVerifyByPolicy != nil {
+				digest := bytes.NewBuffer([]byte(tt.configVerifyByPolicy.digest))
+				var platforms []string
+				if tt.getNodePlatforms != nil {
+					platforms = tt.getNodePlatforms.platforms
+				}
+				configVerifier.
+					On("VerifyByPolicy", mock.Anything, tt.imageName, tt.credentials, tt.policy, platforms).
+					Return(digest, tt.configVerifyByPolicy.deny, tt.configVerifyByPolicy.err).
+					Once()
+			}
+
 			e := enforcer{
 				kubeClientsetWrapper: &kubeWrapper,
 				nv:                   &notaryVerfier,
 				sv:                   &simpleVerifier,
+				cv:                   &configVerifier,
 			}
 
-			gotDigest, gotDeny, gotErr := e.DigestByPolicy(tt.namespace, img, tt.credentials, tt.policy)
+			gotDigest, gotDeny, gotErr := e.DigestByPolicy(context.Background(), tt.namespace, img, tt.credentials, tt.policy)
 
 			if tt.wantDigest != "" {
-				wantDigest := bytes.NewBuffer([]byte(tt.simpleVerifyByPolicy.digest))
+				wantDigest := bytes.NewBuffer([]byte(tt.wantDigest))
 				assert.Equal(t, wantDigest, gotDigest)
 			}
 			assert.Equal(t, tt.wantDeny, gotDeny)
@@ -499,7 +760,7 @@ func Test_enforcer_VulnerabilityPolicy(t *testing.T) {
 				scanner.Test(t)
 				defer scanner.AssertExpectations(t)
 				scanner.
-					On("CanImageDeployBasedOnVulnerabilities", *img).
+					On("CanImageDeployBasedOnVulnerabilities", mock.Anything, *img).
 					Return(scannerResponse.response, scannerResponse.err).
 					Once()
 
@@ -521,7 +782,7 @@ func Test_enforcer_VulnerabilityPolicy(t *testing.T) {
 				scannerFactory: &scannerFactory,
 			}
 
-			gotResponse := e.VulnerabilityPolicy(img, tt.credentials, tt.policy)
+			gotResponse := e.VulnerabilityPolicy(context.Background(), img, tt.credentials, tt.policy)
 
 			assert.Equal(t, tt.wantResponse, gotResponse)
 		})
@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -22,6 +22,7 @@ import (
 
 	policyclientsetfake "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned/fake"
 	policyV1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/breakglass"
 	"github.com/IBM/portieris/pkg/kubernetes"
 	"github.com/IBM/portieris/pkg/metrics"
 	"github.com/IBM/portieris/pkg/notary/fakenotary"
@@ -51,6 +52,11 @@ var _ = BeforeSuite(func() {
 
 var _ = AfterSuite(func() {
 	os.RemoveAll(tempTrustDir)
+	// unregister the metrics of the last test, so that tests that run after the suite can register theirs
+	if pm != nil {
+		pm.UnregisterAll()
+		pm = nil
+	}
 })
 
 var (
@@ -84,7 +90,7 @@ func resetAllFakes() {
 	trust = &fakenotary.FakeNotary{}
 	cr = &fakeregistry.FakeRegistry{}
 	nv := notaryverifier.NewVerifier(kubeWrapper, trust, cr)
-	ctrl = NewController(kubeWrapper, policyClient, nv, pm)
+	ctrl = NewController(kubeWrapper, policyClient, nv, pm, breakglass.Config{}, VerificationConfig{})
 	wh = webhook.NewServer("notary", ctrl, []byte{}, []byte{})
 }
 
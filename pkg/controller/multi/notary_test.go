@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	policyclientsetfake "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned/fake"
+	"github.com/IBM/portieris/pkg/breakglass"
 	"github.com/IBM/portieris/pkg/kubernetes"
 	"github.com/IBM/portieris/pkg/notary/fakenotary"
 	"github.com/IBM/portieris/pkg/policy"
@@ -159,7 +160,7 @@ var _ = Describe("Main", func() {
 
 		updateController := func() {
 			nv := notaryverifier.NewVerifier(kubeWrapper, trust, cr)
-			ctrl = NewController(kubeWrapper, policyClient, nv, pm)
+			ctrl = NewController(kubeWrapper, policyClient, nv, pm, breakglass.Config{}, VerificationConfig{})
 			wh = webhook.NewServer("notary", ctrl, []byte{}, []byte{})
 		}
 
@@ -0,0 +1,269 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/portieris/helpers/credential"
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	kubeinformers "k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Prewarmer verifies the images of the Deployments, StatefulSets and DaemonSets in the cluster in the background,
+// on a schedule and when policies change, so that their verifications are cached before pods are next admitted
+// for them, and admission does not depend on registries and notary servers being available at that moment.
+type Prewarmer struct {
+	controller   *Controller
+	interval     time.Duration
+	deployments  appslisters.DeploymentLister
+	statefulSets appslisters.StatefulSetLister
+	daemonSets   appslisters.DaemonSetLister
+	synced       []cache.InformerSynced
+	// trigger holds a pass requested while another pass runs, further requests are merged into it
+	trigger chan struct{}
+}
+
+// prewarmJob is a verification of an image with the policy match and credentials that a workload uses for it
+type prewarmJob struct {
+	namespace   string
+	img         *image.Reference
+	credentials credential.Credentials
+	match       *policyv1.PolicyMatch
+}
+
+// NewPrewarmer creates a prewarmer that verifies workload images every interval, the results are only kept when
+// the controller has a verification cache, and those that expire within the interval are verified again.
+// Workloads are read from the informers of the factory, which must be started by the caller.
+func NewPrewarmer(c *Controller, interval time.Duration, factory kubeinformers.SharedInformerFactory) *Prewarmer {
+	deployments := factory.Apps().V1().Deployments()
+	statefulSets := factory.Apps().V1().StatefulSets()
+	daemonSets := factory.Apps().V1().DaemonSets()
+	return &Prewarmer{
+		controller:   c,
+		interval:     interval,
+		deployments:  deployments.Lister(),
+		statefulSets: statefulSets.Lister(),
+		daemonSets:   daemonSets.Lister(),
+		synced: []cache.InformerSynced{
+			deployments.Informer().HasSynced,
+			statefulSets.Informer().HasSynced,
+			daemonSets.Informer().HasSynced,
+		},
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// Trigger requests a pass, without waiting for it
+func (p *Prewarmer) Trigger() {
+	select {
+	case p.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// PolicyEventHandler requests a pass when a policy is created, deleted or its spec changes
+func (p *Prewarmer) PolicyEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { p.Trigger() },
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// status updates don't change the generation, and don't change which images are allowed
+			oldMeta, err := meta.Accessor(oldObj)
+			if err != nil {
+				return
+			}
+			newMeta, err := meta.Accessor(newObj)
+			if err != nil {
+				return
+			}
+			if oldMeta.GetGeneration() != newMeta.GetGeneration() {
+				p.Trigger()
+			}
+		},
+		DeleteFunc: func(obj interface{}) { p.Trigger() },
+	}
+}
+
+// Run makes a pass once the caches and the workload informers have synced, then every interval and when a pass
+// is triggered, until stopCh is closed
+func (p *Prewarmer) Run(stopCh <-chan struct{}, cacheSyncs ...cache.InformerSynced) {
+	if !cache.WaitForCacheSync(stopCh, append(cacheSyncs, p.synced...)...) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.prewarm(ctx)
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		case <-p.trigger:
+		}
+	}
+}
+
+// prewarm verifies the distinct images of the workloads in the cluster with a bounded number of workers
+func (p *Prewarmer) prewarm(ctx context.Context) {
+	start := time.Now()
+	templates, err := p.podTemplates()
+	if err != nil {
+		glog.Warningf("Unable to list workloads to pre-verify their images: %v", err)
+		return
+	}
+	jobs := p.jobs(templates)
+
+	c := p.controller
+	workers := c.verificationConfig.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	queue := make(chan prewarmJob, len(jobs))
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				if ctx.Err() != nil {
+					return
+				}
+				p.verify(ctx, job)
+			}
+		}()
+	}
+	wg.Wait()
+	glog.Infof("Pre-verified %d images of %d workloads in %v", len(jobs), len(templates), time.Since(start))
+}
+
+// verify verifies an image as it is verified on admission, only the cached result is of interest
+func (p *Prewarmer) verify(ctx context.Context, job prewarmJob) {
+	c := p.controller
+	ctx = withPolicySource(withFreshness(ctx, p.interval), job.match)
+	if c.verificationConfig.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.verificationConfig.Timeout)
+		defer cancel()
+	}
+	c.PMetrics.VerificationPrewarmCount.Inc()
+	c.Enforcer.VulnerabilityPolicy(ctx, job.img, job.credentials, job.match.Policy)
+	if _, _, err := c.Enforcer.DigestByPolicy(ctx, job.namespace, job.img, job.credentials, job.match.Policy); err != nil {
+		glog.Warningf("Unable to pre-verify image %s in namespace %s: %v", job.img.String(), job.namespace, err)
+	}
+}
+
+// jobs finds the policy and credentials for each image of the pod templates, images that are allowed by an exception,
+// or whose policy does not verify or scan them, are skipped and the same verification is only made once
+func (p *Prewarmer) jobs(templates []corev1.PodTemplateSpec) []prewarmJob {
+	c := p.controller
+	var jobs []prewarmJob
+	seen := map[string]bool{}
+	for _, pt := range templates {
+		namespace := pt.Namespace
+		workload := policyv1.Workload{
+			Labels:             pt.Labels,
+			ServiceAccountName: pt.Spec.ServiceAccountName,
+		}
+		// pods of the workload get the pull secrets of their service account when they have none, as on admission
+		spec := *pt.Spec.DeepCopy()
+		if err := c.kubeClientsetWrapper.AddServiceAccountPullSecrets(namespace, &spec); err != nil {
+			glog.Warningf("Unable to read the image pull secrets of service account %q in namespace %s: %v", spec.ServiceAccountName, namespace, err)
+		}
+		for _, container := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+			if strings.TrimSpace(container.Image) == "" {
+				continue
+			}
+			img, err := image.NewReference(container.Image)
+			if err != nil {
+				continue
+			}
+			if exception, err := c.policyClient.GetImagePolicyException(namespace, img.String()); err == nil && exception != nil {
+				continue
+			}
+			policyMatch, err := c.policyClient.GetPolicyToEnforce(namespace, img.String(), workload)
+			if err != nil || policyMatch.Policy == nil || !(verifies(policyMatch.Policy) || scans(policyMatch.Policy)) {
+				continue
+			}
+			credentials := c.getPodCredentials(namespace, img, spec)
+			key, err := verificationKey("prewarm", namespace, img, "", credentials, policyMatch.Policy, nil)
+			if err != nil {
+				continue
+			}
+			// verifications are recorded for the resource that the policy was read from
+			key += policyMatch.Source
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			jobs = append(jobs, prewarmJob{namespace: namespace, img: img, credentials: credentials, match: policyMatch})
+		}
+	}
+	return jobs
+}
+
+// podTemplates lists the pod templates of the Deployments, StatefulSets and DaemonSets in all namespaces from the
+// informers, with the namespace of the workload set on each template
+func (p *Prewarmer) podTemplates() ([]corev1.PodTemplateSpec, error) {
+	var templates []corev1.PodTemplateSpec
+	add := func(namespace string, pt corev1.PodTemplateSpec) {
+		pt.Namespace = namespace
+		templates = append(templates, pt)
+	}
+
+	deployments, err := p.deployments.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("deployments: %v", err)
+	}
+	for _, d := range deployments {
+		add(d.Namespace, d.Spec.Template)
+	}
+	statefulSets, err := p.statefulSets.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("statefulsets: %v", err)
+	}
+	for _, s := range statefulSets {
+		add(s.Namespace, s.Spec.Template)
+	}
+	daemonSets, err := p.daemonSets.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("daemonsets: %v", err)
+	}
+	for _, d := range daemonSets {
+		add(d.Namespace, d.Spec.Template)
+	}
+	return templates, nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/IBM/portieris/pkg/verifier/vulnerability"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func podTemplate(initImages []string, images ...string) corev1.PodTemplateSpec {
+	pt := corev1.PodTemplateSpec{}
+	for _, name := range initImages {
+		pt.Spec.InitContainers = append(pt.Spec.InitContainers, corev1.Container{Image: name})
+	}
+	for _, name := range images {
+		pt.Spec.Containers = append(pt.Spec.Containers, corev1.Container{Image: name})
+	}
+	return pt
+}
+
+// setupPrewarmer creates a prewarmer for the objects, whose workload informers have synced, with a mock enforcer
+// and a policy client that enforces trust for images other than those of the exception and the unverified image
+func setupPrewarmer(t *testing.T, objects ...runtime.Object) (*Prewarmer, *mockEnforcer) {
+	pm := metrics.NewMetrics()
+	t.Cleanup(pm.UnregisterAll)
+	trust := &policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.TruePointer}}
+
+	policyClient := &mockPolicyClient{}
+	policyClient.Test(t)
+	policyClient.On("GetImagePolicyException", "default", "icr.io/hello/excepted:1").Return(&policyv1.ImagePolicyException{}, nil)
+	policyClient.On("GetImagePolicyException", mock.Anything, mock.Anything).Return((*policyv1.ImagePolicyException)(nil), nil)
+	policyClient.On("GetPolicyToEnforce", mock.Anything, "icr.io/hello/unverified:1", mock.Anything).Return(&policyv1.PolicyMatch{Policy: &policyv1.Policy{}}, nil)
+	policyClient.On("GetPolicyToEnforce", mock.Anything, mock.Anything, mock.Anything).Return(&policyv1.PolicyMatch{Policy: trust}, nil)
+
+	me := &mockEnforcer{}
+	me.Test(t)
+	me.On("VulnerabilityPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(vulnerability.ScanResponse{CanDeploy: true})
+	me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+	clientset := k8sfake.NewSimpleClientset(objects...)
+	c := &Controller{
+		kubeClientsetWrapper: kubernetes.NewKubeClientsetWrapper(clientset),
+		policyClient:         policyClient,
+		Enforcer:             me,
+		PMetrics:             pm,
+		verificationConfig:   VerificationConfig{Workers: 2, Timeout: time.Minute},
+	}
+	factory := kubeinformers.NewSharedInformerFactory(clientset, 0)
+	p := NewPrewarmer(c, time.Hour, factory)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+	return p, me
+}
+
+func TestPrewarmer_prewarm(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: podTemplate([]string{"icr.io/hello/init:1"}, "icr.io/hello/world:1", "icr.io/hello/excepted:1", "icr.io/hello/unverified:1", ""),
+		},
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Template: podTemplate(nil, "icr.io/hello/world:1")},
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "kube-system"},
+		Spec:       appsv1.DaemonSetSpec{Template: podTemplate(nil, "icr.io/hello/world:1")},
+	}
+	p, me := setupPrewarmer(t, deployment, statefulSet, daemonSet)
+
+	p.prewarm(context.Background())
+
+	var verified []string
+	for _, call := range me.Calls {
+		if call.Method != "DigestByPolicy" {
+			continue
+		}
+		ctx := call.Arguments.Get(0).(context.Context)
+		assert.Equal(t, time.Hour, ctx.Value(freshnessKey{}), "verifications refresh results that expire before the next pass")
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		verified = append(verified, call.Arguments.String(1)+"/"+call.Arguments.Get(2).(*image.Reference).String())
+	}
+	assert.ElementsMatch(t, []string{
+		"default/icr.io/hello/init:1",
+		"default/icr.io/hello/world:1",
+		"kube-system/icr.io/hello/world:1",
+	}, verified)
+	me.AssertNumberOfCalls(t, "VulnerabilityPolicy", 3)
+	assert.Equal(t, 3.0, testutil.ToFloat64(p.controller.PMetrics.VerificationPrewarmCount))
+}
+
+func TestPrewarmer_jobsServiceAccount(t *testing.T) {
+	pullSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "default"},
+		Data: map[string][]byte{
+			".dockerconfigjson": []byte(`{ "auths": { "icr.io": { "username": "token", "password": "registry-token" } } }`),
+		},
+	}
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: "builder", Namespace: "default"},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Template: podTemplate(nil, "icr.io/hello/world:1")},
+	}
+	deployment.Spec.Template.Spec.ServiceAccountName = "builder"
+	p, _ := setupPrewarmer(t, pullSecret, serviceAccount, deployment)
+	c := p.controller
+
+	templates, err := p.podTemplates()
+	require.NoError(t, err)
+	jobs := p.jobs(templates)
+	require.Len(t, jobs, 1)
+	assert.NotEmpty(t, jobs[0].credentials, "the pull secrets of the service account are used")
+
+	// the deployment as it is admitted
+	raw, err := json.Marshal(deployment)
+	require.NoError(t, err)
+	_, pt, err := c.kubeClientsetWrapper.GetPodTemplate(&admissionv1.AdmissionRequest{
+		Namespace: "default",
+		Resource:  metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		Object:    runtime.RawExtension{Raw: raw},
+	})
+	require.NoError(t, err)
+	img, err := image.NewReference(pt.Spec.Containers[0].Image)
+	require.NoError(t, err)
+	credentials := c.getPodCredentials("default", img, pt.Spec)
+
+	admissionKey, err := verificationKey("digest", "default", img, "", credentials, jobs[0].match.Policy, nil)
+	require.NoError(t, err)
+	prewarmKey, err := verificationKey("digest", jobs[0].namespace, jobs[0].img, "", jobs[0].credentials, jobs[0].match.Policy, nil)
+	require.NoError(t, err)
+	assert.Equal(t, admissionKey, prewarmKey)
+}
+
+func TestPrewarmer_PolicyEventHandler(t *testing.T) {
+	p, _ := setupPrewarmer(t)
+	handler := p.PolicyEventHandler()
+	policy := &policyv1.ImagePolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy", Generation: 1}}
+	changed := policy.DeepCopy()
+	changed.Generation = 2
+
+	handler.OnUpdate(policy, policy.DeepCopy())
+	assert.Len(t, p.trigger, 0, "a status update does not request a pass")
+	handler.OnUpdate(policy, changed)
+	assert.Len(t, p.trigger, 1)
+	handler.OnAdd(policy, false)
+	handler.OnDelete(policy)
+	assert.Len(t, p.trigger, 1, "requests are merged while a pass is pending")
+}
+
+func TestPrewarmer_Run(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Template: podTemplate(nil, "icr.io/hello/world:1")},
+	}
+	p, me := setupPrewarmer(t, deployment)
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		p.Run(stopCh, func() bool { return true })
+		close(done)
+	}()
+
+	calls := func(n int) func() bool {
+		return func() bool {
+			return testutil.ToFloat64(p.controller.PMetrics.VerificationPrewarmCount) == float64(n)
+		}
+	}
+	assert.Eventually(t, calls(1), 5*time.Second, time.Millisecond, "a pass is made once caches have synced")
+	p.Trigger()
+	assert.Eventually(t, calls(2), 5*time.Second, time.Millisecond, "a pass is made when triggered")
+	close(stopCh)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("prewarmer did not stop")
+	}
+	me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	policyclientset "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned"
+	informersv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/informers/externalversions/portieris.cloud.ibm.com/v1"
+	listersv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/listers/portieris.cloud.ibm.com/v1"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/verifier/vulnerability"
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// recordTimeout bounds writing a record, which is done in the background so that admission doesn't wait for it
+const recordTimeout = 10 * time.Second
+
+// policySourceKey is the context key of the policy resource set by withPolicySource
+type policySourceKey struct{}
+
+// policySource identifies the resource that a policy was read from, and its generation
+type policySource struct {
+	name       string
+	generation int64
+}
+
+// withPolicySource records the resource that the policy of a verification was read from, verifications are only
+// recorded, and records are only reused, for the generation of the resource that they were made against
+func withPolicySource(ctx context.Context, match *policyv1.PolicyMatch) context.Context {
+	return context.WithValue(ctx, policySourceKey{}, policySource{name: match.Source, generation: match.Generation})
+}
+
+// VerificationRecords shares the verifications that allowed images between Portieris replicas, and across restarts,
+// as cluster-scoped ImageVerification resources that are named by the key of the verification. Records are signed
+// with a key that the replicas share, so that a record made by anyone else is not reused.
+type VerificationRecords struct {
+	policyClientset policyclientset.Interface
+	lister          listersv1.ImageVerificationLister
+	hasSynced       cache.InformerSynced
+	informer        cache.SharedIndexInformer
+	key             []byte
+	now             func() time.Time
+}
+
+// NewVerificationRecords creates verification records that are read from the informer, which must be started,
+// and signed with the key
+func NewVerificationRecords(policyClientset policyclientset.Interface, informer informersv1.ImageVerificationInformer, key []byte) *VerificationRecords {
+	return &VerificationRecords{
+		policyClientset: policyClientset,
+		lister:          informer.Lister(),
+		hasSynced:       informer.Informer().HasSynced,
+		informer:        informer.Informer(),
+		key:             key,
+		now:             time.Now,
+	}
+}
+
+// HasSynced reports whether the records have been read
+func (r *VerificationRecords) HasSynced() bool {
+	return r.hasSynced()
+}
+
+// onDelete calls deleted with the name of each record that is deleted, by any replica
+func (r *VerificationRecords) onDelete(deleted func(name string)) {
+	r.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if name, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				deleted(name)
+			}
+		},
+	})
+}
+
+// get returns the recorded verification for the key, when it was made against the policy that the context is for
+func (r *VerificationRecords) get(ctx context.Context, key string) (cacheEntry, bool) {
+	source, ok := ctx.Value(policySourceKey{}).(policySource)
+	if !ok {
+		return cacheEntry{}, false
+	}
+	verification, err := r.lister.Get(key)
+	if err != nil || !verification.Applies(source.name, source.generation, r.now()) {
+		return cacheEntry{}, false
+	}
+	if !r.signed(verification) {
+		glog.Warningf("Ignoring ImageVerification %s, which is not signed by Portieris", key)
+		return cacheEntry{}, false
+	}
+	// only verifications that allowed the image are recorded, the key says which kind of verification it was
+	return cacheEntry{
+		digest:  bytes.NewBufferString(verification.Spec.Digest),
+		scan:    vulnerability.ScanResponse{CanDeploy: true},
+		expires: verification.Spec.ExpiresAt.Time,
+	}, true
+}
+
+// put records a verification that allowed an image against the policy that the context is for, in the background
+func (r *VerificationRecords) put(ctx context.Context, key string, spec policyv1.ImageVerificationSpec) {
+	source, ok := ctx.Value(policySourceKey{}).(policySource)
+	if !ok {
+		return
+	}
+	spec.Policy = source.name
+	spec.PolicyGeneration = source.generation
+	signature, err := r.sign(key, spec)
+	if err != nil {
+		glog.Warningf("Unable to sign the verification of image %s: %v", spec.Image, err)
+		return
+	}
+	spec.Signature = signature
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+		defer cancel()
+		verifications := r.policyClientset.PortierisV1().ImageVerifications()
+		var err error
+		if existing, getErr := r.lister.Get(key); getErr == nil {
+			updated := existing.DeepCopy()
+			updated.Spec = spec
+			_, err = verifications.Update(ctx, updated, metav1.UpdateOptions{})
+		} else {
+			_, err = verifications.Create(ctx, &policyv1.ImageVerification{
+				ObjectMeta: metav1.ObjectMeta{Name: key, Labels: map[string]string{"app": "portieris"}},
+				Spec:       spec,
+			}, metav1.CreateOptions{})
+		}
+		// another replica recorded the same verification
+		if err != nil && !errors.IsAlreadyExists(err) && !errors.IsConflict(err) {
+			glog.Warningf("Unable to record the verification of image %s: %v", spec.Image, err)
+		}
+	}()
+}
+
+// sign returns the HMAC of the name and the spec of a record, without its signature
+func (r *VerificationRecords) sign(name string, spec policyv1.ImageVerificationSpec) (string, error) {
+	spec.Signature = ""
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write(specJSON)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// signed reports whether a record has a valid signature
+func (r *VerificationRecords) signed(verification *policyv1.ImageVerification) bool {
+	want, err := r.sign(verification.Name, verification.Spec)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(want), []byte(verification.Spec.Signature))
+}
+
+// Run deletes expired and unsigned records every interval, once the records have been read, until stopCh is closed
+func (r *VerificationRecords) Run(stopCh <-chan struct{}, interval time.Duration) {
+	if !cache.WaitForCacheSync(stopCh, r.hasSynced) {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.collect(context.Background())
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect deletes the records that have expired or are not signed with the key, unless they were changed since they
+// were read
+func (r *VerificationRecords) collect(ctx context.Context) {
+	verifications, err := r.lister.List(labels.Everything())
+	if err != nil {
+		glog.Warningf("Unable to list ImageVerifications: %v", err)
+		return
+	}
+	now := r.now()
+	deleted := 0
+	for _, verification := range verifications {
+		if now.Before(verification.Spec.ExpiresAt.Time) && r.signed(verification) {
+			continue
+		}
+		resourceVersion := verification.ResourceVersion
+		err := r.policyClientset.PortierisV1().ImageVerifications().Delete(ctx, verification.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion},
+		})
+		switch {
+		case err == nil:
+			deleted++
+		case errors.IsNotFound(err), errors.IsConflict(err):
+		default:
+			glog.Warningf("Unable to delete ImageVerification %s: %v", verification.Name, err)
+		}
+	}
+	if deleted > 0 {
+		glog.Infof("Deleted %d expired or unsigned ImageVerifications", deleted)
+	}
+}
+
+// flush deletes every record, so that no replica reuses the verifications that they hold
+func (r *VerificationRecords) flush(ctx context.Context) error {
+	verifications, err := r.lister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, verification := range verifications {
+		err := r.policyClientset.PortierisV1().ImageVerifications().Delete(ctx, verification.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	glog.Infof("Deleted %d ImageVerifications", len(verifications))
+	return nil
+}
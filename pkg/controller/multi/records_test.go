@@ -0,0 +1,259 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IBM/portieris/helpers/credential"
+	"github.com/IBM/portieris/helpers/image"
+	policyclientsetfake "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned/fake"
+	informers "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/informers/externalversions"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/IBM/portieris/pkg/verifier/vulnerability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// recordsKey is the key that the records of replicas are signed with
+var recordsKey = []byte("portieris")
+
+// setupReplica creates a caching enforcer around a mock enforcer that shares its verifications through the
+// ImageVerifications of the clientset, as a Portieris replica does
+func setupReplica(t *testing.T, pm *metrics.PortierisMetrics, policyClientset *policyclientsetfake.Clientset) (*cachingEnforcer, *mockEnforcer, *VerificationRecords) {
+	factory := informers.NewSharedInformerFactory(policyClientset, 0)
+	records := NewVerificationRecords(policyClientset, factory.Portieris().V1().ImageVerifications(), recordsKey)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	cache := NewVerificationCache(time.Minute, 10*time.Second, pm)
+	cache.ShareWith(records)
+	me := &mockEnforcer{}
+	me.Test(t)
+	return &cachingEnforcer{Enforcer: me, cache: cache, kubeWrapper: kubernetes.NewKubeClientsetWrapper(k8sfake.NewSimpleClientset())}, me, records
+}
+
+// waitForRecords waits until the replica has read n records
+func waitForRecords(t *testing.T, records *VerificationRecords, n int) {
+	assert.Eventually(t, func() bool {
+		verifications, err := records.lister.List(labels.Everything())
+		return err == nil && len(verifications) == n
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestVerificationRecords(t *testing.T) {
+	trust := &policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.TruePointer}}
+	va := &policyv1.Policy{Vulnerability: policyv1.Vulnerability{ICCRVA: policyv1.ICCRVA{Enabled: policyv1.TruePointer}}}
+	creds := credential.Credentials{{Username: "user", Password: "password"}}
+	pinned, _ := image.NewReference("icr.io/hello/world@sha256:1234")
+	match := &policyv1.PolicyMatch{Source: "default/signed", Generation: 1}
+	ctx := withPolicySource(context.Background(), match)
+
+	t.Run("shares a verification that allowed an image with another replica", func(t *testing.T) {
+		pm := metrics.NewMetrics()
+		t.Cleanup(pm.UnregisterAll)
+		policyClientset := policyclientsetfake.NewSimpleClientset()
+		first, firstEnforcer, _ := setupReplica(t, pm, policyClientset)
+		second, secondEnforcer, secondRecords := setupReplica(t, pm, policyClientset)
+		firstEnforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+		firstEnforcer.On("VulnerabilityPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(vulnerability.ScanResponse{CanDeploy: true})
+
+		first.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		first.VulnerabilityPolicy(ctx, pinned, creds, va)
+		waitForRecords(t, secondRecords, 2)
+
+		digest, deny, err := second.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		assert.NoError(t, err)
+		assert.NoError(t, deny)
+		assert.Equal(t, "1234", digest.String())
+		assert.True(t, second.VulnerabilityPolicy(ctx, pinned, creds, va).CanDeploy)
+		secondEnforcer.AssertNotCalled(t, "DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		secondEnforcer.AssertNotCalled(t, "VulnerabilityPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		verifications, err := policyClientset.PortierisV1().ImageVerifications().List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		var recorded []policyv1.ImageVerificationSpec
+		for _, v := range verifications.Items {
+			assert.True(t, v.Spec.ExpiresAt.After(time.Now()))
+			assert.NotEmpty(t, v.Spec.Signature)
+			v.Spec.ExpiresAt = metav1.Time{}
+			v.Spec.Signature = ""
+			recorded = append(recorded, v.Spec)
+		}
+		assert.ElementsMatch(t, []policyv1.ImageVerificationSpec{
+			{Image: pinned.String(), Digest: "1234", Namespace: "default", Policy: "default/signed", PolicyGeneration: 1, Verifiers: []string{"trust"}},
+			{Image: pinned.String(), Digest: "1234", Policy: "default/signed", PolicyGeneration: 1, Verifiers: []string{"vulnerability"}},
+		}, recorded)
+	})
+
+	t.Run("a flush discards the verifications of every replica", func(t *testing.T) {
+		pm := metrics.NewMetrics()
+		t.Cleanup(pm.UnregisterAll)
+		policyClientset := policyclientsetfake.NewSimpleClientset()
+		first, firstEnforcer, firstRecords := setupReplica(t, pm, policyClientset)
+		second, secondEnforcer, secondRecords := setupReplica(t, pm, policyClientset)
+		firstEnforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+		secondEnforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		first.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		waitForRecords(t, firstRecords, 1)
+		waitForRecords(t, secondRecords, 1)
+		second.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		secondEnforcer.AssertNotCalled(t, "DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		rr := httptest.NewRecorder()
+		first.cache.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/verification-cache/flush", nil))
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		verifications, err := policyClientset.PortierisV1().ImageVerifications().List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, verifications.Items)
+		waitForRecords(t, secondRecords, 0)
+		assert.Eventually(t, func() bool {
+			second.cache.mutex.Lock()
+			defer second.cache.mutex.Unlock()
+			return len(second.cache.entries) == 0
+		}, 5*time.Second, time.Millisecond)
+
+		first.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		firstEnforcer.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+		second.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		secondEnforcer.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+	})
+
+	t.Run("does not reuse a verification of another generation of the policy", func(t *testing.T) {
+		pm := metrics.NewMetrics()
+		t.Cleanup(pm.UnregisterAll)
+		policyClientset := policyclientsetfake.NewSimpleClientset()
+		first, firstEnforcer, _ := setupReplica(t, pm, policyClientset)
+		second, secondEnforcer, secondRecords := setupReplica(t, pm, policyClientset)
+		firstEnforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+		secondEnforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		first.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		waitForRecords(t, secondRecords, 1)
+		changed := withPolicySource(context.Background(), &policyv1.PolicyMatch{Source: "default/signed", Generation: 2})
+		second.DigestByPolicy(changed, "default", pinned, creds, trust)
+		secondEnforcer.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+	})
+
+	t.Run("does not reuse a record that is not signed with the key", func(t *testing.T) {
+		pm := metrics.NewMetrics()
+		t.Cleanup(pm.UnregisterAll)
+		policyClientset := policyclientsetfake.NewSimpleClientset()
+		first, firstEnforcer, _ := setupReplica(t, pm, policyClientset)
+		second, secondEnforcer, secondRecords := setupReplica(t, pm, policyClientset)
+		firstEnforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+		secondEnforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+		first.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		waitForRecords(t, secondRecords, 1)
+		verifications, err := policyClientset.PortierisV1().ImageVerifications().List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		recorded := verifications.Items[0]
+
+		forge := func(t *testing.T, change func(*policyv1.ImageVerification)) {
+			forged := recorded.DeepCopy()
+			change(forged)
+			_, err := policyClientset.PortierisV1().ImageVerifications().Update(context.Background(), forged, metav1.UpdateOptions{})
+			require.NoError(t, err)
+			assert.Eventually(t, func() bool {
+				v, err := secondRecords.lister.Get(forged.Name)
+				return err == nil && v.Spec.Signature == forged.Spec.Signature && v.Spec.Digest == forged.Spec.Digest
+			}, 5*time.Second, time.Millisecond)
+		}
+		forge(t, func(v *policyv1.ImageVerification) { v.Spec.Digest = "5678" })
+		_, ok := secondRecords.get(ctx, recorded.Name)
+		assert.False(t, ok, "a record that was changed after it was signed is not reused")
+
+		other := NewVerificationRecords(policyClientset, informers.NewSharedInformerFactory(policyClientset, 0).Portieris().V1().ImageVerifications(), []byte("other"))
+		forge(t, func(v *policyv1.ImageVerification) {
+			v.Spec.Digest = "5678"
+			v.Spec.Signature, err = other.sign(v.Name, v.Spec)
+			require.NoError(t, err)
+		})
+		_, ok = secondRecords.get(ctx, recorded.Name)
+		assert.False(t, ok, "a record that was signed with another key is not reused")
+
+		forge(t, func(v *policyv1.ImageVerification) { v.Spec.Signature = "" })
+		second.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		secondEnforcer.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+	})
+
+	t.Run("does not record verifications that denied an image or without a policy resource", func(t *testing.T) {
+		pm := metrics.NewMetrics()
+		t.Cleanup(pm.UnregisterAll)
+		policyClientset := policyclientsetfake.NewSimpleClientset()
+		e, me, _ := setupReplica(t, pm, policyClientset)
+		me.On("DigestByPolicy", mock.Anything, "default", mock.Anything, mock.Anything, mock.Anything).Return((*bytes.Buffer)(nil), errors.New("trust: policy denied the request"), nil)
+		me.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		e.DigestByPolicy(ctx, "default", pinned, creds, trust)
+		e.DigestByPolicy(context.Background(), "other", pinned, creds, trust)
+		me.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+		time.Sleep(100 * time.Millisecond)
+		verifications, err := policyClientset.PortierisV1().ImageVerifications().List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, verifications.Items)
+	})
+}
+
+func TestVerificationRecords_collect(t *testing.T) {
+	now := time.Now()
+	signer := &VerificationRecords{key: recordsKey}
+	verification := func(name string, expiresAt time.Time, signed bool) *policyv1.ImageVerification {
+		v := &policyv1.ImageVerification{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       policyv1.ImageVerificationSpec{ExpiresAt: metav1.NewTime(expiresAt)},
+		}
+		if signed {
+			var err error
+			v.Spec.Signature, err = signer.sign(name, v.Spec)
+			require.NoError(t, err)
+		}
+		return v
+	}
+	policyClientset := policyclientsetfake.NewSimpleClientset(
+		verification("expired", now.Add(-time.Second), true),
+		verification("unsigned", now.Add(time.Minute), false),
+		verification("valid", now.Add(time.Minute), true),
+	)
+	factory := informers.NewSharedInformerFactory(policyClientset, 0)
+	records := NewVerificationRecords(policyClientset, factory.Portieris().V1().ImageVerifications(), recordsKey)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+	records.now = func() time.Time { return now }
+
+	records.collect(context.Background())
+
+	verifications, err := policyClientset.PortierisV1().ImageVerifications().List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, verifications.Items, 1)
+	assert.Equal(t, "valid", verifications.Items[0].Name)
+}
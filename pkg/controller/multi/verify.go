@@ -0,0 +1,290 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/transport"
+	"github.com/IBM/portieris/pkg/verifier/condition"
+	"github.com/IBM/portieris/pkg/verifier/rego"
+	"github.com/golang/glog"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerificationConfig controls how the images of a pod are verified
+type VerificationConfig struct {
+	// Cache reuses the results of verifying images when it is not nil
+	Cache *VerificationCache
+	// Workers is the number of distinct images of a pod that are verified concurrently, at least one
+	Workers int
+	// Timeout is how long the images of a pod are verified for, those that are not verified in time are denied, zero is no limit
+	Timeout time.Duration
+	// ScannerClient is the HTTP client that vulnerability scanners send requests with, they create their own when it is nil
+	ScannerClient *http.Client
+	// RegistryTransport sets the proxy and trusted CAs of registry requests that containers/image makes, and counts
+	// them, the proxy is read from the environment and the system CAs are trusted when it is nil
+	RegistryTransport *transport.Transport
+}
+
+// imageVerification is the result of verifying an image of a pod against its policy
+type imageVerification struct {
+	// image is the image name from the container, img is nil when it can't be parsed
+	image string
+	img   *image.Reference
+	// key identifies the image in denials, by digest when it is known
+	key       string
+	denials   []string
+	warnings  []string
+	exception string
+	// digest is set when the image is allowed and the container can be mutated to it, if mutate is set
+	digest *bytes.Buffer
+	mutate bool
+	err    error
+}
+
+// namespaceLabels reads the labels of a namespace once, when a policy with conditions or a Rego module needs them
+type namespaceLabels struct {
+	once   sync.Once
+	labels map[string]string
+	err    error
+}
+
+func (n *namespaceLabels) get(c *Controller, namespace string) (map[string]string, error) {
+	n.once.Do(func() {
+		n.labels, n.err = c.kubeClientsetWrapper.GetNamespaceLabels(namespace)
+		if n.labels == nil {
+			n.labels = map[string]string{}
+		}
+	})
+	return n.labels, n.err
+}
+
+// verifyImages verifies the distinct images of a pod with a bounded number of workers. When an image can't be
+// verified because of an error, the images that have not started verification are not verified, and images that
+// are not verified before the timeout, or before the admission request is abandoned, are denied.
+func (c *Controller) verifyImages(ctx context.Context, namespace string, podMeta metav1.ObjectMeta, workload policyv1.Workload, pod corev1.PodSpec, images []string, admissionRequest *admissionv1.AdmissionRequest) map[string]*imageVerification {
+	verifications := map[string]*imageVerification{}
+	jobs := make(chan string, len(images))
+	for _, name := range images {
+		if _, ok := verifications[name]; ok || strings.TrimSpace(name) == "" {
+			continue
+		}
+		verifications[name] = nil
+		jobs <- name
+	}
+	close(jobs)
+	if len(verifications) == 0 {
+		return verifications
+	}
+
+	workers := c.verificationConfig.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(verifications) {
+		workers = len(verifications)
+	}
+	// the context is cancelled when verification of the pod ends early, by an error, the timeout or the admission
+	// request being abandoned, and its cause is the reason that the remaining images are denied
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if c.verificationConfig.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, c.verificationConfig.Timeout,
+			fmt.Errorf("verification did not complete within %v", c.verificationConfig.Timeout))
+		defer cancelTimeout()
+	}
+	// results is buffered so that workers that finish after verification ends don't block
+	results := make(chan *imageVerification, len(verifications))
+	labels := &namespaceLabels{}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for name := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				v := c.verifyImage(ctx, namespace, podMeta, workload, pod, name, labels, admissionRequest)
+				// a verification that was cut short is denied with the reason it was stopped
+				if ctx.Err() != nil {
+					return
+				}
+				results <- v
+				if v.err != nil {
+					cancel(fmt.Errorf("verification of image %q failed", v.image))
+				}
+			}
+		}()
+	}
+
+	reason := ""
+	for pending := len(verifications); pending > 0 && reason == ""; pending-- {
+		select {
+		case v := <-results:
+			verifications[v.image] = v
+		case <-ctx.Done():
+			reason = stopReason(ctx)
+		}
+	}
+	// keep the results of images that were verified before verification stopped
+	for drained := false; !drained; {
+		select {
+		case v := <-results:
+			verifications[v.image] = v
+		default:
+			drained = true
+		}
+	}
+	for name, v := range verifications {
+		if v == nil {
+			glog.Warningf("Image %s in namespace %s not verified, %s", name, namespace, reason)
+			verifications[name] = unverified(name, reason)
+		}
+	}
+	return verifications
+}
+
+// stopReason describes why verification of the images of a pod stopped
+func stopReason(ctx context.Context) string {
+	switch cause := context.Cause(ctx); cause {
+	case context.Canceled, context.DeadlineExceeded:
+		return "the admission request was abandoned"
+	default:
+		return cause.Error()
+	}
+}
+
+// unverified denies an image that was not verified
+func unverified(name, reason string) *imageVerification {
+	v := &imageVerification{image: name, key: name}
+	if img, err := image.NewReference(name); err == nil {
+		v.img = img
+		v.key = imageKey(img)
+	}
+	v.denials = []string{fmt.Sprintf("Deny %q, %s", name, reason)}
+	return v
+}
+
+// imageKey identifies an image in denials, by digest when it has one otherwise by tag
+func imageKey(img *image.Reference) string {
+	if img.GetDigest() == "" {
+		return img.NameWithTag()
+	}
+	return fmt.Sprintf("%s:%s", img.NameWithoutTag(), img.GetDigest())
+}
+
+// verifyImage verifies an image of a pod against the policy for it
+func (c *Controller) verifyImage(ctx context.Context, namespace string, podMeta metav1.ObjectMeta, workload policyv1.Workload, pod corev1.PodSpec, name string, labels *namespaceLabels, admissionRequest *admissionv1.AdmissionRequest) *imageVerification {
+	v := &imageVerification{image: name, denials: []string{}}
+
+	// parse image
+	img, err := image.NewReference(name)
+	if err != nil {
+		glog.Error(err)
+		return v
+	}
+	v.img = img
+	v.key = imageKey(img)
+
+	// an exception that can not be read does not stop the policy being enforced
+	exception, err := c.policyClient.GetImagePolicyException(namespace, img.String())
+	if err != nil {
+		glog.Warningf("Unable to read ImagePolicyExceptions in namespace %s, enforcing policy: %v", namespace, err)
+	} else if exception != nil {
+		expiresAt := exception.Spec.ExpiresAt.UTC().Format(time.RFC3339)
+		glog.Warningf("Image %s allowed without a policy by ImagePolicyException %s/%s, approver: %q, reason: %q, expires at: %s",
+			img.String(), namespace, exception.Name, exception.Spec.Approver, exception.Spec.Reason, expiresAt)
+		v.warnings = append(v.warnings, fmt.Sprintf("image %q: allowed without a policy by ImagePolicyException %q until %s", img.String(), exception.Name, expiresAt))
+		v.exception = exception.Name
+		return v
+	}
+
+	glog.Infof("Getting policy for container image: %s   namespace: %s", img.String(), namespace)
+	policyMatch, err := c.policyClient.GetPolicyToEnforce(namespace, img.String(), workload)
+	if err != nil {
+		v.denials = append(v.denials, err.Error())
+		return v
+	}
+	containerPolicy := policyMatch.Policy
+	ctx = withPolicySource(ctx, policyMatch)
+	for _, conflict := range policyMatch.Conflicts {
+		v.warnings = append(v.warnings, fmt.Sprintf("image %q: policy from %s repository %q was used, %s matches equally well with a different policy", img.String(), policyMatch.Source, policyMatch.Repository, conflict))
+	}
+
+	credentialCandidates := c.getPodCredentials(namespace, img, pod)
+
+	scanResponse := c.Enforcer.VulnerabilityPolicy(ctx, img, credentialCandidates, containerPolicy)
+	if !scanResponse.CanDeploy {
+		v.denials = append(v.denials, scanResponse.DenyReason)
+	}
+
+	digest, deny, err := c.Enforcer.DigestByPolicy(ctx, namespace, img, credentialCandidates, containerPolicy)
+	if err != nil {
+		v.err = err
+		return v
+	}
+	// Update key from image:tag to image:digest
+	if digest != nil {
+		v.key = fmt.Sprintf("%s:%s", img.NameWithoutTag(), digest.String())
+	}
+	if deny != nil {
+		v.denials = append(v.denials, deny.Error())
+		return v
+	}
+
+	if len(containerPolicy.Conditions) > 0 || policyMatch.Rego != nil {
+		namespaceLabels, err := labels.get(c, namespace)
+		if err != nil {
+			v.err = err
+			return v
+		}
+		input := condition.Input{
+			Image:                img,
+			Pod:                  podMeta,
+			ServiceAccountName:   pod.ServiceAccountName,
+			Namespace:            namespace,
+			NamespaceLabels:      namespaceLabels,
+			Policy:               containerPolicy,
+			VulnerabilityAllowed: scanResponse.CanDeploy,
+		}
+		if digest != nil {
+			input.Digest = digest.String()
+		}
+		if deny := condition.VerifyByPolicy(input, containerPolicy.Conditions); deny != nil {
+			v.denials = append(v.denials, fmt.Sprintf("conditions: policy denied the request: %v", deny))
+			return v
+		}
+		if policyMatch.Rego != nil {
+			if deny := rego.VerifyByPolicy(ctx, c.kubeClientsetWrapper, *policyMatch.Rego, admissionRequest, input); deny != nil {
+				v.denials = append(v.denials, fmt.Sprintf("rego: policy denied the request: %v", deny))
+				return v
+			}
+		}
+	}
+
+	v.digest = digest
+	v.mutate = containerPolicy.MutateImage == nil || *containerPolicy.MutateImage
+	return v
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/verifier/vulnerability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setupVerification creates a controller that enforces a trust policy for every image with the enforcer it returns
+func setupVerification(t *testing.T, config VerificationConfig) (*Controller, *mockEnforcer) {
+	policy := &policyv1.Policy{Trust: policyv1.Trust{Enabled: policyv1.TruePointer}}
+	policyClient := &mockPolicyClient{}
+	policyClient.On("GetImagePolicyException", mock.Anything, mock.Anything).Return((*policyv1.ImagePolicyException)(nil), nil)
+	policyClient.On("GetPolicyToEnforce", mock.Anything, mock.Anything, mock.Anything).Return(&policyv1.PolicyMatch{Policy: policy}, nil)
+	enforcer := &mockEnforcer{}
+	enforcer.Test(t)
+	enforcer.On("VulnerabilityPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(vulnerability.ScanResponse{CanDeploy: true})
+	return &Controller{
+		kubeClientsetWrapper: &mockKubeWrapper{},
+		policyClient:         policyClient,
+		Enforcer:             enforcer,
+		verificationConfig:   config,
+	}, enforcer
+}
+
+func verifyPod(c *Controller, images ...string) map[string]*imageVerification {
+	return c.verifyImages(context.Background(), "default", metav1.ObjectMeta{}, policyv1.Workload{}, corev1.PodSpec{}, images, &admissionv1.AdmissionRequest{Namespace: "default"})
+}
+
+func TestController_verifyImages(t *testing.T) {
+	t.Run("verifies each distinct image once", func(t *testing.T) {
+		c, enforcer := setupVerification(t, VerificationConfig{Workers: 4})
+		enforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		got := verifyPod(c, "icr.io/a/sidecar:1", "icr.io/a/app:1", "icr.io/a/sidecar:1", " ")
+		assert.Len(t, got, 2)
+		enforcer.AssertNumberOfCalls(t, "DigestByPolicy", 2)
+		assert.Equal(t, "1234", got["icr.io/a/sidecar:1"].digest.String())
+		assert.Equal(t, "icr.io/a/app:1234", got["icr.io/a/app:1"].key)
+	})
+
+	t.Run("verifies images concurrently", func(t *testing.T) {
+		c, enforcer := setupVerification(t, VerificationConfig{Workers: 3, Timeout: 5 * time.Second})
+		// each verification waits until all three have started, which only happens when they run concurrently
+		var started sync.WaitGroup
+		started.Add(3)
+		enforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+			started.Done()
+			started.Wait()
+		}).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		got := verifyPod(c, "icr.io/a/one:1", "icr.io/a/two:1", "icr.io/a/three:1")
+		for name, v := range got {
+			assert.Empty(t, v.denials, name)
+		}
+	})
+
+	t.Run("denies images that are not verified before the timeout", func(t *testing.T) {
+		c, enforcer := setupVerification(t, VerificationConfig{Workers: 2, Timeout: 50 * time.Millisecond})
+		release := make(chan struct{})
+		defer close(release)
+		slow := mock.MatchedBy(func(img *image.Reference) bool { return img.String() == "icr.io/a/slow:1" })
+		enforcer.On("DigestByPolicy", mock.Anything, mock.Anything, slow, mock.Anything, mock.Anything).Run(func(mock.Arguments) { <-release }).Return(bytes.NewBufferString("1234"), nil, nil)
+		enforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(bytes.NewBufferString("1234"), nil, nil)
+
+		got := verifyPod(c, "icr.io/a/fast:1", "icr.io/a/slow:1")
+		var denied []string
+		for _, v := range got {
+			denied = append(denied, v.denials...)
+		}
+		assert.Equal(t, []string{`Deny "icr.io/a/slow:1", verification did not complete within 50ms`}, denied)
+	})
+
+	t.Run("does not start verifying images after an error", func(t *testing.T) {
+		c, enforcer := setupVerification(t, VerificationConfig{Workers: 1})
+		enforcer.On("DigestByPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return((*bytes.Buffer)(nil), nil, errors.New("trust server unavailable"))
+
+		got := verifyPod(c, "icr.io/a/one:1", "icr.io/a/two:1")
+		enforcer.AssertNumberOfCalls(t, "DigestByPolicy", 1)
+		assert.EqualError(t, got["icr.io/a/one:1"].err, "trust server unavailable")
+		assert.Equal(t, []string{`Deny "icr.io/a/two:1", verification of image "icr.io/a/one:1" failed`}, got["icr.io/a/two:1"].denials)
+	})
+
+	t.Run("stops verifying images when the admission request is abandoned", func(t *testing.T) {
+		c, enforcer := setupVerification(t, VerificationConfig{Workers: 2, Timeout: 5 * time.Second})
+		ctx, cancel := context.WithCancel(context.Background())
+		slow := mock.MatchedBy(func(img *image.Reference) bool { return img.String() == "icr.io/a/slow:1" })
+		// the slow verification only returns when its context is cancelled, as a registry client would
+		enforcer.On("DigestByPolicy", mock.Anything, mock.Anything, slow, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			cancel()
+			<-args.Get(0).(context.Context).Done()
+		}).Return((*bytes.Buffer)(nil), nil, context.Canceled)
+
+		got := c.verifyImages(ctx, "default", metav1.ObjectMeta{}, policyv1.Workload{}, corev1.PodSpec{}, []string{"icr.io/a/slow:1"}, &admissionv1.AdmissionRequest{Namespace: "default"})
+		assert.NoError(t, got["icr.io/a/slow:1"].err)
+		assert.Equal(t, []string{`Deny "icr.io/a/slow:1", the admission request was abandoned`}, got["icr.io/a/slow:1"].denials)
+	})
+}
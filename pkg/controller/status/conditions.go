@@ -0,0 +1,65 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"strings"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/policy/validation"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// status returns the status for the problems found in a policy at generation, keeping the transition times of
+// conditions that have not changed
+func status(p validation.Problems, generation int64, current policyv1.ImagePolicyStatus) policyv1.ImagePolicyStatus {
+	status := policyv1.ImagePolicyStatus{
+		ObservedGeneration: generation,
+		Conditions:         append([]metav1.Condition(nil), current.Conditions...),
+	}
+	valid := condition(policyv1.ConditionValid, generation, "Valid", "", nil)
+	switch {
+	case len(p.Spec) > 0:
+		valid = condition(policyv1.ConditionValid, generation, "", "InvalidSpec", p.Spec)
+	case len(p.Secrets) > 0:
+		valid = condition(policyv1.ConditionValid, generation, "", "SecretsNotResolved", p.Secrets)
+	case len(p.Keys) > 0:
+		valid = condition(policyv1.ConditionValid, generation, "", "KeysNotParsed", p.Keys)
+	}
+	meta.SetStatusCondition(&status.Conditions, valid)
+	meta.SetStatusCondition(&status.Conditions, condition(policyv1.ConditionSecretsResolved, generation, "Resolved", "NotResolved", p.Secrets))
+	meta.SetStatusCondition(&status.Conditions, condition(policyv1.ConditionKeysParsed, generation, "Parsed", "NotParsed", p.Keys))
+	return status
+}
+
+// condition is True with trueReason when there are no problems, otherwise it is False with falseReason
+func condition(conditionType string, generation int64, trueReason, falseReason string, problems []string) metav1.Condition {
+	if len(problems) == 0 {
+		return metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             trueReason,
+		}
+	}
+	return metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             falseReason,
+		Message:            strings.Join(problems, "; "),
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"testing"
+	"time"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/policy/validation"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_status(t *testing.T) {
+	valid := status(validation.Problems{}, 1, policyv1.ImagePolicyStatus{})
+	assert.Equal(t, int64(1), valid.ObservedGeneration)
+	if assert.Len(t, valid.Conditions, 3) {
+		for _, condition := range valid.Conditions {
+			assert.Equal(t, metav1.ConditionTrue, condition.Status, condition.Type)
+			assert.Equal(t, int64(1), condition.ObservedGeneration)
+		}
+	}
+
+	// keep the transition time of a condition that has not changed
+	transition := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	for i := range valid.Conditions {
+		valid.Conditions[i].LastTransitionTime = transition
+	}
+	broken := status(validation.Problems{Keys: []string{"bad key"}}, 2, valid)
+	assert.Equal(t, int64(2), broken.ObservedGeneration)
+	assert.Equal(t, []metav1.Condition{
+		{Type: policyv1.ConditionValid, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: "KeysNotParsed", Message: "bad key", LastTransitionTime: broken.Conditions[0].LastTransitionTime},
+		{Type: policyv1.ConditionSecretsResolved, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: "Resolved", LastTransitionTime: transition},
+		{Type: policyv1.ConditionKeysParsed, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: "NotParsed", Message: "bad key", LastTransitionTime: broken.Conditions[2].LastTransitionTime},
+	}, broken.Conditions)
+	assert.NotEqual(t, transition, broken.Conditions[0].LastTransitionTime)
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+	"fmt"
+
+	policyclientset "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned"
+	informers "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/informers/externalversions"
+	listersv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/listers/portieris.cloud.ibm.com/v1"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/policy/validation"
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// policyKey identifies an ImagePolicy, or a ClusterImagePolicy when namespace is empty
+type policyKey struct {
+	namespace string
+	name      string
+}
+
+func (k policyKey) String() string {
+	if k.namespace == "" {
+		return fmt.Sprintf("ClusterImagePolicy %s", k.name)
+	}
+	return fmt.Sprintf("ImagePolicy %s/%s", k.namespace, k.name)
+}
+
+// Controller maintains the status of ImagePolicy and ClusterImagePolicy resources, so that a broken policy is
+// reported before deployments are denied by it
+type Controller struct {
+	checker              validation.Checker
+	policyClientset      policyclientset.Interface
+	imagePolicies        listersv1.ImagePolicyLister
+	clusterImagePolicies listersv1.ClusterImagePolicyLister
+	synced               []cache.InformerSynced
+	queue                workqueue.TypedRateLimitingInterface[policyKey]
+}
+
+// NewController creates a status controller that watches policies with the informers from informerFactory,
+// which must be started before Run. Secrets are not watched, they are checked again at each informer resync.
+func NewController(kubeWrapper kubernetes.WrapperInterface, policyClientset policyclientset.Interface, informerFactory informers.SharedInformerFactory) (*Controller, error) {
+	imagePolicies := informerFactory.Portieris().V1().ImagePolicies()
+	clusterImagePolicies := informerFactory.Portieris().V1().ClusterImagePolicies()
+	profiles := informerFactory.Portieris().V1().ImagePolicyProfiles()
+	c := &Controller{
+		checker:              validation.NewChecker(kubeWrapper, profiles.Lister()),
+		policyClientset:      policyClientset,
+		imagePolicies:        imagePolicies.Lister(),
+		clusterImagePolicies: clusterImagePolicies.Lister(),
+		synced: []cache.InformerSynced{
+			imagePolicies.Informer().HasSynced,
+			clusterImagePolicies.Informer().HasSynced,
+			profiles.Informer().HasSynced,
+		},
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[policyKey](), workqueue.TypedRateLimitingQueueConfig[policyKey]{Name: "policy-status"}),
+	}
+
+	enqueue := func(obj interface{}) {
+		switch policy := obj.(type) {
+		case *policyv1.ImagePolicy:
+			c.queue.Add(policyKey{namespace: policy.Namespace, name: policy.Name})
+		case *policyv1.ClusterImagePolicy:
+			c.queue.Add(policyKey{name: policy.Name})
+		}
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, obj interface{}) { enqueue(obj) },
+	}
+	if _, err := imagePolicies.Informer().AddEventHandler(handler); err != nil {
+		return nil, err
+	}
+	if _, err := clusterImagePolicies.Informer().AddEventHandler(handler); err != nil {
+		return nil, err
+	}
+	// a change to a profile can break or fix any policy that references it
+	enqueueAll := func(interface{}) { c.enqueueAll() }
+	if _, err := profiles.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueueAll,
+		UpdateFunc: func(_, obj interface{}) { enqueueAll(obj) },
+		DeleteFunc: enqueueAll,
+	}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Controller) enqueueAll() {
+	if policies, err := c.imagePolicies.List(labels.Everything()); err == nil {
+		for _, policy := range policies {
+			c.queue.Add(policyKey{namespace: policy.Namespace, name: policy.Name})
+		}
+	}
+	if policies, err := c.clusterImagePolicies.List(labels.Everything()); err == nil {
+		for _, policy := range policies {
+			c.queue.Add(policyKey{name: policy.Name})
+		}
+	}
+}
+
+// Run updates the status of policies until stopCh is closed
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+	if !cache.WaitForCacheSync(stopCh, c.synced...) {
+		glog.Error("Policy status controller: timed out waiting for caches to sync")
+		return
+	}
+	go func() {
+		for c.processNextItem() {
+		}
+	}()
+	<-stopCh
+}
+
+func (c *Controller) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+	if err := c.sync(key); err != nil {
+		if !errors.IsConflict(err) {
+			glog.Errorf("Unable to update the status of %s: %v", key, err)
+		}
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// sync checks a policy and updates its status when it has changed
+func (c *Controller) sync(key policyKey) error {
+	if key.namespace == "" {
+		policy, err := c.clusterImagePolicies.Get(key.name)
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		status := status(c.checker.Check("", policy.Spec), policy.Generation, policy.Status)
+		if equality.Semantic.DeepEqual(status, policy.Status) {
+			return nil
+		}
+		policy = policy.DeepCopy()
+		policy.Status = status
+		_, err = c.policyClientset.PortierisV1().ClusterImagePolicies().UpdateStatus(context.TODO(), policy, metav1.UpdateOptions{})
+		return err
+	}
+
+	policy, err := c.imagePolicies.ImagePolicies(key.namespace).Get(key.name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	status := status(c.checker.Check(key.namespace, policy.Spec), policy.Generation, policy.Status)
+	if equality.Semantic.DeepEqual(status, policy.Status) {
+		return nil
+	}
+	policy = policy.DeepCopy()
+	policy.Status = status
+	_, err = c.policyClientset.PortierisV1().ImagePolicies(key.namespace).UpdateStatus(context.TODO(), policy, metav1.UpdateOptions{})
+	return err
+}
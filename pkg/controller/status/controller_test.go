@@ -0,0 +1,75 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	policyclientsetfake "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned/fake"
+	informers "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/informers/externalversions"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestController(t *testing.T) {
+	imagePolicy := &policyv1.ImagePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "signed", Namespace: "team-a", Generation: 3},
+		Spec: policyv1.ImagePolicySpec{Repositories: []policyv1.Repository{{
+			Name:   "icr.io/*",
+			Policy: policyv1.Policy{Trust: policyv1.Trust{SignerSecrets: []policyv1.TrustSigner{{Name: "missing"}}}},
+		}}},
+	}
+	clusterImagePolicy := &policyv1.ClusterImagePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Generation: 1},
+		Spec:       policyv1.ImagePolicySpec{Repositories: []policyv1.Repository{{Name: "*"}}},
+	}
+	policyClientset := policyclientsetfake.NewSimpleClientset(imagePolicy, clusterImagePolicy)
+	kubeWrapper := kubernetes.NewKubeClientsetWrapper(k8sfake.NewSimpleClientset())
+	informerFactory := informers.NewSharedInformerFactory(policyClientset, 0)
+	c, err := NewController(kubeWrapper, policyClientset, informerFactory)
+	require.NoError(t, err)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informerFactory.Start(stopCh)
+	go c.Run(stopCh)
+
+	assert.Eventually(t, func() bool {
+		policy, err := policyClientset.PortierisV1().ImagePolicies("team-a").Get(context.TODO(), "signed", metav1.GetOptions{})
+		return err == nil && policy.Status.ObservedGeneration == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	policy, err := policyClientset.PortierisV1().ImagePolicies("team-a").Get(context.TODO(), "signed", metav1.GetOptions{})
+	require.NoError(t, err)
+	valid := meta.FindStatusCondition(policy.Status.Conditions, policyv1.ConditionValid)
+	if assert.NotNil(t, valid) {
+		assert.Equal(t, metav1.ConditionFalse, valid.Status)
+		assert.Equal(t, "SecretsNotResolved", valid.Reason)
+		assert.Equal(t, `repository "icr.io/*": signer secret: secrets "missing" not found`, valid.Message)
+	}
+	assert.True(t, meta.IsStatusConditionFalse(policy.Status.Conditions, policyv1.ConditionSecretsResolved))
+	assert.True(t, meta.IsStatusConditionTrue(policy.Status.Conditions, policyv1.ConditionKeysParsed))
+
+	assert.Eventually(t, func() bool {
+		policy, err := policyClientset.PortierisV1().ClusterImagePolicies().Get(context.TODO(), "default", metav1.GetOptions{})
+		return err == nil && meta.IsStatusConditionTrue(policy.Status.Conditions, policyv1.ConditionValid)
+	}, 5*time.Second, 10*time.Millisecond)
+}
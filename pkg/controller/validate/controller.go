@@ -0,0 +1,97 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/policy/validation"
+	"github.com/IBM/portieris/pkg/webhook"
+	"github.com/golang/glog"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// Controller validates ImagePolicy, ClusterImagePolicy and ImagePolicyException resources when they are created or
+// updated, so that mistakes are reported to the author of the policy rather than as denials of the workloads that it
+// applies to, and exceptions can not outlive the maximum lifetime
+type Controller struct {
+	checker              validation.Checker
+	maxExceptionLifetime time.Duration
+}
+
+// NewController creates a validating controller that finds problems in policies with checker, and denies exceptions
+// that expire more than maxExceptionLifetime after they are created
+func NewController(checker validation.Checker, maxExceptionLifetime time.Duration) *Controller {
+	return &Controller{
+		checker:              checker,
+		maxExceptionLifetime: maxExceptionLifetime,
+	}
+}
+
+// Admit denies a policy with problems in its spec or that references secrets that are missing, problems with
+// the keys in those secrets are returned as warnings
+func (c *Controller) Admit(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	glog.Infof("Processing admission request for %s on %s %s", admissionRequest.Operation, admissionRequest.Kind.Kind, admissionRequest.Name)
+	a := &webhook.AdmissionResponder{}
+
+	var namespace string
+	var spec policyv1.ImagePolicySpec
+	switch admissionRequest.Kind.Kind {
+	case "ImagePolicy":
+		var policy policyv1.ImagePolicy
+		if err := json.Unmarshal(admissionRequest.Object.Raw, &policy); err != nil {
+			a.ToAdmissionResponse(fmt.Errorf("unable to decode ImagePolicy: %v", err))
+			return a.Flush()
+		}
+		namespace = admissionRequest.Namespace
+		spec = policy.Spec
+	case "ClusterImagePolicy":
+		var policy policyv1.ClusterImagePolicy
+		if err := json.Unmarshal(admissionRequest.Object.Raw, &policy); err != nil {
+			a.ToAdmissionResponse(fmt.Errorf("unable to decode ClusterImagePolicy: %v", err))
+			return a.Flush()
+		}
+		spec = policy.Spec
+	case "ImagePolicyException":
+		var exception policyv1.ImagePolicyException
+		if err := json.Unmarshal(admissionRequest.Object.Raw, &exception); err != nil {
+			a.ToAdmissionResponse(fmt.Errorf("unable to decode ImagePolicyException: %v", err))
+			return a.Flush()
+		}
+		a.StringsToAdmissionResponse(validation.CheckException(exception, c.maxExceptionLifetime, time.Now()))
+		if !a.HasErrors() {
+			a.SetAllowed()
+		}
+		return a.Flush()
+	default:
+		a.SetAllowed()
+		return a.Flush()
+	}
+
+	problems := c.checker.Check(namespace, spec)
+	a.StringsToAdmissionResponse(problems.Spec)
+	a.StringsToAdmissionResponse(problems.Secrets)
+	for _, problem := range problems.Keys {
+		a.AddWarning(problem)
+	}
+	if !a.HasErrors() {
+		a.SetAllowed()
+	}
+	return a.Flush()
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/policy/validation"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+type noProfiles struct{}
+
+func (noProfiles) Get(name string) (*policyv1.ImagePolicyProfile, error) {
+	return nil, assert.AnError
+}
+
+func TestController_Admit(t *testing.T) {
+	signedBy := func(keySecret string) policyv1.ImagePolicySpec {
+		return policyv1.ImagePolicySpec{Repositories: []policyv1.Repository{{
+			Name: "icr.io/*",
+			Policy: policyv1.Policy{Simple: policyv1.Simple{Requirements: []policyv1.SimpleRequirement{
+				{Type: "signedBy", KeySecret: keySecret},
+			}}},
+		}}}
+	}
+	tests := []struct {
+		name         string
+		kind         string
+		spec         policyv1.ImagePolicySpec
+		wantAllowed  bool
+		wantMessage  string
+		wantWarnings []string
+	}{
+		{
+			name:        "valid ImagePolicy",
+			kind:        "ImagePolicy",
+			spec:        policyv1.ImagePolicySpec{Repositories: []policyv1.Repository{{Name: "icr.io/*"}}},
+			wantAllowed: true,
+		},
+		{
+			name:        "unknown requirement type",
+			kind:        "ImagePolicy",
+			spec:        policyv1.ImagePolicySpec{Repositories: []policyv1.Repository{{Name: "icr.io/*", Policy: policyv1.Policy{Simple: policyv1.Simple{Requirements: []policyv1.SimpleRequirement{{Type: "signed"}}}}}}},
+			wantMessage: "\n" + `repository "icr.io/*": simple requirement type "signed" is invalid`,
+		},
+		{
+			name:        "missing key secret",
+			kind:        "ImagePolicy",
+			spec:        signedBy("missing"),
+			wantMessage: "\n" + `repository "icr.io/*": key secret: secrets "missing" not found`,
+		},
+		{
+			name:         "key that can't be parsed is a warning",
+			kind:         "ImagePolicy",
+			spec:         signedBy("bad-key"),
+			wantAllowed:  true,
+			wantWarnings: []string{`repository "icr.io/*": key secret "bad-key": Unable to decode key: EOF`},
+		},
+		{
+			name:        "ClusterImagePolicy with an empty repository name",
+			kind:        "ClusterImagePolicy",
+			spec:        policyv1.ImagePolicySpec{Repositories: []policyv1.Repository{{Name: "*"}, {}}},
+			wantMessage: "\nrepository 1: name is empty",
+		},
+		{
+			name:        "ClusterImagePolicy does not check secrets in workload namespaces",
+			kind:        "ClusterImagePolicy",
+			spec:        signedBy("missing"),
+			wantAllowed: true,
+		},
+		{
+			name:        "other kinds are allowed",
+			kind:        "ImagePolicyProfile",
+			wantAllowed: true,
+		},
+	}
+	kubeWrapper := kubernetes.NewKubeClientsetWrapper(k8sfake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bad-key", Namespace: "team-a"},
+		Data:       map[string][]byte{"key": []byte("not a key")},
+	}))
+	c := NewController(validation.NewChecker(kubeWrapper, noProfiles{}), policyv1.DefaultMaxExceptionLifetime)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(map[string]interface{}{"spec": tt.spec})
+			assert.NoError(t, err)
+			resp := c.Admit(context.Background(), &admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Group: "portieris.cloud.ibm.com", Version: "v1", Kind: tt.kind},
+				Namespace: "team-a",
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			assert.Equal(t, tt.wantAllowed, resp.Allowed)
+			if !tt.wantAllowed {
+				assert.Equal(t, tt.wantMessage, resp.Result.Message)
+			}
+			assert.Equal(t, tt.wantWarnings, resp.Warnings)
+		})
+	}
+}
+
+func TestController_AdmitException(t *testing.T) {
+	now := time.Now()
+	exception := func(created, expiresAt time.Time) policyv1.ImagePolicyException {
+		return policyv1.ImagePolicyException{
+			ObjectMeta: metav1.ObjectMeta{Name: "hotfix", Namespace: "team-a", CreationTimestamp: metav1.NewTime(created)},
+			Spec: policyv1.ImagePolicyExceptionSpec{
+				Image:     "icr.io/team-a/app",
+				ExpiresAt: metav1.NewTime(expiresAt),
+				Reason:    "INC-1234",
+				Approver:  "oncall@example.com",
+			},
+		}
+	}
+	tooLong := exception(time.Time{}, now.Add(100*time.Hour))
+	extended := exception(now.Add(-48*time.Hour), now.Add(48*time.Hour))
+	incomplete := exception(time.Time{}, now.Add(time.Hour))
+	incomplete.Spec.Reason = ""
+	incomplete.Spec.Approver = ""
+	tests := []struct {
+		name        string
+		exception   policyv1.ImagePolicyException
+		wantAllowed bool
+		wantMessage string
+	}{
+		{
+			name:        "exception within the maximum lifetime",
+			exception:   exception(time.Time{}, now.Add(time.Hour)),
+			wantAllowed: true,
+		},
+		{
+			name:        "exception that expires after the maximum lifetime",
+			exception:   tooLong,
+			wantMessage: "\nexpiresAt " + tooLong.Spec.ExpiresAt.UTC().Format(time.RFC3339) + " is more than 72h0m0s after the exception was created",
+		},
+		{
+			name:        "exception that is extended past the maximum lifetime",
+			exception:   extended,
+			wantMessage: "\nexpiresAt " + extended.Spec.ExpiresAt.UTC().Format(time.RFC3339) + " is more than 72h0m0s after the exception was created",
+		},
+		{
+			name:        "exception without a reason or approver",
+			exception:   incomplete,
+			wantMessage: "\napprover is required\nreason is required",
+		},
+	}
+	c := NewController(validation.NewChecker(kubernetes.NewKubeClientsetWrapper(k8sfake.NewSimpleClientset()), noProfiles{}), policyv1.DefaultMaxExceptionLifetime)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(tt.exception)
+			assert.NoError(t, err)
+			resp := c.Admit(context.Background(), &admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Group: "portieris.cloud.ibm.com", Version: "v1", Kind: "ImagePolicyException"},
+				Namespace: "team-a",
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			assert.Equal(t, tt.wantAllowed, resp.Allowed)
+			if !tt.wantAllowed {
+				assert.Equal(t, tt.wantMessage, resp.Result.Message)
+			}
+		})
+	}
+}
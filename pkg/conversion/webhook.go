@@ -0,0 +1,66 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conversion points the conversion webhook of the policy CustomResourceDefinitions at Portieris
+package conversion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientv1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Path is where the conversion webhook is served
+const Path = "/convert"
+
+// CustomResourceDefinitions are the policy resources served in more than one version
+var CustomResourceDefinitions = []string{
+	"imagepolicies.portieris.cloud.ibm.com",
+	"clusterimagepolicies.portieris.cloud.ibm.com",
+}
+
+// ConfigureWebhook points the conversion webhook of each of the CustomResourceDefinitions at the
+// service, trusting caBundle, the CRDs are installed without it because the chart can not template them
+func ConfigureWebhook(client apiextensionsclientv1.CustomResourceDefinitionsGetter, namespace, service string, port int32, caBundle []byte) error {
+	path := Path
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"conversion": apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						Service:  &apiextensionsv1.ServiceReference{Namespace: namespace, Name: service, Path: &path, Port: &port},
+						CABundle: caBundle,
+					},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+		},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	for _, name := range CustomResourceDefinitions {
+		if _, err := client.CustomResourceDefinitions().Patch(context.TODO(), name, types.MergePatchType, data, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return nil
+}
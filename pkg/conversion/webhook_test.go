@@ -0,0 +1,83 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func crd(name string, conversion *apiextensionsv1.CustomResourceConversion) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Conversion: conversion},
+	}
+}
+
+func TestConfigureWebhook(t *testing.T) {
+	t.Run("points every CRD at the service", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			crd("imagepolicies.portieris.cloud.ibm.com", &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}),
+			crd("clusterimagepolicies.portieris.cloud.ibm.com", nil),
+		)
+		require.NoError(t, ConfigureWebhook(client.ApiextensionsV1(), "security", "portieris", 443, []byte("ca")))
+
+		for _, name := range CustomResourceDefinitions {
+			got, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), name, metav1.GetOptions{})
+			require.NoError(t, err)
+			conversion := got.Spec.Conversion
+			assert.Equal(t, apiextensionsv1.WebhookConverter, conversion.Strategy)
+			assert.Equal(t, []string{"v1"}, conversion.Webhook.ConversionReviewVersions)
+			service := conversion.Webhook.ClientConfig.Service
+			assert.Equal(t, "security", service.Namespace)
+			assert.Equal(t, "portieris", service.Name)
+			assert.Equal(t, "/convert", *service.Path)
+			assert.Equal(t, int32(443), *service.Port)
+			assert.Equal(t, []byte("ca"), conversion.Webhook.ClientConfig.CABundle)
+		}
+	})
+
+	t.Run("keeps an injected CA when none is given", func(t *testing.T) {
+		injected := &apiextensionsv1.CustomResourceConversion{
+			Strategy: apiextensionsv1.WebhookConverter,
+			Webhook: &apiextensionsv1.WebhookConversion{
+				ClientConfig:             &apiextensionsv1.WebhookClientConfig{CABundle: []byte("injected")},
+				ConversionReviewVersions: []string{"v1"},
+			},
+		}
+		client := fake.NewSimpleClientset(
+			crd("imagepolicies.portieris.cloud.ibm.com", injected),
+			crd("clusterimagepolicies.portieris.cloud.ibm.com", injected.DeepCopy()),
+		)
+		require.NoError(t, ConfigureWebhook(client.ApiextensionsV1(), "portieris", "portieris", 443, nil))
+
+		got, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), "imagepolicies.portieris.cloud.ibm.com", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("injected"), got.Spec.Conversion.Webhook.ClientConfig.CABundle)
+		assert.Equal(t, "portieris", got.Spec.Conversion.Webhook.ClientConfig.Service.Name)
+	})
+
+	t.Run("a missing CRD is an error", func(t *testing.T) {
+		client := fake.NewSimpleClientset(crd("imagepolicies.portieris.cloud.ibm.com", nil))
+		err := ConfigureWebhook(client.ApiextensionsV1(), "portieris", "portieris", 443, nil)
+		assert.EqualError(t, err, `clusterimagepolicies.portieris.cloud.ibm.com: customresourcedefinitions.apiextensions.k8s.io "clusterimagepolicies.portieris.cloud.ibm.com" not found`)
+	})
+}
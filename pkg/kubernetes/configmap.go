@@ -0,0 +1,52 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetConfigMapData returns the value of a key in a ConfigMap
+func (w *Wrapper) GetConfigMapData(namespace, name, key string) (string, error) {
+	configMap, err := w.getConfigMap(namespace, name)
+	if err != nil {
+		return "", err
+	}
+	data, ok := configMap.Data[key]
+	if !ok {
+		return "", fmt.Errorf("ConfigMap %s/%s has no key %q", namespace, name, key)
+	}
+	return data, nil
+}
+
+// getConfigMap retrieves the named ConfigMap from the informer cache, or from the API server when it is not cached
+func (w *Wrapper) getConfigMap(namespace, name string) (*corev1.ConfigMap, error) {
+	if w.configMaps != nil && w.configMapsSynced() {
+		configMap, err := w.configMaps.ConfigMaps(namespace).Get(name)
+		if err == nil {
+			return configMap, nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		// The ConfigMap may be too new to be cached
+	}
+	return w.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWrapper_GetConfigMapData(t *testing.T) {
+	kubeClientset := k8sfake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rules",
+			Namespace: "portieris",
+		},
+		Data: map[string]string{"policy.rego": "package portieris"},
+	})
+	w := NewKubeClientsetWrapper(kubeClientset)
+
+	data, err := w.GetConfigMapData("portieris", "rules", "policy.rego")
+	assert.NoError(t, err)
+	assert.Equal(t, "package portieris", data)
+
+	_, err = w.GetConfigMapData("portieris", "rules", "missing.rego")
+	assert.EqualError(t, err, `ConfigMap portieris/rules has no key "missing.rego"`)
+
+	_, err = w.GetConfigMapData("portieris", "missing", "policy.rego")
+	assert.Error(t, err)
+}
+
+func TestWrapper_GetConfigMapDataCached(t *testing.T) {
+	kubeClientset := k8sfake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "rules", Namespace: "portieris"},
+		Data:       map[string]string{"policy.rego": "package portieris"},
+	})
+	factory := kubeinformers.NewSharedInformerFactory(kubeClientset, 0)
+	w := NewKubeClientsetWrapper(kubeClientset).WithConfigMaps(factory.Core().V1().ConfigMaps())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+	kubeClientset.ClearActions()
+
+	data, err := w.GetConfigMapData("portieris", "rules", "policy.rego")
+	assert.NoError(t, err)
+	assert.Equal(t, "package portieris", data)
+	assert.Empty(t, kubeClientset.Actions(), "the ConfigMap is read from the informer cache")
+
+	_, err = w.GetConfigMapData("portieris", "missing", "policy.rego")
+	assert.Error(t, err)
+	assert.Len(t, kubeClientset.Actions(), 1, "a ConfigMap that is not cached is read from the API server")
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWrapper_GetNamespaceLabels(t *testing.T) {
+	kubeClientset := k8sfake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "prod",
+			Labels: map[string]string{"env": "prod"},
+		},
+	})
+	w := NewKubeClientsetWrapper(kubeClientset)
+
+	labels, err := w.GetNamespaceLabels("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod"}, labels)
+
+	_, err = w.GetNamespaceLabels("missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodePlatformsTTL is how long the platforms of the cluster nodes are reused, nodes are added and removed far less
+// often than pods are admitted
+const nodePlatformsTTL = time.Minute
+
+// nodePlatforms holds the platforms of the cluster nodes until they expire
+type nodePlatforms struct {
+	mutex     sync.Mutex
+	platforms []string
+	expires   time.Time
+}
+
+// GetNodePlatforms returns the distinct os/architecture platforms of the cluster nodes, sorted. The nodes are
+// listed at most once every nodePlatformsTTL, and concurrent callers wait for the same list.
+func (w *Wrapper) GetNodePlatforms(ctx context.Context) ([]string, error) {
+	w.nodes.mutex.Lock()
+	defer w.nodes.mutex.Unlock()
+	if w.nodes.platforms == nil || !time.Now().Before(w.nodes.expires) {
+		platforms, err := w.listNodePlatforms(ctx)
+		if err != nil {
+			return nil, err
+		}
+		w.nodes.platforms = platforms
+		w.nodes.expires = time.Now().Add(nodePlatformsTTL)
+	}
+	return append([]string{}, w.nodes.platforms...), nil
+}
+
+// listNodePlatforms lists the nodes and returns their distinct platforms, sorted
+func (w *Wrapper) listNodePlatforms(ctx context.Context) ([]string, error) {
+	nodes, err := w.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	platforms := []string{}
+	for _, node := range nodes.Items {
+		platform := nodePlatform(node)
+		if platform != "" && !seen[platform] {
+			seen[platform] = true
+			platforms = append(platforms, platform)
+		}
+	}
+	sort.Strings(platforms)
+	return platforms, nil
+}
+
+// nodePlatform uses the well known labels, falling back to the node info reported by the kubelet
+func nodePlatform(node corev1.Node) string {
+	os, arch := node.Labels[corev1.LabelOSStable], node.Labels[corev1.LabelArchStable]
+	if os == "" {
+		os = node.Status.NodeInfo.OperatingSystem
+	}
+	if arch == "" {
+		arch = node.Status.NodeInfo.Architecture
+	}
+	if os == "" || arch == "" {
+		return ""
+	}
+	return os + "/" + arch
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func createNode(name string, labels map[string]string, os, arch string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{
+				OperatingSystem: os,
+				Architecture:    arch,
+			},
+		},
+	}
+}
+
+func TestWrapper_GetNodePlatforms(t *testing.T) {
+	tests := []struct {
+		name          string
+		nodes         []runtime.Object
+		wantPlatforms []string
+	}{
+		{
+			name:          "no nodes",
+			wantPlatforms: []string{},
+		},
+		{
+			name: "distinct platforms are sorted",
+			nodes: []runtime.Object{
+				createNode("a", map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "s390x"}, "", ""),
+				createNode("b", map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "amd64"}, "", ""),
+				createNode("c", map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "amd64"}, "", ""),
+			},
+			wantPlatforms: []string{"linux/amd64", "linux/s390x"},
+		},
+		{
+			name: "node info is used without labels",
+			nodes: []runtime.Object{
+				createNode("a", nil, "linux", "arm64"),
+				createNode("b", nil, "", ""),
+			},
+			wantPlatforms: []string{"linux/arm64"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := k8sfake.NewSimpleClientset(tt.nodes...)
+			w := NewKubeClientsetWrapper(kubeClientset)
+			platforms, err := w.GetNodePlatforms(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPlatforms, platforms)
+		})
+	}
+}
+
+func TestWrapper_GetNodePlatformsCached(t *testing.T) {
+	kubeClientset := k8sfake.NewSimpleClientset(createNode("a", map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "amd64"}, "", ""))
+	w := NewKubeClientsetWrapper(kubeClientset)
+	lists := func() int {
+		n := 0
+		for _, action := range kubeClientset.Actions() {
+			if action.Matches("list", "nodes") {
+				n++
+			}
+		}
+		return n
+	}
+
+	platforms, err := w.GetNodePlatforms(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"linux/amd64"}, platforms)
+	_, err = kubeClientset.CoreV1().Nodes().Create(context.Background(), createNode("b", map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "s390x"}, "", ""), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	platforms, err = w.GetNodePlatforms(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"linux/amd64"}, platforms, "platforms are reused until they expire")
+	assert.Equal(t, 1, lists())
+
+	w.nodes.expires = time.Now().Add(-time.Second)
+	platforms, err = w.GetNodePlatforms(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"linux/amd64", "linux/s390x"}, platforms)
+	assert.Equal(t, 2, lists())
+}
@@ -15,7 +15,6 @@
 package kubernetes
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/golang/glog"
@@ -58,7 +57,17 @@ var supportedKinds = map[string]struct{}{
 
 // GetPodSpec retrieves the podspec from the admission request passed in
 func (w *Wrapper) GetPodSpec(ar *admissionv1.AdmissionRequest) (string, *corev1.PodSpec, error) {
-	ps := corev1.PodSpec{}
+	templateString, pt, err := w.GetPodTemplate(ar)
+	if err != nil {
+		return "", nil, err
+	}
+	return templateString, &pt.Spec, nil
+}
+
+// GetPodTemplate retrieves the pod template, metadata and podspec, from the admission request passed in.
+// For a pod the metadata is that of the pod itself.
+func (w *Wrapper) GetPodTemplate(ar *admissionv1.AdmissionRequest) (string, *corev1.PodTemplateSpec, error) {
+	pt := corev1.PodTemplateSpec{}
 	var templateString string
 
 	switch ar.Resource {
@@ -67,150 +76,150 @@ func (w *Wrapper) GetPodSpec(ar *admissionv1.AdmissionRequest) (string, *corev1.
 		if err := w.decodeObject(ar.Object.Raw, &pod); err != nil {
 			return "", nil, err
 		}
-		ps = pod.Spec
+		pt = corev1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec}
 		templateString = podSpecPath
 	case metav1.GroupVersionResource{Group: "", Version: "v1", Resource: "replicationcontrollers"}:
 		rc := corev1.ReplicationController{}
 		if err := w.decodeObject(ar.Object.Raw, &rc); err != nil {
 			return "", nil, err
 		}
-		ps = rc.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = *rc.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "deployments"}:
 		deploy := extensionsv1beta1.Deployment{}
 		if err := w.decodeObject(ar.Object.Raw, &deploy); err != nil {
 			return "", nil, err
 		}
-		ps = deploy.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = deploy.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1beta1", Resource: "deployments"}:
 		deploy := appsv1beta1.Deployment{}
 		if err := w.decodeObject(ar.Object.Raw, &deploy); err != nil {
 			return "", nil, err
 		}
-		ps = deploy.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = deploy.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1beta2", Resource: "deployments"}:
 		deploy := appsv1beta2.Deployment{}
 		if err := w.decodeObject(ar.Object.Raw, &deploy); err != nil {
 			return "", nil, err
 		}
-		ps = deploy.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = deploy.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}:
 		deploy := appsv1.Deployment{}
 		if err := w.decodeObject(ar.Object.Raw, &deploy); err != nil {
 			return "", nil, err
 		}
-		ps = deploy.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = deploy.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}:
 		rs := appsv1.ReplicaSet{}
 		if err := w.decodeObject(ar.Object.Raw, &rs); err != nil {
 			return "", nil, err
 		}
-		ps = rs.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = rs.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "replicasets"}:
 		rs := extensionsv1beta1.ReplicaSet{}
 		if err := w.decodeObject(ar.Object.Raw, &rs); err != nil {
 			return "", nil, err
 		}
-		ps = rs.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = rs.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1beta2", Resource: "replicasets"}:
 		rs := appsv1beta2.ReplicaSet{}
 		if err := w.decodeObject(ar.Object.Raw, &rs); err != nil {
 			return "", nil, err
 		}
-		ps = rs.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = rs.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}:
 		ds := appsv1.DaemonSet{}
 		if err := w.decodeObject(ar.Object.Raw, &ds); err != nil {
 			return "", nil, err
 		}
-		ps = ds.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = ds.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "daemonsets"}:
 		ds := extensionsv1beta1.DaemonSet{}
 		if err := w.decodeObject(ar.Object.Raw, &ds); err != nil {
 			return "", nil, err
 		}
-		ps = ds.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = ds.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1beta2", Resource: "daemonsets"}:
 		ds := appsv1beta2.DaemonSet{}
 		if err := w.decodeObject(ar.Object.Raw, &ds); err != nil {
 			return "", nil, err
 		}
-		ps = ds.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = ds.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}:
 		sts := appsv1.StatefulSet{}
 		if err := w.decodeObject(ar.Object.Raw, &sts); err != nil {
 			return "", nil, err
 		}
-		ps = sts.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = sts.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1beta1", Resource: "statefulsets"}:
 		sts := appsv1beta1.StatefulSet{}
 		if err := w.decodeObject(ar.Object.Raw, &sts); err != nil {
 			return "", nil, err
 		}
-		ps = sts.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = sts.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "apps", Version: "v1beta2", Resource: "statefulsets"}:
 		sts := appsv1beta2.StatefulSet{}
 		if err := w.decodeObject(ar.Object.Raw, &sts); err != nil {
 			return "", nil, err
 		}
-		ps = sts.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = sts.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}:
 		job := batchv1.Job{}
 		if err := w.decodeObject(ar.Object.Raw, &job); err != nil {
 			return "", nil, err
 		}
-		ps = job.Spec.Template.Spec
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = job.Spec.Template
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = templateSpecPath
 	case metav1.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}:
 		job := batchv1.CronJob{}
 		if err := w.decodeObject(ar.Object.Raw, &job); err != nil {
 			return "", nil, err
 		}
-		ps = job.Spec.JobTemplate.Spec.Template.Spec //:sob:
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = job.Spec.JobTemplate.Spec.Template //:sob:
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = cronJobSpecPath
 	case metav1.GroupVersionResource{Group: "batch", Version: "v1beta1", Resource: "cronjobs"}:
 		job := batchv1beta1.CronJob{}
 		if err := w.decodeObject(ar.Object.Raw, &job); err != nil {
 			return "", nil, err
 		}
-		ps = job.Spec.JobTemplate.Spec.Template.Spec //:sob:
-		w.mutateWithSA(ar.Namespace, &ps)
+		pt = job.Spec.JobTemplate.Spec.Template //:sob:
+		w.AddServiceAccountPullSecrets(ar.Namespace, &pt.Spec)
 		templateString = cronJobSpecPath
 	default:
 		glog.Errorf("Resource not supported: %+v", ar.Resource)
 		return "", nil, fmt.Errorf(`The resource "%s/%s/%s" is not supported. Make sure that you are using a supported kubectl version, and that you are using a supported Kubernetes workload type`, ar.Resource.Group, ar.Resource.Version, ar.Resource.Resource)
 	}
 
-	return templateString, &ps, nil
+	return templateString, &pt, nil
 }
 
 func (w *Wrapper) decodeObject(raw []byte, object object) error {
@@ -228,7 +237,9 @@ func (w *Wrapper) decodeObject(raw []byte, object object) error {
 	return nil
 }
 
-func (w *Wrapper) mutateWithSA(ns string, ps *corev1.PodSpec) error {
+// AddServiceAccountPullSecrets adds the image pull secrets of the pod's service account to a pod spec that has none,
+// as the ServiceAccount admission controller does when the pods of a workload are created
+func (w *Wrapper) AddServiceAccountPullSecrets(ns string, ps *corev1.PodSpec) error {
 	if ns == "" || ps == nil || len(ps.ImagePullSecrets) != 0 {
 		// Do nothing
 		return nil
@@ -238,7 +249,7 @@ func (w *Wrapper) mutateWithSA(ns string, ps *corev1.PodSpec) error {
 	if ps.ServiceAccountName != "" {
 		name = ps.ServiceAccountName
 	}
-	sa, err := w.CoreV1().ServiceAccounts(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	sa, err := w.GetServiceAccount(ns, name)
 	if err != nil {
 		return err
 	}
@@ -685,7 +685,105 @@ func TestWrapper_GetPodSpec(t *testing.T) {
 	}
 }
 
-func TestWrapper_mutateWithSA(t *testing.T) {
+func TestWrapper_GetPodTemplate(t *testing.T) {
+	nginxSpec := corev1.PodSpec{
+		ServiceAccountName: "web",
+		Containers: []corev1.Container{
+			{
+				Name:  "nginx",
+				Image: "docker.io/nginx",
+			},
+		},
+	}
+
+	type ar struct {
+		Object    []byte
+		Namespace string
+		Resource  metav1.GroupVersionResource
+	}
+	tests := []struct {
+		name    string
+		ar      ar
+		want    string
+		want1   *corev1.PodTemplateSpec
+		wantErr bool
+	}{
+		{
+			name: "Returns the metadata of a pod",
+			ar: ar{
+				Object:    []byte(`{"metadata":{"name":"nginx","namespace":"default","labels":{"exposure":"internet"}},"spec":{"serviceAccountName":"web","containers":[{"name":"nginx","image":"docker.io/nginx"}]}}`),
+				Namespace: "",
+				Resource:  metav1.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+			},
+			want: "/spec",
+			want1: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", Labels: map[string]string{"exposure": "internet"}},
+				Spec:       nginxSpec,
+			},
+		},
+		{
+			name: "Returns the template metadata of a deployment",
+			ar: ar{
+				Object:    []byte(`{"metadata":{"name":"nginx","namespace":"default","labels":{"app":"deployment"}},"spec":{"template":{"metadata":{"labels":{"exposure":"internet"}},"spec":{"serviceAccountName":"web","containers":[{"name":"nginx","image":"docker.io/nginx"}]}}}}`),
+				Namespace: "",
+				Resource:  metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			},
+			want: "/spec/template/spec",
+			want1: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"exposure": "internet"}},
+				Spec:       nginxSpec,
+			},
+		},
+		{
+			name: "Returns the template metadata of a cronjob",
+			ar: ar{
+				Object:    []byte(`{"metadata":{"name":"nginx","namespace":"default"},"spec":{"jobTemplate":{"spec":{"template":{"metadata":{"labels":{"exposure":"batch"}},"spec":{"serviceAccountName":"web","containers":[{"name":"nginx","image":"docker.io/nginx"}]}}}}}}`),
+				Namespace: "",
+				Resource:  metav1.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"},
+			},
+			want: "/spec/jobTemplate/spec/template/spec",
+			want1: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"exposure": "batch"}},
+				Spec:       nginxSpec,
+			},
+		},
+		{
+			name: "Errors for an unsupported type",
+			ar: ar{
+				Object:    []byte(`{}`),
+				Namespace: "",
+				Resource:  metav1.GroupVersionResource{Group: "wibble", Version: "bibble", Resource: "tibble"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ar := &admissionv1.AdmissionRequest{
+				Resource:  tt.ar.Resource,
+				Namespace: tt.ar.Namespace,
+				Object: runtime.RawExtension{
+					Raw: tt.ar.Object,
+				},
+			}
+
+			w := &Wrapper{}
+
+			got, got1, err := w.GetPodTemplate(ar)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.want, got)
+					assert.Equal(t, tt.want1, got1)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapper_AddServiceAccountPullSecrets(t *testing.T) {
 	serviceaccounts := []runtime.Object{
 		&corev1.ServiceAccount{
 			ObjectMeta: metav1.ObjectMeta{
@@ -761,13 +859,16 @@ func TestWrapper_mutateWithSA(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			kubeClientset := k8sfake.NewSimpleClientset(serviceaccounts...)
-			w := NewKubeClientsetWrapper(kubeClientset)
-			err := w.mutateWithSA(tt.ns, tt.ps)
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
-				if assert.NoError(t, err) {
-					assert.Equal(t, tt.want, tt.ps)
+			cached, _ := setupInformers(t, "", "", serviceaccounts...)
+			for _, w := range []*Wrapper{NewKubeClientsetWrapper(kubeClientset), cached} {
+				ps := tt.ps.DeepCopy()
+				err := w.AddServiceAccountPullSecrets(tt.ns, ps)
+				if tt.wantErr {
+					assert.Error(t, err)
+				} else {
+					if assert.NoError(t, err) {
+						assert.Equal(t, tt.want, ps)
+					}
 				}
 			}
 		})
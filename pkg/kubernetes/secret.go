@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -22,6 +22,8 @@ import (
 	"strings"
 
 	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -41,10 +43,25 @@ type RegistryCredentials struct {
 	Auth     string `json:"auth"`
 }
 
+// GetSecret retrieves the named secret from the informer cache, or from the API server when it is not cached
+func (w *Wrapper) GetSecret(namespace, name string) (*corev1.Secret, error) {
+	if w.cached() {
+		secret, err := w.secrets.Secrets(namespace).Get(name)
+		if err == nil {
+			return secret.DeepCopy(), nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		// The secret may be outside the cached namespace or labels, or too new to be cached
+	}
+	return w.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
 // GetSecretKey obtains the "key" data from the named secret
 func (w *Wrapper) GetSecretKey(namespace, secretName string) ([]byte, error) {
 	// Retrieve secret
-	secret, err := w.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	secret, err := w.GetSecret(namespace, secretName)
 	if err != nil {
 		glog.Error("Error: ", err)
 		return nil, err
@@ -63,7 +80,7 @@ func (w *Wrapper) GetSecretToken(namespace, secretName, registry string) (string
 	var username, password string
 
 	// Retrieve secret
-	secret, err := w.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	secret, err := w.GetSecret(namespace, secretName)
 	if err != nil {
 		glog.Error("Error: ", err)
 		return username, password, err
@@ -139,7 +156,7 @@ func (w *Wrapper) GetBasicCredentials(namespace, name string) (string, string, e
 	}
 
 	// Retrieve secret
-	secret, err := w.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	secret, err := w.GetSecret(namespace, name)
 	if err != nil {
 		return "", "", err
 	}
@@ -1,4 +1,4 @@
-// Copyright 2018, 2020 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,13 +15,17 @@
 package kubernetes
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func createSecret(name, namespace, dataKey string, dataValue []byte) *corev1.Secret {
@@ -40,6 +44,133 @@ func createSecret(name, namespace, dataKey string, dataValue []byte) *corev1.Sec
 	}
 }
 
+// setupInformers creates a wrapper whose secret informer is restricted to namespace and labelSelector, and waits for its caches to sync
+func setupInformers(t *testing.T, namespace, labelSelector string, objects ...runtime.Object) (*Wrapper, *k8sfake.Clientset) {
+	kubeClientset := k8sfake.NewSimpleClientset(objects...)
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClientset, 0, informers.WithNamespace(namespace))
+	secretInformerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClientset, 0, informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) { options.LabelSelector = labelSelector }))
+	w := NewInformerKubeClientsetWrapper(kubeClientset, secretInformerFactory.Core().V1().Secrets(), informerFactory.Core().V1().ServiceAccounts())
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	informerFactory.Start(stopCh)
+	secretInformerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	secretInformerFactory.WaitForCacheSync(stopCh)
+	return w, kubeClientset
+}
+
+// countGets counts the requests to get a resource from the API server
+func countGets(kubeClientset *k8sfake.Clientset, resource string) *int {
+	gets := 0
+	kubeClientset.PrependReactor("get", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+	return &gets
+}
+
+func TestWrapper_GetSecret(t *testing.T) {
+	labelled := createSecret("labelled", "namespace", "key", []byte("labelled"))
+	labelled.Labels = map[string]string{"portieris.cloud.ibm.com/cache": "true"}
+	secrets := []runtime.Object{
+		labelled,
+		createSecret("name", "namespace", "key", []byte("name")),
+		createSecret("name", "other", "key", []byte("other")),
+	}
+	tests := []struct {
+		name            string
+		namespace       string
+		labelSelector   string
+		secretNamespace string
+		secretName      string
+		wantKey         string
+		wantGets        int
+		wantErr         bool
+	}{
+		{
+			name:            "reads a cached secret from the cache",
+			secretNamespace: "namespace",
+			secretName:      "name",
+			wantKey:         "name",
+		},
+		{
+			name:            "reads a secret outside the cached namespace from the API server",
+			namespace:       "namespace",
+			secretNamespace: "other",
+			secretName:      "name",
+			wantKey:         "other",
+			wantGets:        1,
+		},
+		{
+			name:            "reads a labelled secret from the cache",
+			labelSelector:   "portieris.cloud.ibm.com/cache=true",
+			secretNamespace: "namespace",
+			secretName:      "labelled",
+			wantKey:         "labelled",
+		},
+		{
+			name:            "reads a secret without the cached labels from the API server",
+			labelSelector:   "portieris.cloud.ibm.com/cache=true",
+			secretNamespace: "namespace",
+			secretName:      "name",
+			wantKey:         "name",
+			wantGets:        1,
+		},
+		{
+			name:            "error if secret not found",
+			secretNamespace: "namespace",
+			secretName:      "missing",
+			wantGets:        1,
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, kubeClientset := setupInformers(t, tt.namespace, tt.labelSelector, secrets...)
+			gets := countGets(kubeClientset, "secrets")
+			secret, err := w.GetSecret(tt.secretNamespace, tt.secretName)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantKey, string(secret.Data["key"]))
+			}
+			assert.Equal(t, tt.wantGets, *gets)
+		})
+	}
+
+	t.Run("reads from the API server until the caches have synced", func(t *testing.T) {
+		kubeClientset := k8sfake.NewSimpleClientset(secrets...)
+		informerFactory := informers.NewSharedInformerFactory(kubeClientset, 0)
+		w := NewInformerKubeClientsetWrapper(kubeClientset, informerFactory.Core().V1().Secrets(), informerFactory.Core().V1().ServiceAccounts())
+		assert.False(t, w.HasSynced())
+		gets := countGets(kubeClientset, "secrets")
+
+		_, err := w.GetSecret("namespace", "name")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *gets)
+	})
+
+	t.Run("cached secrets are read when the API server fails", func(t *testing.T) {
+		w, kubeClientset := setupInformers(t, "", "", secrets...)
+		kubeClientset.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("API server unavailable")
+		})
+
+		key, err := w.GetSecretKey("namespace", "name")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("name"), key)
+		}
+	})
+
+	t.Run("wrapper without informers has always synced", func(t *testing.T) {
+		w := NewKubeClientsetWrapper(k8sfake.NewSimpleClientset(secrets...))
+		assert.True(t, w.HasSynced())
+		_, err := w.GetSecret("namespace", "name")
+		assert.NoError(t, err)
+	})
+}
+
 func TestWrapper_GetSecretKey(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -0,0 +1,38 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetServiceAccount retrieves the named service account from the informer cache, or from the API server when it is not cached
+func (w *Wrapper) GetServiceAccount(namespace, name string) (*corev1.ServiceAccount, error) {
+	if w.cached() {
+		sa, err := w.serviceAccounts.ServiceAccounts(namespace).Get(name)
+		if err == nil {
+			return sa.DeepCopy(), nil
+		}
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		// The service account may be outside the cached namespace, or too new to be cached
+	}
+	return w.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestWrapper_GetServiceAccount(t *testing.T) {
+	serviceaccounts := []runtime.Object{
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "other"}},
+	}
+	tests := []struct {
+		name        string
+		namespace   string
+		saNamespace string
+		saName      string
+		wantGets    int
+		wantErr     bool
+	}{
+		{
+			name:        "reads a cached serviceaccount from the cache",
+			saNamespace: "default",
+			saName:      "default",
+		},
+		{
+			name:        "reads a serviceaccount outside the cached namespace from the API server",
+			namespace:   "default",
+			saNamespace: "other",
+			saName:      "default",
+			wantGets:    1,
+		},
+		{
+			name:        "error if serviceaccount not found",
+			saNamespace: "default",
+			saName:      "missing",
+			wantGets:    1,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, kubeClientset := setupInformers(t, tt.namespace, "", serviceaccounts...)
+			gets := countGets(kubeClientset, "serviceaccounts")
+			sa, err := w.GetServiceAccount(tt.saNamespace, tt.saName)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tt.saNamespace, sa.Namespace)
+			}
+			assert.Equal(t, tt.wantGets, *gets)
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,11 +15,16 @@
 package kubernetes
 
 import (
+	"context"
+
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 var codec = serializer.NewCodecFactory(runtime.NewScheme())
@@ -30,17 +35,76 @@ var _ WrapperInterface = &Wrapper{}
 type WrapperInterface interface {
 	kubernetes.Interface
 	GetPodSpec(*admissionv1.AdmissionRequest) (string, *corev1.PodSpec, error)
+	GetPodTemplate(*admissionv1.AdmissionRequest) (string, *corev1.PodTemplateSpec, error)
+	GetSecret(namespace, name string) (*corev1.Secret, error)
+	GetServiceAccount(namespace, name string) (*corev1.ServiceAccount, error)
+	AddServiceAccountPullSecrets(namespace string, ps *corev1.PodSpec) error
 	GetSecretToken(namespace, secretName, registry string) (string, string, error)
 	GetSecretKey(namespace, secretName string) ([]byte, error)
 	GetBasicCredentials(namespace, secretName string) (string, string, error)
+	GetNodePlatforms(ctx context.Context) ([]string, error)
+	GetNamespaceLabels(namespace string) (map[string]string, error)
+	GetConfigMapData(namespace, name, key string) (string, error)
+	CreateEvent(event *corev1.Event) error
 }
 
 // Wrapper is a wrapper around kubeclientset that includes some helper functions for applying behaviour to kube resources
 type Wrapper struct {
 	kubernetes.Interface
+
+	// listers read from the informer caches, they are nil when the wrapper has no informers
+	secrets         corelisters.SecretLister
+	serviceAccounts corelisters.ServiceAccountLister
+	synced          []cache.InformerSynced
+
+	// configMaps reads from its own informer cache, it is nil when ConfigMaps are read from the API server
+	configMaps       corelisters.ConfigMapLister
+	configMapsSynced cache.InformerSynced
+
+	// nodes holds the platforms of the cluster nodes for a short time
+	nodes nodePlatforms
 }
 
 // NewKubeClientsetWrapper creates a wrapper from the kubeclientset passed in
 func NewKubeClientsetWrapper(kubeClientset kubernetes.Interface) *Wrapper {
-	return &Wrapper{kubeClientset}
+	return &Wrapper{Interface: kubeClientset}
+}
+
+// NewInformerKubeClientsetWrapper creates a wrapper from the kubeclientset passed in that reads secrets and
+// service accounts from the informers it is passed, which must be started after the wrapper is created.
+// The informers may be restricted to a namespace or by label, anything that is not in their caches, or is
+// needed before they have synced, is read from the API server.
+func NewInformerKubeClientsetWrapper(kubeClientset kubernetes.Interface, secrets coreinformers.SecretInformer, serviceAccounts coreinformers.ServiceAccountInformer) *Wrapper {
+	return &Wrapper{
+		Interface:       kubeClientset,
+		secrets:         secrets.Lister(),
+		serviceAccounts: serviceAccounts.Lister(),
+		synced: []cache.InformerSynced{
+			secrets.Informer().HasSynced,
+			serviceAccounts.Informer().HasSynced,
+		},
+	}
+}
+
+// WithConfigMaps reads ConfigMaps from the informer, which must be started after it is set, ConfigMaps are read
+// from the API server until the informer has synced and when they are too new to be cached
+func (w *Wrapper) WithConfigMaps(configMaps coreinformers.ConfigMapInformer) *Wrapper {
+	w.configMaps = configMaps.Lister()
+	w.configMapsSynced = configMaps.Informer().HasSynced
+	return w
+}
+
+// HasSynced reports whether the informer caches have synced, a wrapper without informers has always synced
+func (w *Wrapper) HasSynced() bool {
+	for _, synced := range w.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// cached reports whether secrets and service accounts are read from the informer caches
+func (w *Wrapper) cached() bool {
+	return w.secrets != nil && w.HasSynced()
 }
@@ -1,4 +1,4 @@
-// Copyright 2020  Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -26,6 +26,16 @@ import (
 type PortierisMetrics struct {
 	AllowDecisionCount prometheus.Counter
 	DenyDecisionCount  prometheus.Counter
+	BreakGlassCount    prometheus.Counter
+
+	VerificationCacheHitCount  prometheus.Counter
+	VerificationCacheMissCount prometheus.Counter
+	VerificationCoalescedCount prometheus.Counter
+	VerificationPrewarmCount   prometheus.Counter
+
+	HTTPConnectionNewCount    prometheus.Counter
+	HTTPConnectionReusedCount prometheus.Counter
+	HTTPRegistryRequestCount  prometheus.Counter
 
 	allMetrics []prometheus.Collector
 }
@@ -35,6 +45,14 @@ func NewMetrics() *PortierisMetrics {
 	p := &PortierisMetrics{}
 	p.AllowDecisionCount = p.counter("allow_count", "Allow")
 	p.DenyDecisionCount = p.counter("deny_count", "Deny")
+	p.BreakGlassCount = p.counter("break_glass_count", "Allow by breaking glass despite a denial")
+	p.VerificationCacheHitCount = p.verificationCounter("cache_hit_count", "image verifications found in the cache")
+	p.VerificationCacheMissCount = p.verificationCounter("cache_miss_count", "image verifications not found in the cache")
+	p.VerificationCoalescedCount = p.verificationCounter("coalesced_count", "image verifications that shared the result of an identical verification in progress")
+	p.VerificationPrewarmCount = p.verificationCounter("prewarm_count", "image verifications made in the background to warm the cache")
+	p.HTTPConnectionNewCount = p.httpCounter("connection_new_count", "new connections to registries, notary servers and vulnerability scanners")
+	p.HTTPConnectionReusedCount = p.httpCounter("connection_reused_count", "connections to registries, notary servers and vulnerability scanners reused for another request")
+	p.HTTPRegistryRequestCount = p.httpCounter("registry_request_count", "requests to registries for simple signing, image configuration and digests, which open their own connections")
 	prometheus.MustRegister(p.allMetrics...)
 	return p
 }
@@ -49,6 +67,26 @@ func (p *PortierisMetrics) counter(name, help string) prometheus.Counter {
 	return result
 }
 
+func (p *PortierisMetrics) verificationCounter(name, help string) prometheus.Counter {
+	result := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: fmt.Sprintf("portieris_verification_%s", name),
+		Help: fmt.Sprintf("Portieris count of %s", help),
+	})
+
+	p.allMetrics = append(p.allMetrics, result)
+	return result
+}
+
+func (p *PortierisMetrics) httpCounter(name, help string) prometheus.Counter {
+	result := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: fmt.Sprintf("portieris_http_%s", name),
+		Help: fmt.Sprintf("Portieris count of %s", help),
+	})
+
+	p.allMetrics = append(p.allMetrics, result)
+	return result
+}
+
 func metricName(suffix string) string {
 	return fmt.Sprintf("portieris_pod_admission_decision_%s", suffix)
 }
@@ -1,4 +1,4 @@
-// Copyright 2020  Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -115,3 +115,43 @@ func TestDenyDecisionMetric(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotZero(t, value)
 }
+
+func TestVerificationCacheMetrics(t *testing.T) {
+
+	pm := NewMetrics()
+	defer pm.UnregisterAll()
+	pm.VerificationCacheHitCount.Inc()
+	pm.VerificationCacheHitCount.Inc()
+	pm.VerificationCacheMissCount.Inc()
+	pm.VerificationCoalescedCount.Inc()
+	pm.VerificationPrewarmCount.Inc()
+
+	hits, err := getMetric(pm, "portieris_verification_cache_hit_count")
+	assert.Nil(t, err)
+	assert.Equal(t, "2", hits)
+	misses, err := getMetric(pm, "portieris_verification_cache_miss_count")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", misses)
+	coalesced, err := getMetric(pm, "portieris_verification_coalesced_count")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", coalesced)
+	prewarmed, err := getMetric(pm, "portieris_verification_prewarm_count")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", prewarmed)
+}
+
+func TestHTTPConnectionMetrics(t *testing.T) {
+
+	pm := NewMetrics()
+	defer pm.UnregisterAll()
+	pm.HTTPConnectionNewCount.Inc()
+	pm.HTTPConnectionReusedCount.Inc()
+	pm.HTTPConnectionReusedCount.Inc()
+
+	created, err := getMetric(pm, "portieris_http_connection_new_count")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", created)
+	reused, err := getMetric(pm, "portieris_http_connection_reused_count")
+	assert.Nil(t, err)
+	assert.Equal(t, "2", reused)
+}
@@ -1,4 +1,4 @@
-// Copyright 2018, 2022 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,6 +15,7 @@
 package fakenotary
 
 import (
+	"context"
 	"sync"
 
 	"github.com/IBM/portieris/helpers/image"
@@ -48,7 +49,7 @@ type FakeNotary struct {
 }
 
 // GetNotaryRepo ...
-func (fake *FakeNotary) GetNotaryRepo(server, image, notaryToken string) (notaryclient.Repository, error) {
+func (fake *FakeNotary) GetNotaryRepo(ctx context.Context, server, image, notaryToken string) (notaryclient.Repository, error) {
 	fake.getNotaryRepoMutex.Lock()
 	fake.GetNotaryRepoArgsForCall = append(fake.GetNotaryRepoArgsForCall, struct {
 		Server      string
@@ -80,7 +81,7 @@ func (fake *FakeNotary) GetNotaryRepoReturns(notaryRepo notaryclient.Repository,
 }
 
 // CheckAuthRequired ...
-func (fake *FakeNotary) CheckAuthRequired(notaryURL string, img *image.Reference) (*notary.AuthEndpoint, error) {
+func (fake *FakeNotary) CheckAuthRequired(ctx context.Context, notaryURL string, img *image.Reference) (*notary.AuthEndpoint, error) {
 	fake.checkAuthRequiredMutex.Lock()
 	fake.CheckAuthRequiredArgsForCall = append(fake.CheckAuthRequiredArgsForCall, struct {
 		NotaryURL string
@@ -15,17 +15,14 @@
 package notary
 
 import (
-	"crypto/tls"
-	"crypto/x509"
+	"context"
 	"fmt"
-	"net"
+	"io"
 	"net/http"
 	"os"
-	"time"
 
 	httphelper "github.com/IBM/portieris/helpers/http"
 	"github.com/IBM/portieris/helpers/image"
-	"github.com/IBM/portieris/internal/info"
 	notaryclient "github.com/theupdateframework/notary/client"
 	"github.com/theupdateframework/notary/trustpinning"
 	"github.com/theupdateframework/notary/tuf/data"
@@ -40,61 +37,60 @@ type AuthEndpoint struct {
 
 // Client .
 type Client struct {
-	trustDir string
-	rootCAs  *x509.CertPool
+	trustDir  string
+	transport http.RoundTripper
 }
 
 // Interface .
 type Interface interface {
-	GetNotaryRepo(server, image, notaryToken string) (notaryclient.Repository, error)
-	CheckAuthRequired(notaryURL string, img *image.Reference) (*AuthEndpoint, error)
+	GetNotaryRepo(ctx context.Context, server, image, notaryToken string) (notaryclient.Repository, error)
+	CheckAuthRequired(ctx context.Context, notaryURL string, img *image.Reference) (*AuthEndpoint, error)
 }
 
-// NewClient creates and initializes the client
-func NewClient(trustDir string, customCA []byte) (Interface, error) {
+// NewClient creates and initializes the client, which sends requests with transport
+func NewClient(trustDir string, transport http.RoundTripper) (Interface, error) {
 	// Create a trust directory
 	err := createTrustDir(trustDir)
 	if err != nil {
 		return nil, err
 	}
-	rootCA, err := x509.SystemCertPool()
-	if err != nil {
-		return nil, err
-	}
-	if customCA != nil {
-		rootCA.AppendCertsFromPEM(customCA)
-	}
-	return &Client{trustDir: trustDir, rootCAs: rootCA}, nil
+	return &Client{trustDir: trustDir, transport: transport}, nil
 }
 
-// GetNotaryRepo .
-func (c Client) GetNotaryRepo(server, image, notaryToken string) (notaryclient.Repository, error) {
+// GetNotaryRepo returns the repository of image on server, whose requests are abandoned when ctx is done
+func (c Client) GetNotaryRepo(ctx context.Context, server, image, notaryToken string) (notaryclient.Repository, error) {
 	return notaryclient.NewFileCachedRepository(
 		c.trustDir,
 		data.GUN(image),
 		server,
-		c.makeHubTransport(notaryToken),
+		c.makeHubTransport(ctx, notaryToken),
 		nil,
 		trustpinning.TrustPinConfig{},
 	)
 }
 
 // CheckAuthRequired checks if the notary requires authentication and returns information where to authenticate
-func (c Client) CheckAuthRequired(notaryURL string, img *image.Reference) (*AuthEndpoint, error) {
-	client := &http.Client{Transport: c.makeHubTransport("")}
+func (c Client) CheckAuthRequired(ctx context.Context, notaryURL string, img *image.Reference) (*AuthEndpoint, error) {
+	client := &http.Client{Transport: c.makeHubTransport(ctx, "")}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v2/%s/_trust/tuf/root.json", notaryURL, img.NameWithoutTag()), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/v2/%s/_trust/tuf/root.json", notaryURL, img.NameWithoutTag()), nil)
 
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := client.Do(req)
-
-	if err != nil || resp.StatusCode != http.StatusUnauthorized {
-		// either an error occured or authentication isn't required
+	if err != nil {
 		return nil, err
 	}
+	// the body is read and closed so that the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		// authentication isn't required
+		return nil, nil
+	}
 
 	challenges, err := httphelper.ParseAuthHeader(resp.Header)
 
@@ -129,34 +125,17 @@ func (c Client) CheckAuthRequired(notaryURL string, img *image.Reference) (*Auth
 	return nil, fmt.Errorf("no supported auth-endpoint found")
 }
 
-func (c Client) makeHubTransport(notaryToken string) http.RoundTripper {
-	base := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		Dial: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-			DualStack: true,
-		}).Dial,
-		TLSHandshakeTimeout: 10 * time.Second,
-		TLSClientConfig: &tls.Config{
-			// Avoid fallback by default to SSL protocols < TLS1.2
-			MinVersion:               tls.VersionTLS12,
-			PreferServerCipherSuites: true,
-			RootCAs:                  c.rootCAs,
-		},
-		DisableKeepAlives: true,
-	}
-
-	headers := http.Header{
-		"User-Agent": []string{"portieris/" + info.Version},
-	}
+// makeHubTransport sends the requests of the notary client with the shared transport, with the context and token
+func (c Client) makeHubTransport(ctx context.Context, notaryToken string) http.RoundTripper {
+	headers := http.Header{}
 
 	if notaryToken != "" {
 		headers.Set("Authorization", fmt.Sprintf("Bearer %s", notaryToken))
 	}
 
 	return &headerTransport{
-		base:    base,
+		ctx:     ctx,
+		base:    c.transport,
 		headers: headers,
 	}
 }
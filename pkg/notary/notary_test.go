@@ -1,4 +1,4 @@
-// Copyright 2018 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,6 +15,10 @@
 package notary
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -30,10 +34,29 @@ var _ = Describe("Notary", func() {
 
 	Describe("Getting the notary repo", func() {
 		It("should return an error", func() {
-			_, err := trust.GetNotaryRepo("server", "image", "notaryToken")
+			_, err := trust.GetNotaryRepo(context.Background(), "server", "image", "notaryToken")
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("HTTPStore requires an absolute baseURL"))
 		})
 	})
 
+	Describe("Sending requests to notary", func() {
+		It("should send requests with the context of the transport", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Header.Get("Authorization")).To(Equal("Bearer notaryToken"))
+			}))
+			defer server.Close()
+			ctx, cancel := context.WithCancel(context.Background())
+			client := &http.Client{Transport: Client{transport: http.DefaultTransport}.makeHubTransport(ctx, "notaryToken")}
+
+			resp, err := client.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+
+			cancel()
+			_, err = client.Get(server.URL)
+			Expect(err).To(MatchError(ContainSubstring("context canceled")))
+		})
+	})
+
 })
@@ -24,15 +24,19 @@
 // - Adapted to work as a standalone implementation without distribution package dependency
 // - Simplified to focus on header modification use case
 // - Removed deprecated CancelRequest support and request tracking (unused in this codebase)
+// - Requests are sent with the context of the transport
 
 package notary
 
 import (
+	"context"
 	"net/http"
 )
 
 // headerTransport is a custom RoundTripper that adds headers to requests.
 type headerTransport struct {
+	// ctx is set on requests, because the notary client creates them without a context
+	ctx     context.Context
 	base    http.RoundTripper
 	headers http.Header
 }
@@ -56,8 +60,7 @@ func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 // The clone is a shallow copy of the struct and its Header map.
 func (t *headerTransport) cloneRequest(r *http.Request) *http.Request {
 	// shallow copy of the struct
-	r2 := new(http.Request)
-	*r2 = *r
+	r2 := r.WithContext(t.ctx)
 	// deep copy of the Header to avoid race conditions
 	r2.Header = make(http.Header, len(r.Header))
 	for k, s := range r.Header {
@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -17,32 +17,104 @@ package policy
 import (
 	"context"
 	"fmt"
+	"time"
 
 	policyClientSet "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned"
+	informers "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/informers/externalversions"
+	listersv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/listers/portieris.cloud.ibm.com/v1"
 	policyV1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
 )
 
 // Interface defines the interface needed to work out which policy should be enforced
 type Interface interface {
-	GetPolicyToEnforce(namespace, image string) (*policyV1.Policy, error)
+	GetPolicyToEnforce(namespace, image string, workload policyV1.Workload) (*policyV1.PolicyMatch, error)
+	GetImagePolicyException(namespace, image string) (*policyV1.ImagePolicyException, error)
 }
 
 // Client is responsible for working out which policy should be enforced
 type Client struct {
 	// policyClientSet is a clientset for the policy CRDs
 	policyClientSet policyClientSet.Interface
+
+	// listers read from the informer caches, which hold the last known policies while the
+	// API server can't be reached, they are nil when the client has no informers
+	imagePolicies        listersv1.ImagePolicyLister
+	clusterImagePolicies listersv1.ClusterImagePolicyLister
+	exceptions           listersv1.ImagePolicyExceptionLister
+	profiles             listersv1.ImagePolicyProfileLister
+	synced               []cache.InformerSynced
+
+	// maxExceptionLifetime bounds how long after it is created an ImagePolicyException can apply
+	maxExceptionLifetime time.Duration
 }
 
 // NewClient creates a new policy client using the Security Enforcement client set it is passed
 func NewClient(policyClientSet policyClientSet.Interface) *Client {
 	return &Client{
-		policyClientSet: policyClientSet,
+		policyClientSet:      policyClientSet,
+		maxExceptionLifetime: policyV1.DefaultMaxExceptionLifetime,
+	}
+}
+
+// NewInformerClient creates a new policy client that reads policies from the informers of informerFactory,
+// which must be started after the client is created. Until the informer caches have synced, policies are
+// read from the API server with policyClientSet.
+func NewInformerClient(policyClientSet policyClientSet.Interface, informerFactory informers.SharedInformerFactory) *Client {
+	v1 := informerFactory.Portieris().V1()
+	return &Client{
+		policyClientSet:      policyClientSet,
+		maxExceptionLifetime: policyV1.DefaultMaxExceptionLifetime,
+		imagePolicies:        v1.ImagePolicies().Lister(),
+		clusterImagePolicies: v1.ClusterImagePolicies().Lister(),
+		exceptions:           v1.ImagePolicyExceptions().Lister(),
+		profiles:             v1.ImagePolicyProfiles().Lister(),
+		synced: []cache.InformerSynced{
+			v1.ImagePolicies().Informer().HasSynced,
+			v1.ClusterImagePolicies().Informer().HasSynced,
+			v1.ImagePolicyExceptions().Informer().HasSynced,
+			v1.ImagePolicyProfiles().Informer().HasSynced,
+		},
+	}
+}
+
+// WithMaxExceptionLifetime sets how long after it is created an ImagePolicyException can apply, 0 is no limit
+func (c *Client) WithMaxExceptionLifetime(maxLifetime time.Duration) *Client {
+	c.maxExceptionLifetime = maxLifetime
+	return c
+}
+
+// HasSynced reports whether the informer caches have synced, a client without informers has always synced
+func (c *Client) HasSynced() bool {
+	for _, synced := range c.synced {
+		if !synced() {
+			return false
+		}
 	}
+	return true
+}
+
+// cached reports whether policies are read from the informer caches
+func (c *Client) cached() bool {
+	return c.imagePolicies != nil && c.HasSynced()
 }
 
 // getImagePolicyList retrieves the list of image policies in the specified namespace
 func (c *Client) getImagePolicyList(namespace string) (*policyV1.ImagePolicyList, error) {
+	if c.cached() {
+		items, err := c.imagePolicies.ImagePolicies(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		policies := &policyV1.ImagePolicyList{}
+		for _, item := range items {
+			policies.Items = append(policies.Items, *item)
+		}
+		return policies, nil
+	}
 	policies, err := c.policyClientSet.PortierisV1().ImagePolicies(namespace).List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -52,6 +124,17 @@ func (c *Client) getImagePolicyList(namespace string) (*policyV1.ImagePolicyList
 
 // getClusterPolicySpec retrieves the lost of clusterwide image policies
 func (c *Client) getClusterImagePolicyList() (*policyV1.ClusterImagePolicyList, error) {
+	if c.cached() {
+		items, err := c.clusterImagePolicies.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		policies := &policyV1.ClusterImagePolicyList{}
+		for _, item := range items {
+			policies.Items = append(policies.Items, *item)
+		}
+		return policies, nil
+	}
 	policies, err := c.policyClientSet.PortierisV1().ClusterImagePolicies().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -59,8 +142,10 @@ func (c *Client) getClusterImagePolicyList() (*policyV1.ClusterImagePolicyList,
 	return policies, nil
 }
 
-// GetPolicyToEnforce retrieves the policy that should be enforced for the specified image in the given namespace
-func (c *Client) GetPolicyToEnforce(namespace, image string) (*policyV1.Policy, error) {
+// GetPolicyToEnforce retrieves the policy that should be enforced for the specified image in the given namespace,
+// repositories that are scoped to particular workloads are only considered when the workload matches.
+// The match also records where the policy came from and any equally specific repositories that conflict with it.
+func (c *Client) GetPolicyToEnforce(namespace, image string, workload policyV1.Workload) (*policyV1.PolicyMatch, error) {
 	policyList, err := c.getImagePolicyList(namespace)
 	if err != nil {
 		return nil, err
@@ -79,21 +164,70 @@ func (c *Client) GetPolicyToEnforce(namespace, image string) (*policyV1.Policy,
 		}
 
 		// See if there is a match for the image
-		clusterPolicy := clusterPolicyList.FindClusterImagePolicy(image)
-		if clusterPolicy == nil {
+		clusterPolicy := clusterPolicyList.MatchClusterImagePolicy(image, workload)
+		if clusterPolicy.Policy == nil {
 			// We also don't have any cluster image policies, deny the request
 			return nil, fmt.Errorf("Deny %q, no matching repositories in ClusterImagePolicy and no ImagePolicies in the %q namespace", image, namespace)
 		}
-		return clusterPolicy, nil
+		return c.applyProfile(image, &clusterPolicy)
 	}
 
 	// For this image, see if there is an ImagePolicy repository that matches.
 	// Get the policy if it does
-	policy := policyList.FindImagePolicy(image)
+	policy := policyList.MatchImagePolicy(image, workload)
 
-	if policy == nil {
+	if policy.Policy == nil {
 		// We also don't have any cluster image policies, deny the request
 		return nil, fmt.Errorf("Deny %q, no matching repositories in the ImagePolicies", image)
 	}
-	return policy, nil
+	return c.applyProfile(image, &policy)
+}
+
+// GetImagePolicyException retrieves an ImagePolicyException in the given namespace that currently allows the image,
+// or nil when there is none
+func (c *Client) GetImagePolicyException(namespace, image string) (*policyV1.ImagePolicyException, error) {
+	if c.cached() {
+		items, err := c.exceptions.ImagePolicyExceptions(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		exceptions := &policyV1.ImagePolicyExceptionList{}
+		for _, item := range items {
+			exceptions.Items = append(exceptions.Items, *item)
+		}
+		return exceptions.MatchImagePolicyException(image, time.Now(), c.maxExceptionLifetime), nil
+	}
+	exceptions, err := c.policyClientSet.PortierisV1().ImagePolicyExceptions(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return exceptions.MatchImagePolicyException(image, time.Now(), c.maxExceptionLifetime), nil
+}
+
+// getImagePolicyProfile retrieves the named ImagePolicyProfile
+func (c *Client) getImagePolicyProfile(name string) (*policyV1.ImagePolicyProfile, error) {
+	if c.cached() {
+		profile, err := c.profiles.Get(name)
+		if errors.IsNotFound(err) {
+			// report a missing profile as the API server does
+			return nil, errors.NewNotFound(policyV1.Resource("imagepolicyprofiles"), name)
+		}
+		return profile, err
+	}
+	return c.policyClientSet.PortierisV1().ImagePolicyProfiles().Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// applyProfile replaces the policy of a repository that references an ImagePolicyProfile with the
+// profile policy, overlaid with the parts of the repository policy that are set
+func (c *Client) applyProfile(image string, match *policyV1.PolicyMatch) (*policyV1.PolicyMatch, error) {
+	if match.Profile == "" {
+		return match, nil
+	}
+	profile, err := c.getImagePolicyProfile(match.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("Deny %q, ImagePolicyProfile %q for %s repository %q: %v", image, match.Profile, match.Source, match.Repository, err)
+	}
+	policy := profile.Spec.Policy.Overlay(*match.Policy)
+	match.Policy = &policy
+	return match, nil
 }
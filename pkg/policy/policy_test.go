@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -17,13 +17,16 @@ package policy
 import (
 	"errors"
 	"testing"
+	"time"
 
 	policyclientset "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned"
 	"github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/clientset/versioned/fake"
+	informers "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/informers/externalversions"
 	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 var (
@@ -64,6 +67,8 @@ var (
 	helloEarthRepositoryTrustEnabled  = policyv1.Repository{Name: "icr.io/hello/earth", Policy: enabledTrustPolicy}
 	helloEarthRepositoryTrustDisabled = policyv1.Repository{Name: "icr.io/hello/earth", Policy: disabledTrustPolicy}
 
+	internetSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"exposure": "internet"}}
+
 	enabledTrustPolicy  = policyv1.Policy{Trust: policyv1.Trust{Enabled: &trueBool}}
 	disabledTrustPolicy = policyv1.Policy{Trust: policyv1.Trust{Enabled: &falseBool}}
 )
@@ -88,19 +93,42 @@ func createImagePolicy(name, namespace string, repos []policyv1.Repository) *pol
 	}
 }
 
+func createImagePolicyProfile(name string, policy policyv1.Policy) *policyv1.ImagePolicyProfile {
+	return &policyv1.ImagePolicyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		TypeMeta:   metav1.TypeMeta{Kind: "ImagePolicyProfile"},
+		Spec: policyv1.ImagePolicyProfileSpec{
+			Policy: policy,
+		},
+	}
+}
+
 func setup(policies []runtime.Object) (*Client, policyclientset.Interface) {
 	clientSet := fake.NewSimpleClientset(policies...)
 	return NewClient(clientSet), clientSet
 }
 
+func setupInformers(t *testing.T, policies []runtime.Object) (*Client, policyclientset.Interface) {
+	clientSet := fake.NewSimpleClientset(policies...)
+	informerFactory := informers.NewSharedInformerFactory(clientSet, 0)
+	client := NewInformerClient(clientSet, informerFactory)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	informerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	return client, clientSet
+}
+
 func TestClient_GetPolicyToEnforce(t *testing.T) {
 
 	tests := []struct {
 		name      string
 		namespace string
 		image     string
+		workload  policyv1.Workload
 		policies  []runtime.Object
 		want      *policyv1.Policy
+		wantMatch *policyv1.PolicyMatch
 		wantErr   error
 	}{
 		{
@@ -200,22 +228,214 @@ func TestClient_GetPolicyToEnforce(t *testing.T) {
 			},
 			want: &enabledTrustPolicy,
 		},
+		{
+			name:      "Image policy scoped by selector matches workload: return scoped policy",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			workload:  policyv1.Workload{Labels: map[string]string{"exposure": "internet"}},
+			policies: []runtime.Object{
+				createImagePolicy("policy-one", "default", []policyv1.Repository{
+					helloWorldRepositoryTrustDisabled,
+					{Name: "icr.io/hello/world", Policy: enabledTrustPolicy, Selector: internetSelector},
+				}),
+			},
+			want: &enabledTrustPolicy,
+		},
+		{
+			name:      "Image policy scoped by selector does not match workload: return unscoped policy",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			workload:  policyv1.Workload{Labels: map[string]string{"exposure": "batch"}},
+			policies: []runtime.Object{
+				createImagePolicy("policy-one", "default", []policyv1.Repository{
+					{Name: "icr.io/hello/world", Policy: enabledTrustPolicy, Selector: internetSelector},
+					helloWorldRepositoryTrustDisabled,
+				}),
+			},
+			want: &disabledTrustPolicy,
+		},
+		{
+			name:      "Only scoped image policy and workload does not match: return error",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			workload:  policyv1.Workload{ServiceAccountName: "batch"},
+			policies: []runtime.Object{
+				createImagePolicy("policy-one", "default", []policyv1.Repository{
+					{Name: "icr.io/hello/world", Policy: enabledTrustPolicy, ServiceAccountNames: []string{"web"}},
+				}),
+			},
+			wantErr: errors.New(`Deny "icr.io/hello/world", no matching repositories in the ImagePolicies`),
+		},
+		{
+			name:      "Cluster policy scoped by service account matches workload: return scoped policy",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			workload:  policyv1.Workload{ServiceAccountName: "web"},
+			policies: []runtime.Object{
+				createClusterImagePolicy("policy-one", []policyv1.Repository{helloWorldRepositoryTrustDisabled}),
+				createClusterImagePolicy("policy-two", []policyv1.Repository{
+					{Name: "icr.io/hello/world", Policy: enabledTrustPolicy, ServiceAccountNames: []string{"web"}},
+				}),
+			},
+			want: &enabledTrustPolicy,
+		},
+		{
+			name:      "Equally specific cluster policies: return first by name and report conflict",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			policies: []runtime.Object{
+				createClusterImagePolicy("policy-two", []policyv1.Repository{helloWorldRepositoryTrustDisabled}),
+				createClusterImagePolicy("policy-one", []policyv1.Repository{helloWorldRepositoryTrustEnabled}),
+			},
+			want: &enabledTrustPolicy,
+			wantMatch: &policyv1.PolicyMatch{
+				Policy:     &enabledTrustPolicy,
+				Source:     "policy-one",
+				Repository: "icr.io/hello/world",
+				Conflicts:  []string{`policy-two repository "icr.io/hello/world"`},
+			},
+		},
+		{
+			name:      "Equally specific image policies with the same policy: no conflict",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			policies: []runtime.Object{
+				createImagePolicy("policy-one", "default", []policyv1.Repository{helloWorldRepositoryTrustEnabled}),
+				createImagePolicy("policy-two", "default", []policyv1.Repository{helloWorldRepositoryTrustEnabled}),
+			},
+			want: &enabledTrustPolicy,
+			wantMatch: &policyv1.PolicyMatch{
+				Policy:     &enabledTrustPolicy,
+				Source:     "default/policy-one",
+				Repository: "icr.io/hello/world",
+			},
+		},
+		{
+			name:      "Higher priority image policy beats a more specific one",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			policies: []runtime.Object{
+				createImagePolicy("policy-one", "default", []policyv1.Repository{helloWorldRepositoryTrustDisabled}),
+				createImagePolicy("policy-two", "default", []policyv1.Repository{{Name: "icr.io/*", Policy: enabledTrustPolicy, Priority: 10}}),
+			},
+			want: &enabledTrustPolicy,
+			wantMatch: &policyv1.PolicyMatch{
+				Policy:     &enabledTrustPolicy,
+				Source:     "default/policy-two",
+				Repository: "icr.io/*",
+			},
+		},
+		{
+			name:      "Image policy repository references a profile: return the profile policy",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			policies: []runtime.Object{
+				createImagePolicyProfile("trusted", enabledTrustPolicy),
+				createImagePolicy("policy-one", "default", []policyv1.Repository{{Name: "icr.io/*", Profile: "trusted"}}),
+			},
+			want: &enabledTrustPolicy,
+			wantMatch: &policyv1.PolicyMatch{
+				Policy:     &enabledTrustPolicy,
+				Source:     "default/policy-one",
+				Repository: "icr.io/*",
+				Profile:    "trusted",
+			},
+		},
+		{
+			name:      "Cluster policy repository overrides part of a profile: return the overlaid policy",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			policies: []runtime.Object{
+				createImagePolicyProfile("trusted", policyv1.Policy{
+					Trust:       policyv1.Trust{Enabled: &trueBool},
+					MutateImage: &trueBool,
+				}),
+				createClusterImagePolicy("policy-one", []policyv1.Repository{{Name: "icr.io/*", Profile: "trusted", Policy: policyv1.Policy{MutateImage: &falseBool}}}),
+			},
+			want: &policyv1.Policy{Trust: policyv1.Trust{Enabled: &trueBool}, MutateImage: &falseBool},
+		},
+		{
+			name:      "Repository references a missing profile: return error",
+			image:     "icr.io/hello/world",
+			namespace: "default",
+			policies: []runtime.Object{
+				createImagePolicy("policy-one", "default", []policyv1.Repository{{Name: "icr.io/*", Profile: "missing"}}),
+			},
+			wantErr: errors.New(`Deny "icr.io/hello/world", ImagePolicyProfile "missing" for default/policy-one repository "icr.io/*": imagepolicyprofiles.portieris.cloud.ibm.com "missing" not found`),
+		},
 	}
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			client, _ := setup(tt.policies)
-			got, err := client.GetPolicyToEnforce(tt.namespace, tt.image)
+		check := func(t *testing.T, client *Client) {
+			got, err := client.GetPolicyToEnforce(tt.namespace, tt.image, tt.workload)
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 				assert.Nil(t, got)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.want, got)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got.Policy)
+				if tt.wantMatch != nil {
+					assert.Equal(t, tt.wantMatch, got)
+				}
 			}
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := setup(tt.policies)
+			check(t, client)
+		})
+		t.Run(tt.name+" from informers", func(t *testing.T) {
+			client, _ := setupInformers(t, tt.policies)
+			check(t, client)
 		})
 	}
 }
 
+func TestInformerClient(t *testing.T) {
+	policies := []runtime.Object{
+		createImagePolicy("policy-one", "default", []policyv1.Repository{helloWorldRepositoryTrustEnabled}),
+		&policyv1.ImagePolicyException{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hotfix"},
+			Spec: policyv1.ImagePolicyExceptionSpec{
+				Image:     "icr.io/hello/earth",
+				ExpiresAt: metav1.NewTime(time.Now().Add(time.Hour)),
+				Reason:    "INC-1234",
+				Approver:  "security",
+			},
+		},
+	}
+
+	t.Run("reads from the API server until the caches have synced", func(t *testing.T) {
+		clientSet := fake.NewSimpleClientset(policies...)
+		client := NewInformerClient(clientSet, informers.NewSharedInformerFactory(clientSet, 0))
+		assert.False(t, client.HasSynced())
+
+		got, err := client.GetPolicyToEnforce("default", "icr.io/hello/world", policyv1.Workload{})
+		if assert.NoError(t, err) {
+			assert.Equal(t, &enabledTrustPolicy, got.Policy)
+		}
+	})
+
+	t.Run("uses the last known policies when the API server fails", func(t *testing.T) {
+		client, clientSet := setupInformers(t, policies)
+		assert.True(t, client.HasSynced())
+		clientSet.(*fake.Clientset).PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("API server unavailable")
+		})
+
+		got, err := client.GetPolicyToEnforce("default", "icr.io/hello/world", policyv1.Workload{})
+		if assert.NoError(t, err) {
+			assert.Equal(t, &enabledTrustPolicy, got.Policy)
+		}
+		exception, err := client.GetImagePolicyException("default", "icr.io/hello/earth")
+		if assert.NoError(t, err) && assert.NotNil(t, exception) {
+			assert.Equal(t, "hotfix", exception.Name)
+		}
+	})
+
+	t.Run("client without informers has always synced", func(t *testing.T) {
+		client, _ := setup(nil)
+		assert.True(t, client.HasSynced())
+	})
+}
+
 func TestClient_getImagePolicyList(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -282,3 +502,61 @@ func TestClient_getClusterImagePolicyList(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetImagePolicyException(t *testing.T) {
+	exception := func(namespace, name string, expiresAt time.Time) *policyv1.ImagePolicyException {
+		return &policyv1.ImagePolicyException{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			TypeMeta:   metav1.TypeMeta{Kind: "ImagePolicyException"},
+			Spec: policyv1.ImagePolicyExceptionSpec{
+				Image:     "icr.io/team-a/*",
+				ExpiresAt: metav1.NewTime(expiresAt),
+				Reason:    "INC-1234",
+				Approver:  "oncall@example.com",
+			},
+		}
+	}
+	current := exception("default", "current", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name          string
+		namespace     string
+		image         string
+		exceptions    []runtime.Object
+		wantException *policyv1.ImagePolicyException
+	}{
+		{
+			name:          "returns the exception for a matching image",
+			namespace:     "default",
+			image:         "icr.io/team-a/app:v1",
+			exceptions:    []runtime.Object{current},
+			wantException: current,
+		},
+		{
+			name:       "ignores an exception in another namespace",
+			namespace:  "other",
+			image:      "icr.io/team-a/app:v1",
+			exceptions: []runtime.Object{current},
+		},
+		{
+			name:       "ignores an expired exception",
+			namespace:  "default",
+			image:      "icr.io/team-a/app:v1",
+			exceptions: []runtime.Object{exception("default", "expired", time.Now().Add(-time.Hour))},
+		},
+		{
+			name:       "returns nil when there are no exceptions",
+			namespace:  "default",
+			image:      "icr.io/team-a/app:v1",
+			exceptions: []runtime.Object{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := setup(tt.exceptions)
+			got, err := client.GetImagePolicyException(tt.namespace, tt.image)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantException, got)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/IBM/portieris/helpers/wildcard"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/verifier/simple"
+	"github.com/IBM/portieris/pkg/verifier/tags"
+	"github.com/theupdateframework/notary/tuf/utils"
+)
+
+// ProfileGetter gets an ImagePolicyProfile by name, it is satisfied by the ImagePolicyProfile lister
+type ProfileGetter interface {
+	Get(name string) (*policyv1.ImagePolicyProfile, error)
+}
+
+// Problems found in a policy that would cause deployments to be denied, by the kind of problem
+type Problems struct {
+	// Spec are problems with the policy as written
+	Spec []string
+	// Secrets are problems with the secrets referenced by the policy
+	Secrets []string
+	// Keys are problems with the public keys in the secrets referenced by the policy
+	Keys []string
+}
+
+// Checker finds the problems in a policy
+type Checker struct {
+	kubeWrapper kubernetes.WrapperInterface
+	profiles    ProfileGetter
+}
+
+// NewChecker creates a Checker that reads secrets with kubeWrapper and profiles with profiles
+func NewChecker(kubeWrapper kubernetes.WrapperInterface, profiles ProfileGetter) Checker {
+	return Checker{
+		kubeWrapper: kubeWrapper,
+		profiles:    profiles,
+	}
+}
+
+// CheckException finds the problems that stop an ImagePolicyException from applying, a missing field or an expiry
+// more than maxLifetime after the exception was created, 0 is no limit
+func CheckException(exception policyv1.ImagePolicyException, maxLifetime time.Duration, now time.Time) []string {
+	var problems []string
+	for field, empty := range map[string]bool{
+		"image":     exception.Spec.Image == "",
+		"expiresAt": exception.Spec.ExpiresAt.IsZero(),
+		"reason":    exception.Spec.Reason == "",
+		"approver":  exception.Spec.Approver == "",
+	} {
+		if empty {
+			problems = append(problems, fmt.Sprintf("%s is required", field))
+		}
+	}
+	sort.Strings(problems)
+	if !exception.Spec.ExpiresAt.IsZero() && maxLifetime > 0 && exception.Lifetime(now) > maxLifetime {
+		problems = append(problems, fmt.Sprintf("expiresAt %s is more than %v after the exception was created", exception.Spec.ExpiresAt.UTC().Format(time.RFC3339), maxLifetime))
+	}
+	return problems
+}
+
+// Check finds the problems in the spec of the ImagePolicy in namespace, or of a ClusterImagePolicy when namespace
+// is empty. Secrets that a ClusterImagePolicy references without a namespace are found in the namespace of each
+// workload, so they are not checked.
+func (c Checker) Check(namespace string, spec policyv1.ImagePolicySpec) Problems {
+	var p Problems
+	for i, repo := range spec.Repositories {
+		if repo.Name == "" {
+			p.Spec = append(p.Spec, fmt.Sprintf("repository %d: name is empty", i))
+			continue
+		}
+		prefix := fmt.Sprintf("repository %q", repo.Name)
+		checkName(&p, prefix, repo)
+		policy := repo.Policy
+		if namespace != "" {
+			// the secrets of an ImagePolicy are always read from its own namespace
+			checkSecretNamespaces(&p, prefix, namespace, repo.Policy)
+			policy = repo.Policy.WithoutSecretNamespaces()
+		}
+		if repo.Profile != "" {
+			profile, err := c.profiles.Get(repo.Profile)
+			if err != nil {
+				p.Spec = append(p.Spec, fmt.Sprintf("%s: ImagePolicyProfile %q: %v", prefix, repo.Profile, err))
+				continue
+			}
+			policy = profile.Spec.Policy.Overlay(policy)
+		}
+		c.checkTrust(&p, prefix, namespace, policy.Trust)
+		c.checkSimple(&p, prefix, namespace, policy.Simple)
+		if policy.Tags.Pattern != "" {
+			if _, err := tags.CompilePattern(policy.Tags.Pattern); err != nil {
+				p.Spec = append(p.Spec, fmt.Sprintf("%s: tags pattern %q is invalid: %v", prefix, policy.Tags.Pattern, err))
+			}
+		}
+	}
+	if spec.Rego != nil && spec.Rego.ConfigMap != nil && namespace == "" {
+		ref := spec.Rego.ConfigMap
+		if _, err := c.kubeWrapper.GetConfigMapData(ref.Namespace, ref.Name, ref.Key); err != nil {
+			p.Spec = append(p.Spec, fmt.Sprintf("rego: %v", err))
+		}
+	}
+	return p
+}
+
+// checkName finds the name and excludes that can't match any image, because they are not valid for the name type
+func checkName(p *Problems, prefix string, repo policyv1.Repository) {
+	switch repo.NameType {
+	case "", policyv1.NameTypeWildcard, policyv1.NameTypePath:
+	case policyv1.NameTypeRegexp:
+		for _, pattern := range append([]string{repo.Name}, repo.Excludes...) {
+			if err := wildcard.ValidateRegexp(pattern); err != nil {
+				p.Spec = append(p.Spec, fmt.Sprintf("%s: regexp %q is invalid: %v", prefix, pattern, err))
+			}
+		}
+	default:
+		p.Spec = append(p.Spec, fmt.Sprintf("%s: nameType %q is invalid", prefix, repo.NameType))
+	}
+}
+
+// checkSecretNamespaces finds the secrets of an ImagePolicy in namespace that are referenced in another namespace,
+// which only a ClusterImagePolicy can do
+func checkSecretNamespaces(p *Problems, prefix, namespace string, policy policyv1.Policy) {
+	report := func(kind, name, secretNamespace string) {
+		if secretNamespace != "" && secretNamespace != namespace {
+			p.Spec = append(p.Spec, fmt.Sprintf("%s: %s secret %q in namespace %q can only be referenced by a ClusterImagePolicy", prefix, kind, name, secretNamespace))
+		}
+	}
+	for _, signer := range policy.Trust.SignerSecrets {
+		report("signer", signer.Name, signer.Namespace)
+	}
+	for _, requirement := range policy.Simple.Requirements {
+		report("key", requirement.KeySecret, requirement.KeySecretNamespace)
+	}
+	report("store", policy.Simple.StoreSecret, policy.Simple.StoreSecretNamespace)
+}
+
+func (c Checker) checkTrust(p *Problems, prefix, namespace string, trust policyv1.Trust) {
+	if trust.TrustServer != "" {
+		if u, err := url.Parse(trust.TrustServer); err != nil || u.Scheme != "https" || u.Host == "" {
+			p.Spec = append(p.Spec, fmt.Sprintf("%s: trustServer %q is not an https URL", prefix, trust.TrustServer))
+		}
+	}
+	for _, signer := range trust.SignerSecrets {
+		secretNamespace := namespace
+		if signer.Namespace != "" {
+			secretNamespace = signer.Namespace
+		}
+		if secretNamespace == "" {
+			continue
+		}
+		secret, err := c.kubeWrapper.GetSecret(secretNamespace, signer.Name)
+		if err != nil {
+			p.Secrets = append(p.Secrets, fmt.Sprintf("%s: signer secret: %v", prefix, err))
+			continue
+		}
+		if len(secret.Data["name"]) == 0 || len(secret.Data["publicKey"]) == 0 {
+			p.Secrets = append(p.Secrets, fmt.Sprintf("%s: signer secret %q: name or publicKey is empty", prefix, signer.Name))
+			continue
+		}
+		if _, err := utils.ParsePEMPublicKey(secret.Data["publicKey"]); err != nil {
+			p.Keys = append(p.Keys, fmt.Sprintf("%s: signer secret %q: %v", prefix, signer.Name, err))
+		}
+	}
+}
+
+func (c Checker) checkSimple(p *Problems, prefix, namespace string, policy policyv1.Simple) {
+	for _, requirement := range policy.Requirements {
+		switch requirement.Type {
+		case "insecureAcceptAnything", "reject":
+			continue
+		case "signedBy":
+		default:
+			p.Spec = append(p.Spec, fmt.Sprintf("%s: simple requirement type %q is invalid", prefix, requirement.Type))
+			continue
+		}
+		if err := simple.ValidateSignedIdentity(requirement); err != nil {
+			p.Spec = append(p.Spec, fmt.Sprintf("%s: signedIdentity: %v", prefix, err))
+		}
+		if requirement.KeySecret == "" {
+			p.Spec = append(p.Spec, fmt.Sprintf("%s: keySecret is missing in signedBy requirement", prefix))
+			continue
+		}
+		secretNamespace := namespace
+		if requirement.KeySecretNamespace != "" {
+			secretNamespace = requirement.KeySecretNamespace
+		}
+		if secretNamespace == "" {
+			continue
+		}
+		key, err := c.kubeWrapper.GetSecretKey(secretNamespace, requirement.KeySecret)
+		if err != nil {
+			p.Secrets = append(p.Secrets, fmt.Sprintf("%s: key secret: %v", prefix, err))
+			continue
+		}
+		if err := simple.ParseKey(key); err != nil {
+			p.Keys = append(p.Keys, fmt.Sprintf("%s: key secret %q: %v", prefix, requirement.KeySecret, err))
+		}
+	}
+	if policy.StoreURL != "" {
+		if u, err := url.Parse(policy.StoreURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			p.Spec = append(p.Spec, fmt.Sprintf("%s: storeURL %q is not an https:// or http:// URL", prefix, policy.StoreURL))
+		}
+	}
+	storeNamespace := namespace
+	if policy.StoreSecretNamespace != "" {
+		storeNamespace = policy.StoreSecretNamespace
+	}
+	if policy.StoreSecret != "" && storeNamespace != "" {
+		if _, _, err := c.kubeWrapper.GetBasicCredentials(storeNamespace, policy.StoreSecret); err != nil {
+			p.Secrets = append(p.Secrets, fmt.Sprintf("%s: store secret: %v", prefix, err))
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	listersv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/client/listers/portieris.cloud.ibm.com/v1"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func publicKeyPEM(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func secret(namespace, name string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func newChecker(t *testing.T, objects []runtime.Object, profiles ...*policyv1.ImagePolicyProfile) Checker {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, profile := range profiles {
+		require.NoError(t, indexer.Add(profile))
+	}
+	return NewChecker(kubernetes.NewKubeClientsetWrapper(k8sfake.NewSimpleClientset(objects...)), listersv1.NewImagePolicyProfileLister(indexer))
+}
+
+func TestChecker_Check(t *testing.T) {
+	objects := []runtime.Object{
+		secret("team-a", "signer", map[string]string{"name": "alice", "publicKey": string(publicKeyPEM(t))}),
+		secret("team-a", "empty-signer", map[string]string{"name": "bob"}),
+		secret("team-a", "bad-signer", map[string]string{"name": "carol", "publicKey": "not a key"}),
+		secret("team-a", "bad-key", map[string]string{"key": "not a key"}),
+		secret("team-a", "no-key", map[string]string{"other": "value"}),
+	}
+	profile := &policyv1.ImagePolicyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "signed"},
+		Spec: policyv1.ImagePolicyProfileSpec{Policy: policyv1.Policy{
+			Trust: policyv1.Trust{SignerSecrets: []policyv1.TrustSigner{{Name: "missing"}}},
+		}},
+	}
+	tests := []struct {
+		name      string
+		namespace string
+		repo      policyv1.Repository
+		want      Problems
+	}{
+		{
+			name:      "valid trust policy",
+			namespace: "team-a",
+			repo: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Trust: policyv1.Trust{
+				TrustServer:   "https://notary.icr.io",
+				SignerSecrets: []policyv1.TrustSigner{{Name: "signer"}},
+			}}},
+		},
+		{
+			name:      "trust server is not https",
+			namespace: "team-a",
+			repo:      policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Trust: policyv1.Trust{TrustServer: "notary.icr.io"}}},
+			want:      Problems{Spec: []string{`repository "icr.io/*": trustServer "notary.icr.io" is not an https URL`}},
+		},
+		{
+			name:      "signer secrets",
+			namespace: "team-a",
+			repo: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Trust: policyv1.Trust{
+				SignerSecrets: []policyv1.TrustSigner{{Name: "missing"}, {Name: "empty-signer"}, {Name: "bad-signer"}},
+			}}},
+			want: Problems{
+				Secrets: []string{
+					`repository "icr.io/*": signer secret: secrets "missing" not found`,
+					`repository "icr.io/*": signer secret "empty-signer": name or publicKey is empty`,
+				},
+				Keys: []string{`repository "icr.io/*": signer secret "bad-signer": no valid public key found`},
+			},
+		},
+		{
+			name: "cluster policy does not check secrets in workload namespaces",
+			repo: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Trust: policyv1.Trust{
+				SignerSecrets: []policyv1.TrustSigner{{Name: "missing"}},
+			}}},
+		},
+		{
+			name: "cluster policy checks signer secrets in a named namespace",
+			repo: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Trust: policyv1.Trust{
+				SignerSecrets: []policyv1.TrustSigner{{Name: "signer", Namespace: "team-a"}, {Name: "missing", Namespace: "team-a"}},
+			}}},
+			want: Problems{Secrets: []string{`repository "icr.io/*": signer secret: secrets "missing" not found`}},
+		},
+		{
+			name: "simple requirements",
+			repo: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Simple: policyv1.Simple{
+				Requirements: []policyv1.SimpleRequirement{
+					{Type: "accept"},
+					{Type: "signedBy"},
+					{Type: "signedBy", KeySecret: "bad-key", KeySecretNamespace: "team-a", SignedIdentity: policyv1.IdentityRequirement{Type: "exact"}},
+					{Type: "signedBy", KeySecret: "no-key", KeySecretNamespace: "team-a"},
+					{Type: "signedBy", KeySecret: "workload-key"},
+				},
+			}}},
+			want: Problems{
+				Spec: []string{
+					`repository "icr.io/*": simple requirement type "accept" is invalid`,
+					`repository "icr.io/*": keySecret is missing in signedBy requirement`,
+					`repository "icr.io/*": signedIdentity: invalid SignedIdentity Type: exact`,
+				},
+				Secrets: []string{`repository "icr.io/*": key secret: secret "no-key" in "team-a" does not contain a "key" attribute`},
+				Keys:    []string{`repository "icr.io/*": key secret "bad-key": Unable to decode key: EOF`},
+			},
+		},
+		{
+			name:      "malformed signedIdentity",
+			namespace: "team-a",
+			repo: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Simple: policyv1.Simple{
+				Requirements: []policyv1.SimpleRequirement{
+					{Type: "signedBy", KeySecret: "bad-key", SignedIdentity: policyv1.IdentityRequirement{Type: "matchExactRepository", DockerRepository: "not a repository"}},
+				},
+			}}},
+			want: Problems{
+				Spec: []string{`repository "icr.io/*": signedIdentity: Invalid format of dockerRepository "not a repository": invalid reference format`},
+				Keys: []string{`repository "icr.io/*": key secret "bad-key": Unable to decode key: EOF`},
+			},
+		},
+		{
+			name:      "storeURL scheme",
+			namespace: "team-a",
+			repo:      policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Simple: policyv1.Simple{StoreURL: "ftp://sigstore.example.com"}}},
+			want:      Problems{Spec: []string{`repository "icr.io/*": storeURL "ftp://sigstore.example.com" is not an https:// or http:// URL`}},
+		},
+		{
+			name:      "store secret",
+			namespace: "team-a",
+			repo:      policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Simple: policyv1.Simple{StoreURL: "https://sigstore.example.com", StoreSecret: "missing"}}},
+			want:      Problems{Secrets: []string{`repository "icr.io/*": store secret: secrets "missing" not found`}},
+		},
+		{
+			name: "cluster policy checks the store secret in a named namespace",
+			repo: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Simple: policyv1.Simple{
+				StoreURL: "https://sigstore.example.com", StoreSecret: "missing", StoreSecretNamespace: "team-a",
+			}}},
+			want: Problems{Secrets: []string{`repository "icr.io/*": store secret: secrets "missing" not found`}},
+		},
+		{
+			name:      "image policy can't reference secrets in other namespaces",
+			namespace: "team-b",
+			repo: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{
+				Trust: policyv1.Trust{SignerSecrets: []policyv1.TrustSigner{{Name: "signer", Namespace: "team-a"}}},
+				Simple: policyv1.Simple{
+					Requirements:         []policyv1.SimpleRequirement{{Type: "signedBy", KeySecret: "bad-key", KeySecretNamespace: "team-a"}},
+					StoreURL:             "https://sigstore.example.com",
+					StoreSecret:          "store",
+					StoreSecretNamespace: "team-a",
+				},
+			}},
+			want: Problems{
+				Spec: []string{
+					`repository "icr.io/*": signer secret "signer" in namespace "team-a" can only be referenced by a ClusterImagePolicy`,
+					`repository "icr.io/*": key secret "bad-key" in namespace "team-a" can only be referenced by a ClusterImagePolicy`,
+					`repository "icr.io/*": store secret "store" in namespace "team-a" can only be referenced by a ClusterImagePolicy`,
+				},
+				Secrets: []string{
+					`repository "icr.io/*": signer secret: secrets "signer" not found`,
+					`repository "icr.io/*": key secret: secrets "bad-key" not found`,
+					`repository "icr.io/*": store secret: secrets "store" not found`,
+				},
+			},
+		},
+		{
+			name:      "image policy can reference secrets in its own namespace",
+			namespace: "team-a",
+			repo: policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Trust: policyv1.Trust{
+				SignerSecrets: []policyv1.TrustSigner{{Name: "signer", Namespace: "team-a"}},
+			}}},
+		},
+		{
+			name:      "empty repository name",
+			namespace: "team-a",
+			repo:      policyv1.Repository{Policy: policyv1.Policy{Trust: policyv1.Trust{TrustServer: "notary.icr.io"}}},
+			want:      Problems{Spec: []string{"repository 0: name is empty"}},
+		},
+		{
+			name:      "invalid tags pattern",
+			namespace: "team-a",
+			repo:      policyv1.Repository{Name: "icr.io/*", Policy: policyv1.Policy{Tags: policyv1.Tags{Pattern: "v[0-9"}}},
+			want: Problems{Spec: []string{
+				"repository \"icr.io/*\": tags pattern \"v[0-9\" is invalid: error parsing regexp: missing closing ]: `[0-9`",
+			}},
+		},
+		{
+			name:      "invalid regexp name and exclude",
+			namespace: "team-a",
+			repo:      policyv1.Repository{Name: `icr\.io/(`, NameType: policyv1.NameTypeRegexp, Excludes: []string{`icr\.io/team-a/.+`, "[a-"}},
+			want: Problems{Spec: []string{
+				"repository \"icr\\\\.io/(\": regexp \"icr\\\\.io/(\" is invalid: error parsing regexp: missing closing ): `icr\\.io/(`",
+				"repository \"icr\\\\.io/(\": regexp \"[a-\" is invalid: error parsing regexp: missing closing ]: `[a-`",
+			}},
+		},
+		{
+			name:      "invalid name type",
+			namespace: "team-a",
+			repo:      policyv1.Repository{Name: "icr.io/*", NameType: "glob"},
+			want:      Problems{Spec: []string{`repository "icr.io/*": nameType "glob" is invalid`}},
+		},
+		{
+			name:      "profile is checked",
+			namespace: "team-a",
+			repo:      policyv1.Repository{Name: "icr.io/*", Profile: "signed"},
+			want:      Problems{Secrets: []string{`repository "icr.io/*": signer secret: secrets "missing" not found`}},
+		},
+		{
+			name:      "missing profile",
+			namespace: "team-a",
+			repo:      policyv1.Repository{Name: "icr.io/*", Profile: "missing"},
+			want:      Problems{Spec: []string{`repository "icr.io/*": ImagePolicyProfile "missing": imagepolicyprofile.portieris.cloud.ibm.com "missing" not found`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newChecker(t, objects, profile)
+			got := c.Check(tt.namespace, policyv1.ImagePolicySpec{Repositories: []policyv1.Repository{tt.repo}})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChecker_CheckRego(t *testing.T) {
+	c := newChecker(t, nil)
+	spec := policyv1.ImagePolicySpec{Rego: &policyv1.Rego{ConfigMap: &policyv1.ConfigMapKeyReference{Namespace: "portieris", Name: "rules", Key: "policy.rego"}}}
+	got := c.Check("", spec)
+	assert.Equal(t, Problems{Spec: []string{`rego: configmaps "rules" not found`}}, got)
+}
@@ -1,4 +1,4 @@
-// Copyright 2018, 2022 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,6 +15,7 @@
 package fakeregistry
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -41,7 +42,7 @@ type FakeRegistry struct {
 }
 
 // GetContentTrustToken ...
-func (fake *FakeRegistry) GetContentTrustToken(oauthEndpoint string, username string, password string, service string, scope string) (string, error) {
+func (fake *FakeRegistry) GetContentTrustToken(ctx context.Context, oauthEndpoint string, username string, password string, service string, scope string) (string, error) {
 	fake.getContentTrustTokenMutex.Lock()
 	fake.getContentTrustTokenArgsForCall = append(fake.getContentTrustTokenArgsForCall, struct {
 		oauthEndpoint string
@@ -76,6 +77,6 @@ func (fake *FakeRegistry) GetContentTrustTokenReturns(token string, err error) {
 }
 
 // GetRegistryToken ...
-func (fake *FakeRegistry) GetRegistryToken(oauthEndpoint string, username string, password string, service string, scope string) (string, error) {
+func (fake *FakeRegistry) GetRegistryToken(ctx context.Context, oauthEndpoint string, username string, password string, service string, scope string) (string, error) {
 	return "", fmt.Errorf("not implemented")
 }
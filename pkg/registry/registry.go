@@ -1,4 +1,4 @@
-// Copyright 2018, 2022 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,30 +15,35 @@
 package registry
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/IBM/portieris/helpers/oauth"
 )
 
 // Client .
-type Client struct{}
+type Client struct {
+	httpClient *http.Client
+}
 
 // Interface .
 type Interface interface {
-	GetContentTrustToken(oauthEndpoint string, username string, password string, service string, scope string) (string, error)
-	GetRegistryToken(oauthEndpoint string, username string, password string, service string, scope string) (string, error)
+	GetContentTrustToken(ctx context.Context, oauthEndpoint string, username string, password string, service string, scope string) (string, error)
+	GetRegistryToken(ctx context.Context, oauthEndpoint string, username string, password string, service string, scope string) (string, error)
 }
 
-// NewClient .
-func NewClient() Interface {
-	return &Client{}
+// NewClient returns a client that requests tokens with httpClient
+func NewClient(httpClient *http.Client) Interface {
+	return &Client{httpClient: httpClient}
 }
 
 // GetContentTrustToken .
-func (c Client) GetContentTrustToken(oauthEndpoint string, username string, password string, service string, scope string) (string, error) {
+func (c Client) GetContentTrustToken(ctx context.Context, oauthEndpoint string, username string, password string, service string, scope string) (string, error) {
 	if username == "" && password == "" {
 		return "", nil
 	}
 
-	token, err := oauth.Request(oauthEndpoint, username, password, service, scope)
+	token, err := oauth.Request(ctx, c.httpClient, oauthEndpoint, username, password, service, scope)
 	if err != nil {
 		return "", err
 	}
@@ -46,8 +51,8 @@ func (c Client) GetContentTrustToken(oauthEndpoint string, username string, pass
 }
 
 // GetRegistryToken .
-func (c Client) GetRegistryToken(oauthEndpoint string, username string, password string, service string, scope string) (string, error) {
-	token, err := oauth.Request(oauthEndpoint, username, password, "registry", scope)
+func (c Client) GetRegistryToken(ctx context.Context, oauthEndpoint string, username string, password string, service string, scope string) (string, error) {
+	token, err := oauth.Request(ctx, c.httpClient, oauthEndpoint, username, password, "registry", scope)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,100 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/portieris/helpers/credential"
+	"github.com/IBM/portieris/internal/info"
+	"github.com/IBM/portieris/pkg/transport"
+	"github.com/golang/glog"
+	"go.podman.io/image/v5/docker"
+	"go.podman.io/image/v5/types"
+)
+
+// NewSystemContext returns a containers/image system context that reads no configuration from files. containers/image
+// creates the HTTP transport of each registry client itself, so requests for simple signing, image configuration and
+// digests don't share the connections of t, but they use its proxy and trusted CAs and are counted by its metrics.
+// t may be nil, then the proxy is read from the environment and the system CAs are trusted.
+func NewSystemContext(t *transport.Transport) *types.SystemContext {
+	systemContext := &types.SystemContext{
+		RootForImplicitAbsolutePaths: "/nowhere", // read nothing from files
+		DockerRegistryUserAgent:      "portieris/" + info.Version,
+	}
+	if t != nil {
+		systemContext.BaseTLSConfig = t.TLSConfig()
+		systemContext.DockerProxy = t.Proxy
+	}
+	return systemContext
+}
+
+// NewImageSource opens the image in its registry, anonymously when allowed otherwise with the first of the
+// credentials that is accepted, the caller must close the returned source
+func NewImageSource(ctx context.Context, imageName string, credentials credential.Credentials, systemContext *types.SystemContext) (types.ImageSource, error) {
+	imageReference, err := docker.ParseReference(`//` + imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	imageSource, err := imageReference.NewImageSource(ctx, systemContext)
+	if err == nil {
+		glog.Infof("Registry: anonymous access allowed for image %s", imageName)
+		return imageSource, nil
+	}
+	glog.Errorf("Registry: anonymous access denied for image %s, continuing with ImagePullSecrets... Error %v", imageName, err)
+
+	numCreds := len(credentials)
+	for i, credential := range credentials {
+		authContext := *systemContext
+		authContext.DockerAuthConfig = &types.DockerAuthConfig{
+			Username: credential.Username,
+			Password: credential.Password,
+		}
+		imageSource, err := imageReference.NewImageSource(ctx, &authContext)
+		if err != nil {
+			glog.Warningf("Registry: ImagePullSecret with username %s for image %s failed (secret %d/%d). Error %v", credential.Username, imageName, i+1, numCreds, err)
+			continue
+		}
+		glog.Infof("Registry: ImagePullSecret with username %s for image %s was valid (secret %d/%d)", credential.Username, imageName, i+1, numCreds)
+		return imageSource, nil
+	}
+
+	return nil, fmt.Errorf("no access to %q, anonymous and %d ImagePullSecrets tried: %v", imageName, numCreds, err)
+}
+
+// GetDigest returns the hex digest of the manifest that the image name refers to in its registry, anonymously
+// when allowed otherwise with the first of the credentials that is accepted
+func GetDigest(ctx context.Context, imageName string, credentials credential.Credentials, systemContext *types.SystemContext) (string, error) {
+	imageReference, err := docker.ParseReference(`//` + imageName)
+	if err != nil {
+		return "", err
+	}
+
+	manifestDigest, err := docker.GetDigest(ctx, systemContext, imageReference)
+	for i := 0; err != nil && i < len(credentials); i++ {
+		authContext := *systemContext
+		authContext.DockerAuthConfig = &types.DockerAuthConfig{
+			Username: credentials[i].Username,
+			Password: credentials[i].Password,
+		}
+		manifestDigest, err = docker.GetDigest(ctx, &authContext, imageReference)
+	}
+	if err != nil {
+		return "", fmt.Errorf("no access to the digest of %q, anonymous and %d ImagePullSecrets tried: %v", imageName, len(credentials), err)
+	}
+	return manifestDigest.Encoded(), nil
+}
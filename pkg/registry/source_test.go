@@ -0,0 +1,66 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/IBM/portieris/pkg/transport"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const manifestDigest = "sha256:1234567890123456789012345678901234567890123456789012345678901234"
+
+func TestGetDigest(t *testing.T) {
+	// a registry whose certificate is signed by a CA that only the transport trusts
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("User-Agent"), "portieris/"))
+		switch r.URL.Path {
+		case "/v2/":
+		case "/v2/hello/world/manifests/latest":
+			w.Header().Set("Docker-Content-Digest", manifestDigest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	pm := metrics.NewMetrics()
+	defer pm.UnregisterAll()
+	config := transport.DefaultConfig()
+	config.CAFile = caFile
+	tr, err := transport.New(config, pm)
+	require.NoError(t, err)
+	imageName := strings.TrimPrefix(server.URL, "https://") + "/hello/world:latest"
+
+	_, err = GetDigest(context.Background(), imageName, nil, NewSystemContext(nil))
+	assert.Error(t, err, "the CA is not trusted without the transport")
+
+	digest, err := GetDigest(context.Background(), imageName, nil, NewSystemContext(tr))
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimPrefix(manifestDigest, "sha256:"), digest)
+	assert.Equal(t, 2.0, testutil.ToFloat64(pm.HTTPRegistryRequestCount), "the ping and the manifest request are counted")
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/IBM/portieris/helpers/useragent"
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/golang/glog"
+)
+
+// Config configures the HTTP transport that is shared by the clients of notary servers, registry token services and
+// vulnerability scanners, so that their connections are kept alive and reused across admission requests
+type Config struct {
+	// CAFile is a PEM file of certificate authorities that are trusted in addition to the system pool, it is ignored when it doesn't exist
+	CAFile string
+	// MaxIdleConnsPerHost is the number of idle connections to each host that are kept for reuse
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the connections to each host, including those in use, zero is no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept for reuse, zero is no limit
+	IdleConnTimeout time.Duration
+}
+
+// DefaultConfig returns the configuration used when none is given
+func DefaultConfig() Config {
+	return Config{
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// Transport is an http.RoundTripper that pools connections, sets the Portieris user agent on requests, and counts
+// the connections that are created and reused when it has metrics
+type Transport struct {
+	base      *http.Transport
+	userAgent http.RoundTripper
+	pm        *metrics.PortierisMetrics
+}
+
+// New creates a transport with the configuration, proxies are read from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+// environment variables, as they are for registry requests made by containers/image. pm may be nil.
+func New(config Config, pm *metrics.PortierisMetrics) (*Transport, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, err
+	}
+	if config.CAFile != "" {
+		ca, err := os.ReadFile(config.CAFile)
+		switch {
+		case os.IsNotExist(err):
+			glog.Infof("CA not provided at %s, will use default system pool", config.CAFile)
+		case err != nil:
+			return nil, fmt.Errorf("could not read %s: %v", config.CAFile, err)
+		default:
+			rootCAs.AppendCertsFromPEM(ca)
+		}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	base := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig: &tls.Config{
+			// Avoid fallback by default to SSL protocols < TLS1.2
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    rootCAs,
+		},
+	}
+	return &Transport{
+		base:      base,
+		userAgent: &useragent.Set{Transport: base},
+		pm:        pm,
+	}, nil
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the request is cloned because a RoundTripper must not modify the request it is given
+	return t.userAgent.RoundTrip(req.Clone(t.trace(req.Context())))
+}
+
+// trace counts whether the connection that a request is sent on is new or reused
+func (t *Transport) trace(ctx context.Context) context.Context {
+	if t.pm == nil {
+		return ctx
+	}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				t.pm.HTTPConnectionReusedCount.Inc()
+			} else {
+				t.pm.HTTPConnectionNewCount.Inc()
+			}
+		},
+	})
+}
+
+// Client returns a client that sends requests with the transport, timeout limits the time of each request including
+// reading the response, zero is no limit other than the context of the request
+func (t *Transport) Client(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: t,
+		Timeout:   timeout,
+	}
+}
+
+// CloseIdleConnections closes the connections that are kept for reuse
+func (t *Transport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
+
+// TLSConfig returns a copy of the TLS configuration of the transport, which trusts the CAs of its configuration, for
+// clients that create their own transport
+func (t *Transport) TLSConfig() *tls.Config {
+	return t.base.TLSClientConfig.Clone()
+}
+
+// Proxy returns the proxy of the transport for a request to reqURL, for clients that create their own transport, and
+// counts the request when the transport has metrics
+func (t *Transport) Proxy(reqURL *url.URL) (*url.URL, error) {
+	if t.pm != nil {
+		t.pm.HTTPRegistryRequestCount.Inc()
+	}
+	return t.base.Proxy(&http.Request{URL: reqURL})
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IBM/portieris/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, client *http.Client, url string) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(io.Discard, resp.Body)
+	assert.Empty(t, req.Header.Get("User-Agent"), "the request is not modified")
+	return err
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "portieris/undefined", r.Header.Get("User-Agent"))
+	}))
+	defer server.Close()
+	pm := metrics.NewMetrics()
+	defer pm.UnregisterAll()
+	tr, err := New(DefaultConfig(), pm)
+	require.NoError(t, err)
+	client := tr.Client(time.Minute)
+
+	require.NoError(t, get(t, client, server.URL))
+	require.NoError(t, get(t, client, server.URL))
+	assert.Equal(t, 1.0, testutil.ToFloat64(pm.HTTPConnectionNewCount))
+	assert.Equal(t, 1.0, testutil.ToFloat64(pm.HTTPConnectionReusedCount))
+
+	tr.CloseIdleConnections()
+	require.NoError(t, get(t, client, server.URL))
+	assert.Equal(t, 2.0, testutil.ToFloat64(pm.HTTPConnectionNewCount))
+}
+
+func TestNew(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	t.Run("trusts the CA file", func(t *testing.T) {
+		tr, err := New(Config{CAFile: caFile, MaxConnsPerHost: 2}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, tr.base.MaxConnsPerHost)
+		assert.NoError(t, get(t, tr.Client(time.Minute), server.URL))
+	})
+
+	t.Run("uses the system pool when the CA file doesn't exist", func(t *testing.T) {
+		tr, err := New(Config{CAFile: filepath.Join(dir, "missing.pem")}, nil)
+		require.NoError(t, err)
+		assert.Error(t, get(t, tr.Client(time.Minute), server.URL))
+	})
+
+	t.Run("fails when the CA file can't be read", func(t *testing.T) {
+		_, err := New(Config{CAFile: dir}, nil)
+		assert.Error(t, err)
+	})
+}
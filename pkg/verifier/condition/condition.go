@@ -0,0 +1,180 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/verifier/imageconfig"
+	"github.com/google/cel-go/cel"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// costLimit bounds the work of a single expression so a policy can not stall admission
+const costLimit = 1000000
+
+// maxPrograms bounds the compiled expressions that are kept, an expression that changes is kept under a new key
+// so the cache is emptied when it is full rather than tracking use
+const maxPrograms = 256
+
+var (
+	programsLock sync.Mutex
+	programs     = map[string]cel.Program{}
+)
+
+// Input is the context that the conditions of a policy are evaluated against
+type Input struct {
+	// Image is the image as referenced by the container
+	Image *image.Reference
+	// Digest is the digest verified by the policy, without the sha256: prefix, empty when no digest was verified
+	Digest string
+	// Pod is the metadata of the pod, or of the pod template of a workload
+	Pod metav1.ObjectMeta
+	// ServiceAccountName is the service account of the pod
+	ServiceAccountName string
+	// Namespace is the namespace of the admission request
+	Namespace string
+	// NamespaceLabels are the labels of the namespace
+	NamespaceLabels map[string]string
+	// Policy is the policy that was enforced
+	Policy *policyv1.Policy
+	// VulnerabilityAllowed is false when a vulnerability scanner denied the image
+	VulnerabilityAllowed bool
+}
+
+var env = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("image", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("digest", cel.StringType),
+		cel.Variable("pod", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("namespaceObject", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("policy", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("results", cel.MapType(cel.StringType, cel.BoolType)),
+	)
+})
+
+// VerifyByPolicy evaluates each condition against the input, it returns the reason for denial or nil.
+// An expression that is invalid, or can not be evaluated, denies the image.
+func VerifyByPolicy(input Input, conditions []policyv1.Condition) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+	e, err := env()
+	if err != nil {
+		return fmt.Errorf("unable to create the expression environment: %v", err)
+	}
+	activation, err := input.Variables()
+	if err != nil {
+		return err
+	}
+	for _, condition := range conditions {
+		program, err := compile(e, condition.Expression)
+		if err != nil {
+			return fmt.Errorf("condition %q is invalid: %v", condition.Expression, err)
+		}
+		out, _, err := program.Eval(activation)
+		if err != nil {
+			return fmt.Errorf("condition %q can not be evaluated: %v", condition.Expression, err)
+		}
+		if allowed, ok := out.Value().(bool); !ok || !allowed {
+			if condition.Message != "" {
+				return fmt.Errorf("%s", condition.Message)
+			}
+			return fmt.Errorf("condition %q is not satisfied", condition.Expression)
+		}
+	}
+	return nil
+}
+
+// compile parses, checks and plans an expression once for each distinct expression
+func compile(e *cel.Env, expression string) (cel.Program, error) {
+	programsLock.Lock()
+	defer programsLock.Unlock()
+	if program, ok := programs[expression]; ok {
+		return program, nil
+	}
+	ast, issues := e.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("result is %v, not bool", ast.OutputType())
+	}
+	program, err := e.Program(ast, cel.CostLimit(costLimit))
+	if err != nil {
+		return nil, err
+	}
+	if len(programs) >= maxPrograms {
+		programs = map[string]cel.Program{}
+	}
+	programs[expression] = program
+	return program, nil
+}
+
+// Variables returns the variables that expressions can use, they are also the input document of a Rego module
+func (input Input) Variables() (map[string]interface{}, error) {
+	policy := map[string]interface{}{}
+	results := map[string]bool{"vulnerability": input.VulnerabilityAllowed}
+	if input.Policy != nil {
+		raw, err := json.Marshal(input.Policy)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &policy); err != nil {
+			return nil, err
+		}
+		results["trust"] = input.Policy.Trust.Enabled != nil && *input.Policy.Trust.Enabled
+		results["simple"] = len(input.Policy.Simple.Requirements) > 0
+		results["config"] = imageconfig.Enabled(input.Policy)
+	}
+	digest := ""
+	if input.Digest != "" {
+		digest = "sha256:" + input.Digest
+	}
+	return map[string]interface{}{
+		"image": map[string]string{
+			"reference":  input.Image.String(),
+			"hostname":   input.Image.GetHostname(),
+			"repository": input.Image.NameWithoutTag(),
+			"tag":        input.Image.GetTag(),
+			"digest":     input.Image.GetDigest(),
+		},
+		"digest": digest,
+		"pod": map[string]interface{}{
+			"name":               input.Pod.Name,
+			"labels":             stringMap(input.Pod.Labels),
+			"annotations":        stringMap(input.Pod.Annotations),
+			"serviceAccountName": input.ServiceAccountName,
+		},
+		"namespaceObject": map[string]interface{}{
+			"name":   input.Namespace,
+			"labels": stringMap(input.NamespaceLabels),
+		},
+		"policy":  policy,
+		"results": results,
+	}, nil
+}
+
+// stringMap avoids a null value for a missing map, so expressions can always test for keys
+func stringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package condition
+
+import (
+	"testing"
+
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVerifyByPolicy(t *testing.T) {
+	img, err := image.NewReference("icr.io/team/app:1.2.3")
+	require.NoError(t, err)
+	trust := true
+	input := func(namespaceLabels map[string]string, policy *policyv1.Policy) Input {
+		return Input{
+			Image:                img,
+			Digest:               "1234",
+			Pod:                  metav1.ObjectMeta{Name: "app", Labels: map[string]string{"app": "web"}},
+			ServiceAccountName:   "deployer",
+			Namespace:            "team",
+			NamespaceLabels:      namespaceLabels,
+			Policy:               policy,
+			VulnerabilityAllowed: true,
+		}
+	}
+	prodRequiresTrust := policyv1.Condition{
+		Expression: "!(has(namespaceObject.labels.env) && namespaceObject.labels.env == 'prod') || results.trust",
+		Message:    "prod namespaces require trust",
+	}
+
+	tests := []struct {
+		name       string
+		input      Input
+		conditions []policyv1.Condition
+		wantDeny   string
+	}{
+		{
+			name:  "No conditions allows",
+			input: input(nil, &policyv1.Policy{}),
+		},
+		{
+			name:       "Prod namespace without trust is denied with the message",
+			input:      input(map[string]string{"env": "prod"}, &policyv1.Policy{}),
+			conditions: []policyv1.Condition{prodRequiresTrust},
+			wantDeny:   "prod namespaces require trust",
+		},
+		{
+			name:       "Prod namespace with trust is allowed",
+			input:      input(map[string]string{"env": "prod"}, &policyv1.Policy{Trust: policyv1.Trust{Enabled: &trust}}),
+			conditions: []policyv1.Condition{prodRequiresTrust},
+		},
+		{
+			name:       "Namespace without labels is allowed",
+			input:      input(nil, &policyv1.Policy{}),
+			conditions: []policyv1.Condition{prodRequiresTrust},
+		},
+		{
+			name:  "Image, pod and digest variables are set",
+			input: input(nil, &policyv1.Policy{}),
+			conditions: []policyv1.Condition{
+				{Expression: "image.hostname == 'icr.io' && image.repository == 'icr.io/team/app' && image.tag == '1.2.3'"},
+				{Expression: "pod.labels.app == 'web' && pod.serviceAccountName == 'deployer' && namespaceObject.name == 'team'"},
+				{Expression: "digest == 'sha256:1234' && image.digest == ''"},
+			},
+		},
+		{
+			name:  "Policy is available as a map",
+			input: input(nil, &policyv1.Policy{Tags: policyv1.Tags{Pattern: "v.*"}}),
+			conditions: []policyv1.Condition{
+				{Expression: "has(policy.tags.pattern)"},
+			},
+		},
+		{
+			name:       "Unsatisfied condition without a message names the expression",
+			input:      input(nil, &policyv1.Policy{}),
+			conditions: []policyv1.Condition{{Expression: "image.tag != '1.2.3'"}},
+			wantDeny:   `condition "image.tag != '1.2.3'" is not satisfied`,
+		},
+		{
+			name:       "Conditions are all required",
+			input:      input(nil, &policyv1.Policy{}),
+			conditions: []policyv1.Condition{{Expression: "true"}, {Expression: "false", Message: "second"}},
+			wantDeny:   "second",
+		},
+		{
+			name:       "Expression that is not bool is denied",
+			input:      input(nil, &policyv1.Policy{}),
+			conditions: []policyv1.Condition{{Expression: "image.tag"}},
+			wantDeny:   `condition "image.tag" is invalid: result is string, not bool`,
+		},
+		{
+			name:       "Missing key is denied",
+			input:      input(nil, &policyv1.Policy{}),
+			conditions: []policyv1.Condition{{Expression: "namespaceObject.labels.env == 'prod'"}},
+			wantDeny:   `condition "namespaceObject.labels.env == 'prod'" can not be evaluated: no such key: env`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deny := VerifyByPolicy(tt.input, tt.conditions)
+			if tt.wantDeny == "" {
+				assert.NoError(t, deny)
+			} else {
+				assert.EqualError(t, deny, tt.wantDeny)
+			}
+		})
+	}
+
+	t.Run("Invalid expression is denied", func(t *testing.T) {
+		deny := VerifyByPolicy(input(nil, &policyv1.Policy{}), []policyv1.Condition{{Expression: "image.tag =="}})
+		require.Error(t, deny)
+		assert.Contains(t, deny.Error(), `condition "image.tag ==" is invalid`)
+	})
+}
+
+func TestCompile(t *testing.T) {
+	e, err := env()
+	require.NoError(t, err)
+
+	first, err := compile(e, "results.trust")
+	require.NoError(t, err)
+	second, err := compile(e, "results.trust")
+	require.NoError(t, err)
+	assert.Same(t, first, second, "an expression is compiled once")
+
+	_, err = compile(e, "image.tag")
+	assert.EqualError(t, err, "result is string, not bool")
+	assert.NotContains(t, programs, "image.tag", "an invalid expression is not kept")
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IBM/portieris/helpers/image"
+	"github.com/IBM/portieris/helpers/wildcard"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func baseImageEnabled(policy policyv1.BaseImage) bool {
+	return len(policy.Allowed) > 0
+}
+
+// verifyBaseImage checks the base image annotations against the allowed base images and returns the
+// base image reference by digest, when the annotations record it, or the reason for denial
+func verifyBaseImage(inspection *Inspection, policy policyv1.BaseImage) (string, error) {
+	name, ok := inspection.Annotations[imgspecv1.AnnotationBaseImageName]
+	if !ok {
+		return "", fmt.Errorf("required annotation %q is missing, the base image is unknown", imgspecv1.AnnotationBaseImageName)
+	}
+	base, err := image.NewReference(name)
+	if err != nil {
+		return "", fmt.Errorf("base image %q is invalid: %v", name, err)
+	}
+	baseDigest := inspection.Annotations[imgspecv1.AnnotationBaseImageDigest]
+	byDigest := ""
+	if baseDigest != "" {
+		byDigest = base.NameWithoutTag() + "@" + baseDigest
+	}
+
+	allowed := false
+	for _, pattern := range policy.Allowed {
+		if strings.Contains(pattern, "@") {
+			// a digest is only matched exactly, with a wildcard for the name
+			allowed = byDigest != "" && wildcard.Compare(pattern, byDigest)
+		} else {
+			allowed = wildcard.CompareImageRef(pattern, base.NameWithTag())
+		}
+		if allowed {
+			break
+		}
+	}
+	if !allowed {
+		return "", fmt.Errorf("base image %q is not an allowed base image", name)
+	}
+	if policy.VerifyLayers != nil && *policy.VerifyLayers && byDigest == "" {
+		return "", fmt.Errorf("required annotation %q is missing, the base image layers can not be verified", imgspecv1.AnnotationBaseImageDigest)
+	}
+	return byDigest, nil
+}
+
+// verifyBaseLayers checks that the image was built on the base image, the base layers must be the first layers of the image
+func verifyBaseLayers(inspection, base *Inspection) error {
+	if len(base.LayerDigests) > len(inspection.LayerDigests) {
+		return fmt.Errorf("image has fewer layers than base image")
+	}
+	for i, layer := range base.LayerDigests {
+		if inspection.LayerDigests[i] != layer {
+			return fmt.Errorf("image layer %d is %s, base image layer is %s", i+1, inspection.LayerDigests[i], layer)
+		}
+	}
+	return nil
+}
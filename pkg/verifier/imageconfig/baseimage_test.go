@@ -0,0 +1,137 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageconfig
+
+import (
+	"testing"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyBaseImage(t *testing.T) {
+	verifyLayers := true
+	baseName := "org.opencontainers.image.base.name"
+	baseDigest := "org.opencontainers.image.base.digest"
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		policy      policyv1.BaseImage
+		wantBase    string
+		wantDeny    string
+	}{
+		{
+			name:        "Missing base name is denied",
+			annotations: map[string]string{},
+			policy:      policyv1.BaseImage{Allowed: []string{"*"}},
+			wantDeny:    `required annotation "org.opencontainers.image.base.name" is missing, the base image is unknown`,
+		},
+		{
+			name:        "Allowed base name matches",
+			annotations: map[string]string{baseName: "icr.io/golden/ubi:9.4", baseDigest: "sha256:1234"},
+			policy:      policyv1.BaseImage{Allowed: []string{"icr.io/golden/*"}},
+			wantBase:    "icr.io/golden/ubi@sha256:1234",
+		},
+		{
+			name:        "Allowed base name without a tag matches any tag",
+			annotations: map[string]string{baseName: "icr.io/golden/ubi:9.4"},
+			policy:      policyv1.BaseImage{Allowed: []string{"icr.io/golden/ubi"}},
+		},
+		{
+			name:        "Docker Hub short names are normalized",
+			annotations: map[string]string{baseName: "alpine:3.19"},
+			policy:      policyv1.BaseImage{Allowed: []string{"docker.io/library/alpine:3.*"}},
+		},
+		{
+			name:        "Base name that does not match is denied",
+			annotations: map[string]string{baseName: "docker.io/library/alpine:3.19"},
+			policy:      policyv1.BaseImage{Allowed: []string{"icr.io/golden/*"}},
+			wantDeny:    `base image "docker.io/library/alpine:3.19" is not an allowed base image`,
+		},
+		{
+			name:        "Allowed base digest matches",
+			annotations: map[string]string{baseName: "icr.io/golden/ubi:9.4", baseDigest: "sha256:1234"},
+			policy:      policyv1.BaseImage{Allowed: []string{"icr.io/golden/ubi@sha256:1234"}},
+			wantBase:    "icr.io/golden/ubi@sha256:1234",
+		},
+		{
+			name:        "Base digest that does not match is denied",
+			annotations: map[string]string{baseName: "icr.io/golden/ubi:9.4", baseDigest: "sha256:5678"},
+			policy:      policyv1.BaseImage{Allowed: []string{"icr.io/golden/ubi@sha256:1234"}},
+			wantDeny:    `base image "icr.io/golden/ubi:9.4" is not an allowed base image`,
+		},
+		{
+			name:        "Allowed digest pattern without a base digest is denied",
+			annotations: map[string]string{baseName: "icr.io/golden/ubi:9.4"},
+			policy:      policyv1.BaseImage{Allowed: []string{"icr.io/golden/ubi@*"}},
+			wantDeny:    `base image "icr.io/golden/ubi:9.4" is not an allowed base image`,
+		},
+		{
+			name:        "Verify layers without a base digest is denied",
+			annotations: map[string]string{baseName: "icr.io/golden/ubi:9.4"},
+			policy:      policyv1.BaseImage{Allowed: []string{"icr.io/golden/*"}, VerifyLayers: &verifyLayers},
+			wantDeny:    `required annotation "org.opencontainers.image.base.digest" is missing, the base image layers can not be verified`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, deny := verifyBaseImage(&Inspection{Annotations: tt.annotations}, tt.policy)
+			if tt.wantDeny == "" {
+				assert.NoError(t, deny)
+				assert.Equal(t, tt.wantBase, base)
+			} else {
+				assert.EqualError(t, deny, tt.wantDeny)
+			}
+		})
+	}
+}
+
+func TestVerifyBaseLayers(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    []string
+		base     []string
+		wantDeny string
+	}{
+		{
+			name:  "Base layers first is allowed",
+			image: []string{"sha256:a", "sha256:b", "sha256:c"},
+			base:  []string{"sha256:a", "sha256:b"},
+		},
+		{
+			name:     "Different base layer is denied",
+			image:    []string{"sha256:a", "sha256:x", "sha256:c"},
+			base:     []string{"sha256:a", "sha256:b"},
+			wantDeny: "image layer 2 is sha256:x, base image layer is sha256:b",
+		},
+		{
+			name:     "Fewer layers than the base is denied",
+			image:    []string{"sha256:a"},
+			base:     []string{"sha256:a", "sha256:b"},
+			wantDeny: "image has fewer layers than base image",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deny := verifyBaseLayers(&Inspection{LayerDigests: tt.image}, &Inspection{LayerDigests: tt.base})
+			if tt.wantDeny == "" {
+				assert.NoError(t, deny)
+			} else {
+				assert.EqualError(t, deny, tt.wantDeny)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/portieris/helpers/wildcard"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+)
+
+// Enabled reports whether the policy has any constraint that needs the image to be inspected
+func Enabled(policy *policyv1.Policy) bool {
+	return configEnabled(policy.Config) || baseImageEnabled(policy.BaseImage) || platformsEnabled(policy.Platforms)
+}
+
+func configEnabled(policy policyv1.Config) bool {
+	return policy.MaxAge != nil || policy.MaxSize != nil || policy.MaxLayers != nil ||
+		(policy.DenyRootUser != nil && *policy.DenyRootUser) ||
+		len(policy.RequiredLabels) > 0 || len(policy.RequiredAnnotations) > 0
+}
+
+// verifyConfig checks the inspected image against the config policy and returns the reason for denial
+func verifyConfig(inspection *Inspection, policy policyv1.Config, now time.Time) error {
+	if policy.MaxAge != nil {
+		if inspection.Created == nil {
+			return fmt.Errorf("image creation time is unknown, maximum age is %v", policy.MaxAge.Duration)
+		}
+		if age := now.Sub(*inspection.Created); age > policy.MaxAge.Duration {
+			return fmt.Errorf("image was created %v ago, maximum age is %v", age.Truncate(time.Second), policy.MaxAge.Duration)
+		}
+	}
+	if policy.MaxSize != nil && inspection.Size > policy.MaxSize.Value() {
+		return fmt.Errorf("image size %d bytes exceeds the maximum %s", inspection.Size, policy.MaxSize.String())
+	}
+	if policy.MaxLayers != nil && inspection.Layers > int(*policy.MaxLayers) {
+		return fmt.Errorf("image has %d layers, maximum is %d", inspection.Layers, *policy.MaxLayers)
+	}
+	if policy.DenyRootUser != nil && *policy.DenyRootUser && isRootUser(inspection.User) {
+		return fmt.Errorf("image runs as root user %q", inspection.User)
+	}
+	if err := requireAll("label", policy.RequiredLabels, inspection.Labels); err != nil {
+		return err
+	}
+	return requireAll("annotation", policy.RequiredAnnotations, inspection.Annotations)
+}
+
+// isRootUser returns true for a user, in the user[:group] form of the image configuration, that runs as uid 0
+func isRootUser(user string) bool {
+	name := strings.SplitN(user, ":", 2)[0]
+	// an empty user defaults to root, a numeric uid may be zero padded
+	return name == "root" || strings.TrimLeft(name, "0") == ""
+}
+
+// requireAll checks that every required key is present with a value matching the wildcard pattern, an empty pattern only requires the key
+func requireAll(kind string, required, actual map[string]string) error {
+	keys := make([]string, 0, len(required))
+	for key := range required {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := actual[key]
+		if !ok {
+			return fmt.Errorf("required %s %q is missing", kind, key)
+		}
+		if pattern := required[key]; pattern != "" && !wildcard.Compare(pattern, value) {
+			return fmt.Errorf("%s %q value %q does not match %q", kind, key, value, pattern)
+		}
+	}
+	return nil
+}
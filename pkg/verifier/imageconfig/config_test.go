@@ -0,0 +1,171 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageconfig
+
+import (
+	"testing"
+	"time"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVerifyConfig(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	created := now.Add(-48 * time.Hour)
+	maxAge := metav1.Duration{Duration: 24 * time.Hour}
+	maxSize := resource.MustParse("100Mi")
+	maxLayers := int32(5)
+	denyRoot := true
+
+	inspection := func(modify func(*Inspection)) *Inspection {
+		i := &Inspection{
+			Digest:      "1234",
+			Created:     &created,
+			Size:        50 * 1024 * 1024,
+			Layers:      3,
+			User:        "1001",
+			Labels:      map[string]string{"org.opencontainers.image.source": "https://github.com/IBM/portieris"},
+			Annotations: map[string]string{"org.opencontainers.image.vendor": "IBM"},
+		}
+		if modify != nil {
+			modify(i)
+		}
+		return i
+	}
+
+	tests := []struct {
+		name       string
+		inspection *Inspection
+		policy     policyv1.Config
+		wantDeny   string
+	}{
+		{
+			name:       "Empty policy allows anything",
+			inspection: inspection(func(i *Inspection) { i.User = "" }),
+		},
+		{
+			name:       "Image older than the maximum age is denied",
+			inspection: inspection(nil),
+			policy:     policyv1.Config{MaxAge: &maxAge},
+			wantDeny:   "image was created 48h0m0s ago, maximum age is 24h0m0s",
+		},
+		{
+			name:       "Image within the maximum age is allowed",
+			inspection: inspection(func(i *Inspection) { c := now.Add(-time.Hour); i.Created = &c }),
+			policy:     policyv1.Config{MaxAge: &maxAge},
+		},
+		{
+			name:       "Image without a creation time is denied a maximum age",
+			inspection: inspection(func(i *Inspection) { i.Created = nil }),
+			policy:     policyv1.Config{MaxAge: &maxAge},
+			wantDeny:   "image creation time is unknown, maximum age is 24h0m0s",
+		},
+		{
+			name:       "Image larger than the maximum size is denied",
+			inspection: inspection(func(i *Inspection) { i.Size = 200 * 1024 * 1024 }),
+			policy:     policyv1.Config{MaxSize: &maxSize},
+			wantDeny:   "image size 209715200 bytes exceeds the maximum 100Mi",
+		},
+		{
+			name:       "Image within the maximum size is allowed",
+			inspection: inspection(nil),
+			policy:     policyv1.Config{MaxSize: &maxSize},
+		},
+		{
+			name:       "Image with too many layers is denied",
+			inspection: inspection(func(i *Inspection) { i.Layers = 6 }),
+			policy:     policyv1.Config{MaxLayers: &maxLayers},
+			wantDeny:   "image has 6 layers, maximum is 5",
+		},
+		{
+			name:       "Image with no user is denied as root",
+			inspection: inspection(func(i *Inspection) { i.User = "" }),
+			policy:     policyv1.Config{DenyRootUser: &denyRoot},
+			wantDeny:   `image runs as root user ""`,
+		},
+		{
+			name:       "Image with root user and group is denied",
+			inspection: inspection(func(i *Inspection) { i.User = "root:wheel" }),
+			policy:     policyv1.Config{DenyRootUser: &denyRoot},
+			wantDeny:   `image runs as root user "root:wheel"`,
+		},
+		{
+			name:       "Image with uid 0 is denied",
+			inspection: inspection(func(i *Inspection) { i.User = "0:1001" }),
+			policy:     policyv1.Config{DenyRootUser: &denyRoot},
+			wantDeny:   `image runs as root user "0:1001"`,
+		},
+		{
+			name:       "Image with a non root uid and root group is allowed",
+			inspection: inspection(func(i *Inspection) { i.User = "1001:0" }),
+			policy:     policyv1.Config{DenyRootUser: &denyRoot},
+		},
+		{
+			name:       "Image with a named user is allowed",
+			inspection: inspection(func(i *Inspection) { i.User = "app" }),
+			policy:     policyv1.Config{DenyRootUser: &denyRoot},
+		},
+		{
+			name:       "Missing required label is denied",
+			inspection: inspection(nil),
+			policy:     policyv1.Config{RequiredLabels: map[string]string{"org.opencontainers.image.revision": ""}},
+			wantDeny:   `required label "org.opencontainers.image.revision" is missing`,
+		},
+		{
+			name:       "Required label matching the pattern is allowed",
+			inspection: inspection(nil),
+			policy:     policyv1.Config{RequiredLabels: map[string]string{"org.opencontainers.image.source": "https://github.com/IBM/*"}},
+		},
+		{
+			name:       "Required label not matching the pattern is denied",
+			inspection: inspection(nil),
+			policy:     policyv1.Config{RequiredLabels: map[string]string{"org.opencontainers.image.source": "https://github.ibm.com/*"}},
+			wantDeny:   `label "org.opencontainers.image.source" value "https://github.com/IBM/portieris" does not match "https://github.ibm.com/*"`,
+		},
+		{
+			name:       "Required annotation present is allowed",
+			inspection: inspection(nil),
+			policy:     policyv1.Config{RequiredAnnotations: map[string]string{"org.opencontainers.image.vendor": "IBM"}},
+		},
+		{
+			name:       "Missing required annotation is denied",
+			inspection: inspection(nil),
+			policy:     policyv1.Config{RequiredAnnotations: map[string]string{"org.opencontainers.image.licenses": ""}},
+			wantDeny:   `required annotation "org.opencontainers.image.licenses" is missing`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deny := verifyConfig(tt.inspection, tt.policy, now)
+			if tt.wantDeny == "" {
+				assert.NoError(t, deny)
+			} else {
+				assert.EqualError(t, deny, tt.wantDeny)
+			}
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	denyRoot := false
+	assert.False(t, Enabled(&policyv1.Policy{}))
+	assert.False(t, Enabled(&policyv1.Policy{Config: policyv1.Config{DenyRootUser: &denyRoot}}))
+	assert.True(t, Enabled(&policyv1.Policy{Config: policyv1.Config{RequiredLabels: map[string]string{"a": ""}}}))
+	assert.True(t, Enabled(&policyv1.Policy{BaseImage: policyv1.BaseImage{Allowed: []string{"icr.io/golden/*"}}}))
+	assert.True(t, Enabled(&policyv1.Policy{Platforms: policyv1.Platforms{Required: []string{"linux/s390x"}}}))
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IBM/portieris/helpers/credential"
+	"github.com/IBM/portieris/pkg/registry"
+	digest "github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.podman.io/image/v5/image"
+	"go.podman.io/image/v5/manifest"
+	"go.podman.io/image/v5/types"
+)
+
+// Inspection holds the properties of an image read from its manifest and configuration
+type Inspection struct {
+	// Digest of the manifest, or manifest list, that the reference resolves to
+	Digest string
+	// Created is when the image was built, nil when the configuration does not say
+	Created *time.Time
+	// Size is the total compressed size of the layers
+	Size int64
+	// Layers is the number of layers
+	Layers int
+	// LayerDigests of the compressed layers, base layers first
+	LayerDigests []string
+	// User the image is configured to run as
+	User string
+	// Labels from the image configuration
+	Labels map[string]string
+	// Platforms the image provides, as os/architecture with an optional /variant
+	Platforms []string
+	// Platform of the inspected image manifest
+	Platform string
+	// Annotations from the manifest list and image manifest, the image manifest wins when both set a key
+	Annotations map[string]string
+}
+
+// errNoInstance is returned when none of the image manifests in a manifest list match the platforms
+var errNoInstance = errors.New("no image manifest to inspect")
+
+// inspectImage opens the image in the registry with the credentials and inspects the image manifests for the platforms,
+// or only the manifest list when the image manifests are not needed
+func inspectImage(ctx context.Context, systemContext *types.SystemContext, imageName string, credentials credential.Credentials, platforms []string, inspectInstances bool) ([]*Inspection, error) {
+	imageSource, err := registry.NewImageSource(ctx, imageName, credentials, systemContext)
+	if err != nil {
+		return nil, err
+	}
+	defer imageSource.Close()
+	return inspect(ctx, systemContext, imageSource, platforms, inspectInstances)
+}
+
+// inspect reads the manifest and configuration of the image, when the source is a manifest list every
+// image manifest that matches one of the platforms is inspected, or every image manifest when there are no platforms.
+// Without inspectInstances only the digest, platforms and annotations of a manifest list are read.
+func inspect(ctx context.Context, systemContext *types.SystemContext, imageSource types.ImageSource, platforms []string, inspectInstances bool) ([]*Inspection, error) {
+	blob, mimeType, err := imageSource.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	topDigest, err := manifest.Digest(blob)
+	if err != nil {
+		return nil, err
+	}
+	top := Inspection{
+		Digest:      strings.TrimPrefix(topDigest.String(), "sha256:"),
+		Annotations: map[string]string{},
+	}
+	if !manifest.MIMETypeIsMultiImage(mimeType) {
+		inspection, err := inspectInstance(ctx, systemContext, imageSource, top, nil, blob, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		inspection.Platforms = []string{inspection.Platform}
+		return []*Inspection{inspection}, nil
+	}
+
+	if mimeType == imgspecv1.MediaTypeImageIndex {
+		index, err := manifest.OCI1IndexFromManifest(blob)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range index.Annotations {
+			top.Annotations[k] = v
+		}
+	}
+	list, err := manifest.ListFromBlob(blob, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	var instances []digest.Digest
+	for _, d := range list.Instances() {
+		entry, err := list.Instance(d)
+		if err != nil {
+			return nil, err
+		}
+		platform := entry.ReadOnly.Platform
+		// attestations and other artifacts in an index have no platform, or an unknown one
+		if platform == nil || platform.OS == "unknown" {
+			continue
+		}
+		name := platformName(platform.OS, platform.Architecture, platform.Variant)
+		top.Platforms = append(top.Platforms, name)
+		if len(platforms) == 0 || matchPlatform(name, platforms) {
+			instances = append(instances, d)
+		}
+	}
+	if !inspectInstances {
+		return []*Inspection{&top}, nil
+	}
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("%w for platforms %v, the image provides %v", errNoInstance, platforms, top.Platforms)
+	}
+
+	var inspections []*Inspection
+	for i := range instances {
+		instance := &instances[i]
+		blob, mimeType, err := imageSource.GetManifest(ctx, instance)
+		if err != nil {
+			return nil, err
+		}
+		inspection, err := inspectInstance(ctx, systemContext, imageSource, top, instance, blob, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		inspections = append(inspections, inspection)
+	}
+	return inspections, nil
+}
+
+// inspectInstance reads the image manifest and its configuration, adding them to a copy of the top level inspection
+func inspectInstance(ctx context.Context, systemContext *types.SystemContext, imageSource types.ImageSource, top Inspection, instance *digest.Digest, blob []byte, mimeType string) (*Inspection, error) {
+	inspection := top
+	inspection.Annotations = map[string]string{}
+	for k, v := range top.Annotations {
+		inspection.Annotations[k] = v
+	}
+	if mimeType == imgspecv1.MediaTypeImageManifest {
+		m, err := manifest.OCI1FromManifest(blob)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range m.Annotations {
+			inspection.Annotations[k] = v
+		}
+	}
+
+	img, err := image.FromUnparsedImage(ctx, systemContext, image.UnparsedInstance(imageSource, instance))
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range img.LayerInfos() {
+		inspection.Layers++
+		inspection.LayerDigests = append(inspection.LayerDigests, layer.Digest.String())
+		if layer.Size > 0 {
+			inspection.Size += layer.Size
+		}
+	}
+	config, err := img.OCIConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inspection.Created = config.Created
+	inspection.User = config.Config.User
+	inspection.Labels = config.Config.Labels
+	inspection.Platform = platformName(config.OS, config.Architecture, config.Variant)
+	return &inspection, nil
+}
+
+// matchPlatform matches an image platform to one of the platforms, which without a variant match any variant
+func matchPlatform(platform string, platforms []string) bool {
+	for _, p := range platforms {
+		if providesPlatform([]string{platform}, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func platformName(os, architecture, variant string) string {
+	if variant == "" {
+		return os + "/" + architecture
+	}
+	return os + "/" + architecture + "/" + variant
+}
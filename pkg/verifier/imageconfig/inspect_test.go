@@ -0,0 +1,60 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageconfig
+
+import (
+	"context"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/types"
+)
+
+// indexSource serves an image index, reading any image manifest of it fails the test
+type indexSource struct {
+	types.ImageSource
+	t         *testing.T
+	manifests int
+}
+
+func (s *indexSource) GetManifest(ctx context.Context, instance *digest.Digest) ([]byte, string, error) {
+	s.manifests++
+	if instance != nil {
+		s.t.Errorf("image manifest %s read", instance)
+	}
+	return []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.oci.image.index.v1+json",
+		"manifests": [
+			{"mediaType": "application/vnd.oci.image.manifest.v1+json", "size": 1, "digest": "sha256:1111111111111111111111111111111111111111111111111111111111111111", "platform": {"os": "linux", "architecture": "amd64"}},
+			{"mediaType": "application/vnd.oci.image.manifest.v1+json", "size": 1, "digest": "sha256:2222222222222222222222222222222222222222222222222222222222222222", "platform": {"os": "linux", "architecture": "arm64", "variant": "v8"}},
+			{"mediaType": "application/vnd.oci.image.manifest.v1+json", "size": 1, "digest": "sha256:3333333333333333333333333333333333333333333333333333333333333333", "platform": {"os": "unknown", "architecture": "unknown"}}
+		]
+	}`), "application/vnd.oci.image.index.v1+json", nil
+}
+
+func TestInspect_platformsOnly(t *testing.T) {
+	source := &indexSource{t: t}
+
+	inspections, err := inspect(context.Background(), nil, source, nil, false)
+
+	require.NoError(t, err)
+	require.Len(t, inspections, 1)
+	assert.Equal(t, []string{"linux/amd64", "linux/arm64/v8"}, inspections[0].Platforms, "attestations are not platforms")
+	assert.NotEmpty(t, inspections[0].Digest)
+	assert.Equal(t, 1, source.manifests, "only the index is read")
+}
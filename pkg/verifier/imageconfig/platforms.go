@@ -0,0 +1,50 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageconfig
+
+import (
+	"fmt"
+	"strings"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+)
+
+func platformsEnabled(policy policyv1.Platforms) bool {
+	return len(policy.Required) > 0
+}
+
+// verifyPlatforms checks that the image provides every required platform and returns the reason for denial
+func verifyPlatforms(inspection *Inspection, policy policyv1.Platforms) error {
+	var missing []string
+	for _, required := range policy.Required {
+		if !providesPlatform(inspection.Platforms, required) {
+			missing = append(missing, required)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("image does not provide required platforms %v, it provides %v", missing, inspection.Platforms)
+	}
+	return nil
+}
+
+// providesPlatform matches a required os/architecture, which without a variant matches any variant
+func providesPlatform(platforms []string, required string) bool {
+	for _, platform := range platforms {
+		if platform == required || strings.Count(required, "/") == 1 && strings.HasPrefix(platform, required+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageconfig
+
+import (
+	"testing"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyPlatforms(t *testing.T) {
+	tests := []struct {
+		name      string
+		platforms []string
+		required  []string
+		wantDeny  string
+	}{
+		{
+			name:      "No required platforms allows anything",
+			platforms: []string{"linux/amd64"},
+		},
+		{
+			name:      "All required platforms are provided",
+			platforms: []string{"linux/amd64", "linux/arm64/v8", "linux/s390x"},
+			required:  []string{"linux/amd64", "linux/s390x"},
+		},
+		{
+			name:      "Required platform without a variant matches any variant",
+			platforms: []string{"linux/arm64/v8"},
+			required:  []string{"linux/arm64"},
+		},
+		{
+			name:      "Required variant must match",
+			platforms: []string{"linux/arm/v6"},
+			required:  []string{"linux/arm/v7"},
+			wantDeny:  "image does not provide required platforms [linux/arm/v7], it provides [linux/arm/v6]",
+		},
+		{
+			name:      "Missing platforms are all reported",
+			platforms: []string{"linux/amd64"},
+			required:  []string{"linux/amd64", "linux/s390x", "linux/ppc64le"},
+			wantDeny:  "image does not provide required platforms [linux/s390x linux/ppc64le], it provides [linux/amd64]",
+		},
+		{
+			name:      "Architecture prefix is not a match",
+			platforms: []string{"linux/arm64"},
+			required:  []string{"linux/arm"},
+			wantDeny:  "image does not provide required platforms [linux/arm], it provides [linux/arm64]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deny := verifyPlatforms(&Inspection{Platforms: tt.platforms}, policyv1.Platforms{Required: tt.required})
+			if tt.wantDeny == "" {
+				assert.NoError(t, deny)
+			} else {
+				assert.EqualError(t, deny, tt.wantDeny)
+			}
+		})
+	}
+}
+
+func TestMatchPlatform(t *testing.T) {
+	tests := []struct {
+		name      string
+		platform  string
+		platforms []string
+		want      bool
+	}{
+		{
+			name:      "Node platform matches",
+			platform:  "linux/amd64",
+			platforms: []string{"linux/amd64", "linux/s390x"},
+			want:      true,
+		},
+		{
+			name:      "Node platform matches any variant",
+			platform:  "linux/arm64/v8",
+			platforms: []string{"linux/arm64"},
+			want:      true,
+		},
+		{
+			name:      "Other platforms do not match",
+			platform:  "linux/ppc64le",
+			platforms: []string{"linux/amd64", "linux/s390x"},
+		},
+		{
+			name:      "Architecture prefix is not a match",
+			platform:  "linux/arm64",
+			platforms: []string{"linux/arm"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchPlatform(tt.platform, tt.platforms))
+		})
+	}
+}
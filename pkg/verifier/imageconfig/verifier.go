@@ -0,0 +1,109 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBM/portieris/helpers/credential"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/registry"
+	"github.com/IBM/portieris/pkg/transport"
+	"go.podman.io/image/v5/types"
+)
+
+// Verifier is for verifying the image manifest and configuration held in the registry
+type Verifier interface {
+	VerifyByPolicy(ctx context.Context, imageToVerify string, credentials credential.Credentials, policy *policyv1.Policy, platforms []string) (*bytes.Buffer, error, error)
+}
+
+type verifier struct {
+	// transport sets the proxy and trusted CAs of registry requests, and counts them, when it is not nil
+	transport *transport.Transport
+}
+
+// NewVerifier creates a new Verifier, registry requests use the proxy and trusted CAs of t, which may be nil
+func NewVerifier(t *transport.Transport) Verifier {
+	return &verifier{transport: t}
+}
+
+// InspectsInstances is true when the policy checks the image manifests of a manifest list, which are
+// chosen by the platforms passed to VerifyByPolicy
+func InspectsInstances(policy *policyv1.Policy) bool {
+	return configEnabled(policy.Config) || baseImageEnabled(policy.BaseImage)
+}
+
+// VerifyByPolicy inspects the image in the registry and checks it against the policy, it returns the
+// digest the reference resolved to, verify error or processing error. For a manifest list the image
+// manifests for the platforms, the os/architecture of the cluster nodes, are checked, or every image
+// manifest when there are no platforms.
+func (v verifier) VerifyByPolicy(ctx context.Context, imageToVerify string, credentials credential.Credentials, policy *policyv1.Policy, platforms []string) (*bytes.Buffer, error, error) {
+	systemContext := registry.NewSystemContext(v.transport)
+	// the platforms of a manifest list are read from the list, its image manifests are only read to check them
+	inspections, err := inspectImage(ctx, systemContext, imageToVerify, credentials, platforms, InspectsInstances(policy))
+	if errors.Is(err, errNoInstance) {
+		return nil, err, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, inspection := range inspections {
+		deny, err := verifyInspection(ctx, systemContext, inspection, credentials, policy)
+		if err != nil {
+			return nil, nil, err
+		}
+		if deny != nil {
+			if len(inspections) > 1 {
+				deny = fmt.Errorf("%s: %v", inspection.Platform, deny)
+			}
+			return nil, deny, nil
+		}
+	}
+	if deny := verifyPlatforms(inspections[0], policy.Platforms); deny != nil {
+		return nil, deny, nil
+	}
+	return bytes.NewBufferString(inspections[0].Digest), nil, nil
+}
+
+// verifyInspection checks the configuration and base image of one image manifest, it returns the verify error or processing error
+func verifyInspection(ctx context.Context, systemContext *types.SystemContext, inspection *Inspection, credentials credential.Credentials, policy *policyv1.Policy) (error, error) {
+	if deny := verifyConfig(inspection, policy.Config, time.Now()); deny != nil {
+		return deny, nil
+	}
+	if !baseImageEnabled(policy.BaseImage) {
+		return nil, nil
+	}
+	base, deny := verifyBaseImage(inspection, policy.BaseImage)
+	if deny != nil {
+		return deny, nil
+	}
+	if policy.BaseImage.VerifyLayers != nil && *policy.BaseImage.VerifyLayers {
+		baseInspections, err := inspectImage(ctx, systemContext, base, credentials, []string{inspection.Platform}, true)
+		if errors.Is(err, errNoInstance) {
+			return fmt.Errorf("base image %s: %v", base, err), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("base image %s: %v", base, err)
+		}
+		if deny := verifyBaseLayers(inspection, baseInspections[0]); deny != nil {
+			return deny, nil
+		}
+	}
+	return nil, nil
+}
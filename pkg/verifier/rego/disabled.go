@@ -0,0 +1,32 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build no_rego
+
+package rego
+
+import (
+	"context"
+	"errors"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/verifier/condition"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// VerifyByPolicy denies the image, this build of Portieris leaves out the Open Policy Agent library
+func VerifyByPolicy(ctx context.Context, kubeWrapper kubernetes.WrapperInterface, policy policyv1.Rego, request *admissionv1.AdmissionRequest, input condition.Input) error {
+	return errors.New("Rego policies are not supported by this build of Portieris")
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_rego
+
+package rego
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/verifier/condition"
+	opa "github.com/open-policy-agent/opa/v1/rego"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// DefaultQuery produces the decision when the policy does not set a query
+const DefaultQuery = "data.portieris.decision"
+
+// maxPrepared bounds the prepared queries that are kept, a module that changes replaces its entry
+// under a new key so the cache is emptied when it is full rather than tracking use
+const maxPrepared = 64
+
+var (
+	preparedLock sync.Mutex
+	prepared     = map[string]opa.PreparedEvalQuery{}
+)
+
+// VerifyByPolicy evaluates the Rego module with the admission request and the verification results
+// as input, it returns the reason for denial or nil. A module that can not be read, compiled or
+// evaluated, or a decision that is undefined, denies the image.
+func VerifyByPolicy(ctx context.Context, kubeWrapper kubernetes.WrapperInterface, policy policyv1.Rego, request *admissionv1.AdmissionRequest, input condition.Input) error {
+	module := policy.Module
+	if policy.ConfigMap != nil {
+		var err error
+		module, err = kubeWrapper.GetConfigMapData(policy.ConfigMap.Namespace, policy.ConfigMap.Name, policy.ConfigMap.Key)
+		if err != nil {
+			return fmt.Errorf("unable to read the Rego module: %v", err)
+		}
+	}
+	query := policy.Query
+	if query == "" {
+		query = DefaultQuery
+	}
+
+	pq, err := prepare(ctx, module, query)
+	if err != nil {
+		return fmt.Errorf("Rego module is invalid: %v", err)
+	}
+	document, err := input.Variables()
+	if err != nil {
+		return err
+	}
+	document["request"] = request
+	results, err := pq.Eval(ctx, opa.EvalInput(document))
+	if err != nil {
+		return fmt.Errorf("Rego query %s can not be evaluated: %v", query, err)
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return fmt.Errorf("Rego query %s is undefined", query)
+	}
+	return decide(query, results[0].Expressions[0].Value)
+}
+
+// decide reads the decision, an object with an allow boolean and optional messages
+func decide(query string, value interface{}) error {
+	decision, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Rego query %s is not an object", query)
+	}
+	allow, ok := decision["allow"].(bool)
+	if !ok {
+		return fmt.Errorf("Rego query %s has no allow boolean", query)
+	}
+	if allow {
+		return nil
+	}
+	var messages []string
+	if list, ok := decision["messages"].([]interface{}); ok {
+		for _, message := range list {
+			messages = append(messages, fmt.Sprint(message))
+		}
+	}
+	if len(messages) == 0 {
+		return fmt.Errorf("denied by Rego query %s", query)
+	}
+	return fmt.Errorf("%s", strings.Join(messages, ", "))
+}
+
+// prepare compiles the module and query once for each distinct module source
+func prepare(ctx context.Context, module, query string) (opa.PreparedEvalQuery, error) {
+	key := query + "\x00" + module
+	preparedLock.Lock()
+	defer preparedLock.Unlock()
+	if pq, ok := prepared[key]; ok {
+		return pq, nil
+	}
+	pq, err := opa.New(opa.Query(query), opa.Module("portieris.rego", module)).PrepareForEval(ctx)
+	if err != nil {
+		return pq, err
+	}
+	if len(prepared) >= maxPrepared {
+		prepared = map[string]opa.PreparedEvalQuery{}
+	}
+	prepared[key] = pq
+	return pq, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !no_rego
+
+package rego
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/verifier/condition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+const module = `package portieris
+
+default allow := false
+
+allow if {
+	input.results.trust
+	count(messages) == 0
+}
+
+messages contains msg if {
+	input.request.userInfo.username == "intruder"
+	msg := sprintf("user %s may not deploy %s", [input.request.userInfo.username, input.image.repository])
+}
+
+decision := {"allow": allow, "messages": messages}
+`
+
+func TestVerifyByPolicy(t *testing.T) {
+	img, err := image.NewReference("icr.io/team/app:1.2.3")
+	require.NoError(t, err)
+	trust := true
+	kubeWrapper := kubernetes.NewKubeClientsetWrapper(k8sfake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "rules", Namespace: "portieris"},
+		Data:       map[string]string{"policy.rego": module},
+	}))
+	input := func(policy *policyv1.Policy) condition.Input {
+		return condition.Input{Image: img, Namespace: "team", Policy: policy, VulnerabilityAllowed: true}
+	}
+	request := func(username string) *admissionv1.AdmissionRequest {
+		request := &admissionv1.AdmissionRequest{Namespace: "team"}
+		request.UserInfo.Username = username
+		return request
+	}
+
+	tests := []struct {
+		name     string
+		policy   policyv1.Rego
+		request  *admissionv1.AdmissionRequest
+		input    condition.Input
+		wantDeny string
+	}{
+		{
+			name:    "Inline module allows",
+			policy:  policyv1.Rego{Module: module},
+			request: request("developer"),
+			input:   input(&policyv1.Policy{Trust: policyv1.Trust{Enabled: &trust}}),
+		},
+		{
+			name:     "Denial without messages names the query",
+			policy:   policyv1.Rego{Module: module},
+			request:  request("developer"),
+			input:    input(&policyv1.Policy{}),
+			wantDeny: "denied by Rego query data.portieris.decision",
+		},
+		{
+			name:     "Denial messages are returned",
+			policy:   policyv1.Rego{ConfigMap: &policyv1.ConfigMapKeyReference{Name: "rules", Namespace: "portieris", Key: "policy.rego"}},
+			request:  request("intruder"),
+			input:    input(&policyv1.Policy{Trust: policyv1.Trust{Enabled: &trust}}),
+			wantDeny: "user intruder may not deploy icr.io/team/app",
+		},
+		{
+			name:     "Missing ConfigMap key is denied",
+			policy:   policyv1.Rego{ConfigMap: &policyv1.ConfigMapKeyReference{Name: "rules", Namespace: "portieris", Key: "other.rego"}},
+			request:  request("developer"),
+			input:    input(&policyv1.Policy{}),
+			wantDeny: `unable to read the Rego module: ConfigMap portieris/rules has no key "other.rego"`,
+		},
+		{
+			name:     "Undefined query is denied",
+			policy:   policyv1.Rego{Module: module, Query: "data.portieris.missing"},
+			request:  request("developer"),
+			input:    input(&policyv1.Policy{}),
+			wantDeny: "Rego query data.portieris.missing is undefined",
+		},
+		{
+			name:     "Query that is not an object is denied",
+			policy:   policyv1.Rego{Module: module, Query: "data.portieris.allow"},
+			request:  request("developer"),
+			input:    input(&policyv1.Policy{}),
+			wantDeny: "Rego query data.portieris.allow is not an object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deny := VerifyByPolicy(context.Background(), kubeWrapper, tt.policy, tt.request, tt.input)
+			if tt.wantDeny == "" {
+				assert.NoError(t, deny)
+			} else {
+				assert.EqualError(t, deny, tt.wantDeny)
+			}
+		})
+	}
+
+	t.Run("Invalid module is denied", func(t *testing.T) {
+		deny := VerifyByPolicy(context.Background(), kubeWrapper, policyv1.Rego{Module: "package portieris\n\ndecision := {"}, request("developer"), input(&policyv1.Policy{}))
+		require.Error(t, deny)
+		assert.Contains(t, deny.Error(), "Rego module is invalid")
+	})
+}
@@ -1,4 +1,4 @@
-// Copyright 2020 Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -19,9 +19,22 @@ import (
 	"fmt"
 	"io/ioutil"
 
+	"golang.org/x/crypto/openpgp"
 	"golang.org/x/crypto/openpgp/armor"
 )
 
+// ParseKey checks that keyBytes is an armored PGP public key that can be read
+func ParseKey(keyBytes []byte) error {
+	keyData, err := decodeArmoredKey(keyBytes)
+	if err != nil {
+		return err
+	}
+	if _, err := openpgp.ReadKeyRing(bytes.NewReader(keyData)); err != nil {
+		return fmt.Errorf("Unable to read key: %v", err)
+	}
+	return nil
+}
+
 func decodeArmoredKey(keyBytes []byte) ([]byte, error) {
 	if len(keyBytes) == 0 {
 		return nil, fmt.Errorf("Key: empty")
@@ -1,4 +1,4 @@
-// Copyright 2020 Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -143,3 +143,17 @@ func TestDecodeArmoredKey(t *testing.T) {
 		})
 	}
 }
+
+func TestParseKey(t *testing.T) {
+	assert.NoError(t, ParseKey([]byte(myKey)))
+	assert.EqualError(t, ParseKey(nil), "Key: empty")
+
+	notAKey := "-----BEGIN PGP PUBLIC KEY BLOCK-----\n" +
+		"\n" +
+		"c29tZSB0ZXh0Cg==\n" +
+		"-----END PGP PUBLIC KEY BLOCK-----\n"
+	err := ParseKey([]byte(notAKey))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Unable to read key")
+	}
+}
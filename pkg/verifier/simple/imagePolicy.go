@@ -23,8 +23,10 @@ import (
 	"strings"
 
 	"github.com/IBM/portieris/helpers/credential"
-	"github.com/IBM/portieris/internal/info"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/IBM/portieris/pkg/registry"
 	"github.com/golang/glog"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"go.podman.io/image/v5/docker"
 	"go.podman.io/image/v5/image"
 	"go.podman.io/image/v5/manifest"
@@ -32,8 +34,9 @@ import (
 	"go.podman.io/image/v5/types"
 )
 
-// VerifyByPolicy verifies the image according to the supplied policy and returns the verified digest, verify error or processing error
-func (v verifier) VerifyByPolicy(imageToVerify string, credentials credential.Credentials, registriesConfigDir string, simplePolicy *signature.Policy) (*bytes.Buffer, error, error) {
+// VerifyByPolicy verifies the image according to the supplied policy and returns the verified digest, verify error or processing error.
+// multiArch selects how an image index is verified, platforms are the os/architecture of the cluster nodes for MultiArchVerifyNodes.
+func (v verifier) VerifyByPolicy(ctx context.Context, imageToVerify string, credentials credential.Credentials, registriesConfigDir string, simplePolicy *signature.Policy, multiArch policyv1.MultiArch, platforms []string) (*bytes.Buffer, error, error) {
 
 	policyContext, err := signature.NewPolicyContext(simplePolicy)
 	if err != nil {
@@ -44,17 +47,14 @@ func (v verifier) VerifyByPolicy(imageToVerify string, credentials credential.Cr
 		return nil, nil, err
 	}
 	// if expensive, make instance
-	systemContext := &types.SystemContext{
-		RootForImplicitAbsolutePaths: "/nowhere", // read nothing from files
-		DockerRegistryUserAgent:      "portieris/" + info.Version,
-		RegistriesDirPath:            registriesConfigDir,
-	}
+	systemContext := registry.NewSystemContext(v.transport)
+	systemContext.RegistriesDirPath = registriesConfigDir
 
-	imageSource, err := imageReference.NewImageSource(context.Background(), systemContext)
+	imageSource, err := imageReference.NewImageSource(ctx, systemContext)
 	if err == nil {
 		defer imageSource.Close()
 		glog.Infof("SimpleSigning verification: anonymous access allowed for image %s, continuing with anonymous verify", imageToVerify)
-		return verifyAttempt(imageSource, policyContext)
+		return verifyAttempt(ctx, imageSource, policyContext, multiArch, platforms)
 	}
 	glog.Errorf("SimpleSigning verification: anonymous access denied for image %s, continuing with ImagePullSecrets... Error %v", imageToVerify, err)
 
@@ -65,7 +65,7 @@ func (v verifier) VerifyByPolicy(imageToVerify string, credentials credential.Cr
 			Password: credential.Password,
 		}
 		systemContext.DockerAuthConfig = dockerAuthConfig
-		imageSource, err := imageReference.NewImageSource(context.Background(), systemContext)
+		imageSource, err := imageReference.NewImageSource(ctx, systemContext)
 		if err != nil {
 			if i+1 == numCreds {
 				glog.Errorf("SimpleSigning verification: ImagePullSecret with username %s for image %s failed, no more secrets in scope (secret %d/%d). Failing. Error %v", credential.Username, imageToVerify, i+1, numCreds, err)
@@ -76,15 +76,23 @@ func (v verifier) VerifyByPolicy(imageToVerify string, credentials credential.Cr
 		}
 		defer imageSource.Close()
 		glog.Infof("SimpleSigning verification: ImagePullSecret with username %s for image %s was valid (secret %d/%d), continuing to next stage", credential.Username, imageToVerify, i+1, numCreds)
-		return verifyAttempt(imageSource, policyContext)
+		return verifyAttempt(ctx, imageSource, policyContext, multiArch, platforms)
 	}
 
 	return nil, nil, fmt.Errorf("Deny %q, no valid ImagePullSecret, %d tried", imageToVerify, len(credentials))
 }
 
-func verifyAttempt(imageSource types.ImageSource, policyContext *signature.PolicyContext) (*bytes.Buffer, error, error) {
+func verifyAttempt(ctx context.Context, imageSource types.ImageSource, policyContext *signature.PolicyContext, multiArch policyv1.MultiArch, platforms []string) (*bytes.Buffer, error, error) {
 	unparsedImage := image.UnparsedInstance(imageSource, nil)
-	_, err := policyContext.IsRunningImageAllowed(context.Background(), unparsedImage)
+	m, mimeType, err := unparsedImage.Manifest(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if manifest.MIMETypeIsMultiImage(mimeType) && multiArch.Verify != "" && multiArch.Verify != policyv1.MultiArchVerifyIndex {
+		return verifyInstances(ctx, imageSource, policyContext, m, mimeType, multiArch, platforms)
+	}
+
+	_, err = policyContext.IsRunningImageAllowed(ctx, unparsedImage)
 	switch err.(type) {
 	case nil:
 	case signature.PolicyRequirementError:
@@ -93,10 +101,68 @@ func verifyAttempt(imageSource types.ImageSource, policyContext *signature.Polic
 		return nil, nil, err
 	}
 	// get the digest
-	m, _, err := unparsedImage.Manifest(context.Background())
+	return manifestDigest(m)
+}
+
+// verifyInstances verifies the signatures of the platform manifests of an image index, rather than the index itself
+func verifyInstances(ctx context.Context, imageSource types.ImageSource, policyContext *signature.PolicyContext, m []byte, mimeType string, multiArch policyv1.MultiArch, platforms []string) (*bytes.Buffer, error, error) {
+	list, err := manifest.ListFromBlob(m, mimeType)
 	if err != nil {
 		return nil, nil, err
 	}
+	verified := 0
+	for _, instanceDigest := range list.Instances() {
+		instance, err := list.Instance(instanceDigest)
+		if err != nil {
+			return nil, nil, err
+		}
+		platform := instancePlatform(instance.ReadOnly.Platform)
+		// attestations and other artifacts are stored with an unknown platform and are not signed as images
+		if platform == "" || strings.HasPrefix(platform, "unknown/") {
+			continue
+		}
+		if multiArch.Verify == policyv1.MultiArchVerifyNodes && !matchPlatform(platform, platforms) {
+			continue
+		}
+		d := instanceDigest
+		_, err = policyContext.IsRunningImageAllowed(ctx, image.UnparsedInstance(imageSource, &d))
+		switch err.(type) {
+		case nil:
+		case signature.PolicyRequirementError:
+			return nil, fmt.Errorf("platform %s: %v", platform, err), nil
+		default:
+			return nil, nil, err
+		}
+		glog.Infof("SimpleSigning verification: platform %s manifest %s verified", platform, instanceDigest)
+		verified++
+	}
+	if verified == 0 {
+		return nil, fmt.Errorf("no platform manifest to verify for node platforms %v", platforms), nil
+	}
+	if multiArch.MutateToIndex == nil || !*multiArch.MutateToIndex {
+		return nil, nil, nil
+	}
+	return manifestDigest(m)
+}
+
+// instancePlatform returns the os/architecture of an index entry, or an empty string when it is not recorded
+func instancePlatform(platform *imgspecv1.Platform) string {
+	if platform == nil || platform.OS == "" || platform.Architecture == "" {
+		return ""
+	}
+	return platform.OS + "/" + platform.Architecture
+}
+
+func matchPlatform(platform string, platforms []string) bool {
+	for _, p := range platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+func manifestDigest(m []byte) (*bytes.Buffer, error, error) {
 	digest, err := manifest.Digest(m)
 	if err != nil {
 		return nil, nil, err
@@ -17,11 +17,22 @@
 package simple
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/IBM/portieris/helpers/credential"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	digest "github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/docker"
 	"go.podman.io/image/v5/signature"
+	"go.podman.io/image/v5/types"
 )
 
 var policyRequirementInsecure = signature.NewPRInsecureAcceptAnything()
@@ -79,7 +90,7 @@ func TestVerifyByPolicy(t *testing.T) {
 					},
 				},
 			}
-			digest, deny, err := verifier{}.VerifyByPolicy(tt.image, tt.credentials, "", policy)
+			digest, deny, err := verifier{}.VerifyByPolicy(context.Background(), tt.image, tt.credentials, "", policy, policyv1.MultiArch{}, nil)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errMsg, "unexpected error")
@@ -98,3 +109,149 @@ func TestVerifyByPolicy(t *testing.T) {
 		})
 	}
 }
+
+// fakeImageSource serves manifests from memory, the top level manifest and instances by digest
+type fakeImageSource struct {
+	ref       types.ImageReference
+	manifest  []byte
+	mimeType  string
+	instances map[digest.Digest][]byte
+}
+
+func (f *fakeImageSource) Reference() types.ImageReference { return f.ref }
+func (f *fakeImageSource) Close() error                    { return nil }
+func (f *fakeImageSource) HasThreadSafeGetBlob() bool      { return false }
+func (f *fakeImageSource) GetManifest(ctx context.Context, instanceDigest *digest.Digest) ([]byte, string, error) {
+	if instanceDigest == nil {
+		return f.manifest, f.mimeType, nil
+	}
+	if m, ok := f.instances[*instanceDigest]; ok {
+		return m, imgspecv1.MediaTypeImageManifest, nil
+	}
+	return nil, "", fmt.Errorf("manifest %s not found", instanceDigest)
+}
+func (f *fakeImageSource) GetBlob(context.Context, types.BlobInfo, types.BlobInfoCache) (io.ReadCloser, int64, error) {
+	return nil, 0, fmt.Errorf("not implemented")
+}
+func (f *fakeImageSource) GetSignatures(context.Context, *digest.Digest) ([][]byte, error) {
+	return nil, nil
+}
+func (f *fakeImageSource) LayerInfosForCopy(context.Context, *digest.Digest) ([]types.BlobInfo, error) {
+	return nil, nil
+}
+
+// newFakeIndex builds an image index with an image manifest for each os/architecture platform
+func newFakeIndex(t *testing.T, platforms ...string) *fakeImageSource {
+	ref, err := docker.ParseReference("//icr.io/team/app:1")
+	require.NoError(t, err)
+	source := &fakeImageSource{ref: ref, mimeType: imgspecv1.MediaTypeImageIndex, instances: map[digest.Digest][]byte{}}
+	index := imgspecv1.Index{MediaType: imgspecv1.MediaTypeImageIndex}
+	index.SchemaVersion = 2
+	for _, platform := range platforms {
+		m, err := json.Marshal(imgspecv1.Manifest{
+			MediaType: imgspecv1.MediaTypeImageManifest,
+			Config:    imgspecv1.Descriptor{MediaType: imgspecv1.MediaTypeImageConfig, Digest: digest.FromString(platform), Size: 1},
+		})
+		require.NoError(t, err)
+		d := digest.FromBytes(m)
+		source.instances[d] = m
+		osArch := strings.SplitN(platform, "/", 2)
+		index.Manifests = append(index.Manifests, imgspecv1.Descriptor{
+			MediaType: imgspecv1.MediaTypeImageManifest,
+			Digest:    d,
+			Size:      int64(len(m)),
+			Platform:  &imgspecv1.Platform{OS: osArch[0], Architecture: osArch[1]},
+		})
+	}
+	source.manifest, err = json.Marshal(index)
+	require.NoError(t, err)
+	return source
+}
+
+func TestVerifyAttempt_MultiArch(t *testing.T) {
+	mutate := true
+	accept := signature.NewPRInsecureAcceptAnything()
+	reject := signature.NewPRReject()
+
+	tests := []struct {
+		name        string
+		platforms   []string
+		requirement signature.PolicyRequirement
+		multiArch   policyv1.MultiArch
+		nodes       []string
+		wantIndex   bool
+		wantDeny    string
+	}{
+		{
+			name:        "Index signature is verified by default",
+			platforms:   []string{"linux/amd64", "linux/arm64"},
+			requirement: accept,
+			wantIndex:   true,
+		},
+		{
+			name:        "Index signature is verified and denied by default",
+			platforms:   []string{"linux/amd64", "linux/arm64"},
+			requirement: reject,
+			wantDeny:    "Running image docker://icr.io/team/app:1 is rejected by policy.",
+		},
+		{
+			name:        "All platforms verified are not mutated",
+			platforms:   []string{"linux/amd64", "linux/arm64", "unknown/unknown"},
+			requirement: accept,
+			multiArch:   policyv1.MultiArch{Verify: policyv1.MultiArchVerifyAll},
+		},
+		{
+			name:        "All platforms verified are mutated to the index when requested",
+			platforms:   []string{"linux/amd64", "linux/arm64"},
+			requirement: accept,
+			multiArch:   policyv1.MultiArch{Verify: policyv1.MultiArchVerifyAll, MutateToIndex: &mutate},
+			wantIndex:   true,
+		},
+		{
+			name:        "All platforms denies the first platform",
+			platforms:   []string{"linux/amd64", "linux/arm64"},
+			requirement: reject,
+			multiArch:   policyv1.MultiArch{Verify: policyv1.MultiArchVerifyAll},
+			wantDeny:    "platform linux/amd64: Running image docker://icr.io/team/app:1 is rejected by policy.",
+		},
+		{
+			name:        "Node platforms skip other platforms",
+			platforms:   []string{"linux/amd64", "linux/arm64"},
+			requirement: reject,
+			multiArch:   policyv1.MultiArch{Verify: policyv1.MultiArchVerifyNodes},
+			nodes:       []string{"linux/arm64"},
+			wantDeny:    "platform linux/arm64: Running image docker://icr.io/team/app:1 is rejected by policy.",
+		},
+		{
+			name:        "Node platforms missing from the index are denied",
+			platforms:   []string{"linux/amd64", "linux/arm64"},
+			requirement: accept,
+			multiArch:   policyv1.MultiArch{Verify: policyv1.MultiArchVerifyNodes},
+			nodes:       []string{"linux/s390x"},
+			wantDeny:    "no platform manifest to verify for node platforms [linux/s390x]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newFakeIndex(t, tt.platforms...)
+			policyContext, err := signature.NewPolicyContext(&signature.Policy{
+				Default: signature.PolicyRequirements{tt.requirement},
+			})
+			require.NoError(t, err)
+
+			gotDigest, deny, err := verifyAttempt(context.Background(), source, policyContext, tt.multiArch, tt.nodes)
+			require.NoError(t, err)
+			if tt.wantDeny != "" {
+				assert.EqualError(t, deny, tt.wantDeny)
+				return
+			}
+			assert.NoError(t, deny)
+			if tt.wantIndex {
+				require.NotNil(t, gotDigest)
+				assert.Equal(t, digest.FromBytes(source.manifest).Encoded(), gotDigest.String())
+			} else {
+				assert.Nil(t, gotDigest)
+			}
+		})
+	}
+}
@@ -84,6 +84,12 @@ func (v verifier) TransformPolicies(kWrapper kubernetes.WrapperInterface, namesp
 	}, nil
 }
 
+// ValidateSignedIdentity checks that the signedIdentity of a signedBy requirement can be used
+func ValidateSignedIdentity(requirement policyv1.SimpleRequirement) error {
+	_, err := policySignedIdentity(&requirement)
+	return err
+}
+
 func policySignedIdentity(inPolicy *policyv1.SimpleRequirement) (signature.PolicyReferenceMatch, error) {
 	switch inPolicy.SignedIdentity.Type {
 	case "":
@@ -16,10 +16,12 @@ package simple
 
 import (
 	"bytes"
+	"context"
 
 	"github.com/IBM/portieris/helpers/credential"
 	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
 	"github.com/IBM/portieris/pkg/kubernetes"
+	"github.com/IBM/portieris/pkg/transport"
 	"go.podman.io/image/v5/signature"
 )
 
@@ -27,13 +29,16 @@ import (
 type Verifier interface {
 	TransformPolicies(kWrapper kubernetes.WrapperInterface, namespace string, inPolicies []policyv1.SimpleRequirement) (*signature.Policy, error)
 	CreateRegistryDir(storeURL, storeUser, storePassword string) (string, error)
-	VerifyByPolicy(imageToVerify string, credentials credential.Credentials, registriesConfigDir string, simplePolicy *signature.Policy) (*bytes.Buffer, error, error)
+	VerifyByPolicy(ctx context.Context, imageToVerify string, credentials credential.Credentials, registriesConfigDir string, simplePolicy *signature.Policy, multiArch policyv1.MultiArch, platforms []string) (*bytes.Buffer, error, error)
 	RemoveRegistryDir(dirName string) error
 }
 
-type verifier struct{}
+type verifier struct {
+	// transport sets the proxy and trusted CAs of registry requests, and counts them, when it is not nil
+	transport *transport.Transport
+}
 
-// NewVerifier creates a new Verfier
-func NewVerifier() Verifier {
-	return &verifier{}
+// NewVerifier creates a new Verfier, registry requests use the proxy and trusted CAs of t, which may be nil
+func NewVerifier(t *transport.Transport) Verifier {
+	return &verifier{transport: t}
 }
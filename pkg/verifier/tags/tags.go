@@ -0,0 +1,65 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/IBM/portieris/helpers/image"
+	"github.com/IBM/portieris/helpers/wildcard"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+)
+
+// VerifyByPolicy checks how the image is referenced against the tags policy, it returns
+// the reason for denial or nil. Tag rules apply to the tag in the image name, or the implied
+// latest tag when there is neither a tag nor a digest, an image referenced only by digest has no tag to check.
+func VerifyByPolicy(img *image.Reference, policy policyv1.Tags) error {
+	if policy.RequireDigest != nil && *policy.RequireDigest && img.GetDigest() == "" {
+		return fmt.Errorf("image %q must be referenced by digest", img.String())
+	}
+
+	if !img.HasTag() && img.GetDigest() != "" {
+		return nil
+	}
+	tag := img.GetTag()
+
+	for _, pattern := range policy.Deny {
+		if wildcard.Compare(pattern, tag) {
+			return fmt.Errorf("tag %q of image %q is denied by %q", tag, img.String(), pattern)
+		}
+	}
+
+	if policy.Pattern != "" {
+		re, err := CompilePattern(policy.Pattern)
+		if err != nil {
+			return fmt.Errorf("tag pattern %q is invalid: %v", policy.Pattern, err)
+		}
+		if !re.MatchString(tag) {
+			return fmt.Errorf("tag %q of image %q does not match %q", tag, img.String(), policy.Pattern)
+		}
+	}
+	return nil
+}
+
+// CompilePattern compiles a tag pattern anchored to the whole tag, through the bounded cache of compiled patterns
+// so that it is compiled once while it is in use. The pattern must be valid on its own, so that an unbalanced group
+// can not escape the anchors.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if _, err := wildcard.Compile(pattern); err != nil {
+		return nil, err
+	}
+	return wildcard.Compile("^(?:" + pattern + ")$")
+}
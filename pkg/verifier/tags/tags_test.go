@@ -0,0 +1,111 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tags
+
+import (
+	"testing"
+
+	"github.com/IBM/portieris/helpers/image"
+	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyByPolicy(t *testing.T) {
+	requireDigest := true
+	semver := `v?[0-9]+\.[0-9]+\.[0-9]+`
+
+	tests := []struct {
+		name     string
+		image    string
+		policy   policyv1.Tags
+		wantDeny string
+	}{
+		{
+			name:   "Empty policy allows anything",
+			image:  "icr.io/team/app",
+			policy: policyv1.Tags{},
+		},
+		{
+			name:     "Require digest denies a tag",
+			image:    "icr.io/team/app:v1.0.0",
+			policy:   policyv1.Tags{RequireDigest: &requireDigest},
+			wantDeny: `image "icr.io/team/app:v1.0.0" must be referenced by digest`,
+		},
+		{
+			name:   "Require digest allows a digest",
+			image:  "icr.io/team/app@sha256:1234567890",
+			policy: policyv1.Tags{RequireDigest: &requireDigest},
+		},
+		{
+			name:     "Deny latest denies an explicit latest",
+			image:    "icr.io/team/app:latest",
+			policy:   policyv1.Tags{Deny: []string{"latest"}},
+			wantDeny: `tag "latest" of image "icr.io/team/app:latest" is denied by "latest"`,
+		},
+		{
+			name:     "Deny latest denies an implied latest",
+			image:    "icr.io/team/app",
+			policy:   policyv1.Tags{Deny: []string{"latest"}},
+			wantDeny: `tag "latest" of image "icr.io/team/app" is denied by "latest"`,
+		},
+		{
+			name:   "Deny latest allows a digest without a tag",
+			image:  "icr.io/team/app@sha256:1234567890",
+			policy: policyv1.Tags{Deny: []string{"latest"}},
+		},
+		{
+			name:     "Deny wildcard denies a matching tag with a digest",
+			image:    "icr.io/team/app:1.0-SNAPSHOT@sha256:1234567890",
+			policy:   policyv1.Tags{Deny: []string{"*-SNAPSHOT"}},
+			wantDeny: `tag "1.0-SNAPSHOT" of image "icr.io/team/app:1.0-SNAPSHOT@sha256:1234567890" is denied by "*-SNAPSHOT"`,
+		},
+		{
+			name:   "Pattern allows a matching tag",
+			image:  "icr.io/team/app:v1.2.3",
+			policy: policyv1.Tags{Pattern: semver},
+		},
+		{
+			name:     "Pattern must match the whole tag",
+			image:    "icr.io/team/app:v1.2.3-rc1",
+			policy:   policyv1.Tags{Pattern: semver},
+			wantDeny: `tag "v1.2.3-rc1" of image "icr.io/team/app:v1.2.3-rc1" does not match "v?[0-9]+\\.[0-9]+\\.[0-9]+"`,
+		},
+		{
+			name:     "Invalid pattern denies",
+			image:    "icr.io/team/app:v1.2.3",
+			policy:   policyv1.Tags{Pattern: "("},
+			wantDeny: "tag pattern \"(\" is invalid: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:     "Pattern with an unbalanced group does not escape the anchors",
+			image:    "icr.io/team/app:anything",
+			policy:   policyv1.Tags{Pattern: "v1)|(.*"},
+			wantDeny: "tag pattern \"v1)|(.*\" is invalid: error parsing regexp: unexpected ): `v1)|(.*`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := image.NewReference(tt.image)
+			require.NoError(t, err)
+			deny := VerifyByPolicy(img, tt.policy)
+			if tt.wantDeny == "" {
+				assert.NoError(t, deny)
+			} else {
+				assert.EqualError(t, deny, tt.wantDeny)
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -24,7 +24,6 @@ import (
 	"github.com/golang/glog"
 	"github.com/theupdateframework/notary/tuf/data"
 	"github.com/theupdateframework/notary/tuf/utils"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var releasesRole = data.RoleName(path.Join(data.CanonicalTargetsRole.String(), "releases"))
@@ -41,8 +40,8 @@ type foundSigner struct {
 }
 
 // getDigest .
-func (v *Verifier) getDigest(server, image, notaryToken, targetName string, signers []Signer) (*bytes.Buffer, error) {
-	repo, err := v.trust.GetNotaryRepo(server, image, notaryToken)
+func (v *Verifier) getDigest(ctx context.Context, server, image, notaryToken, targetName string, signers []Signer) (*bytes.Buffer, error) {
+	repo, err := v.trust.GetNotaryRepo(ctx, server, image, notaryToken)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +124,7 @@ func (v *Verifier) getDigest(server, image, notaryToken, targetName string, sign
 func (v *Verifier) getSignerSecret(namespace, signerSecretName string) (Signer, error) {
 
 	// Retrieve secret
-	secret, err := v.kubeClientsetWrapper.CoreV1().Secrets(namespace).Get(context.TODO(), signerSecretName, metav1.GetOptions{})
+	secret, err := v.kubeClientsetWrapper.GetSecret(namespace, signerSecretName)
 	if err != nil {
 		glog.Error("Error: ", err)
 		return Signer{}, err
@@ -1,4 +1,4 @@
-// Copyright 2018 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,6 +15,7 @@
 package trust
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/IBM/portieris/pkg/kubernetes"
@@ -67,7 +68,7 @@ Aaq77T1fhZkFO5uTAgMBAAE=
 		It("should return an error if it fails to get the repo", func() {
 			trust.GetNotaryRepoReturns(nil, fakeErr)
 			ctrl = NewVerifier(kubeWrapper, trust, cr)
-			_, err := ctrl.getDigest(server, image, notaryToken, targetName, nil)
+			_, err := ctrl.getDigest(context.Background(), server, image, notaryToken, targetName, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(fakeErrorMessage))
 		})
@@ -76,7 +77,7 @@ Aaq77T1fhZkFO5uTAgMBAAE=
 			fakeRepo.GetAllTargetMetadataByNameReturns(nil, fakeErr)
 			trust.GetNotaryRepoReturns(fakeRepo, nil)
 			ctrl = NewVerifier(kubeWrapper, trust, cr)
-			_, err := ctrl.getDigest(server, image, notaryToken, targetName, nil)
+			_, err := ctrl.getDigest(context.Background(), server, image, notaryToken, targetName, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(fakeErrorMessage))
 		})
@@ -85,7 +86,7 @@ Aaq77T1fhZkFO5uTAgMBAAE=
 			fakeRepo.GetAllTargetMetadataByNameReturns([]notaryclient.TargetSignedStruct{}, nil)
 			trust.GetNotaryRepoReturns(fakeRepo, nil)
 			ctrl = NewVerifier(kubeWrapper, trust, cr)
-			_, err := ctrl.getDigest(server, image, notaryToken, targetName, nil)
+			_, err := ctrl.getDigest(context.Background(), server, image, notaryToken, targetName, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("No signed targets found"))
 		})
@@ -108,7 +109,7 @@ Aaq77T1fhZkFO5uTAgMBAAE=
 				}, nil)
 				trust.GetNotaryRepoReturns(fakeRepo, nil)
 				ctrl = NewVerifier(kubeWrapper, trust, cr)
-				digest, err := ctrl.getDigest(server, image, notaryToken, targetName, nil)
+				digest, err := ctrl.getDigest(context.Background(), server, image, notaryToken, targetName, nil)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(digest.String()).To(Equal("31323334353637383930"))
 			})
@@ -143,7 +144,7 @@ Aaq77T1fhZkFO5uTAgMBAAE=
 				}, nil)
 				trust.GetNotaryRepoReturns(fakeRepo, nil)
 				ctrl = NewVerifier(kubeWrapper, trust, cr)
-				_, err := ctrl.getDigest(server, image, notaryToken, targetName, []Signer{
+				_, err := ctrl.getDigest(context.Background(), server, image, notaryToken, targetName, []Signer{
 					{
 						signer:    "wibble",
 						publicKey: "invalid signer public key",
@@ -181,7 +182,7 @@ Aaq77T1fhZkFO5uTAgMBAAE=
 				}, nil)
 				trust.GetNotaryRepoReturns(fakeRepo, nil)
 				ctrl = NewVerifier(kubeWrapper, trust, cr)
-				_, err := ctrl.getDigest(server, image, notaryToken, targetName, []Signer{
+				_, err := ctrl.getDigest(context.Background(), server, image, notaryToken, targetName, []Signer{
 					{
 						signer:    "wibble",
 						publicKey: signerPublicKey,
@@ -219,7 +220,7 @@ Aaq77T1fhZkFO5uTAgMBAAE=
 				}, nil)
 				trust.GetNotaryRepoReturns(fakeRepo, nil)
 				ctrl = NewVerifier(kubeWrapper, trust, cr)
-				_, err := ctrl.getDigest(server, image, notaryToken, targetName, []Signer{
+				_, err := ctrl.getDigest(context.Background(), server, image, notaryToken, targetName, []Signer{
 					{
 						signer: "wibble",
 					},
@@ -256,7 +257,7 @@ Aaq77T1fhZkFO5uTAgMBAAE=
 				}, nil)
 				trust.GetNotaryRepoReturns(fakeRepo, nil)
 				ctrl = NewVerifier(kubeWrapper, trust, cr)
-				_, err := ctrl.getDigest(server, image, notaryToken, targetName, []Signer{
+				_, err := ctrl.getDigest(context.Background(), server, image, notaryToken, targetName, []Signer{
 					{
 						// signer: "wibble",
 						publicKey: signerPublicKey,
@@ -294,7 +295,7 @@ Aaq77T1fhZkFO5uTAgMBAAE=
 				}, nil)
 				trust.GetNotaryRepoReturns(fakeRepo, nil)
 				ctrl = NewVerifier(kubeWrapper, trust, cr)
-				digest, err := ctrl.getDigest(server, image, notaryToken, targetName, []Signer{
+				digest, err := ctrl.getDigest(context.Background(), server, image, notaryToken, targetName, []Signer{
 					{
 						signer:    "wibble",
 						publicKey: signerPublicKey,
@@ -1,4 +1,4 @@
-// Copyright 2018, 2022 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -16,6 +16,7 @@ package trust
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
@@ -35,7 +36,7 @@ var codec = serializer.NewCodecFactory(runtime.NewScheme())
 
 // Interface is for verifying notary signatures
 type Interface interface {
-	VerifyByPolicy(string, *image.Reference, credential.Credentials, *policyv1.Policy) (*bytes.Buffer, error, error)
+	VerifyByPolicy(context.Context, string, *image.Reference, credential.Credentials, *policyv1.Policy) (*bytes.Buffer, error, error)
 }
 
 // Verifier is the notary controller
@@ -58,7 +59,7 @@ func NewVerifier(kubeWrapper kubernetes.WrapperInterface, trust notary.Interface
 }
 
 // VerifyByPolicy ...
-func (v *Verifier) VerifyByPolicy(namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
+func (v *Verifier) VerifyByPolicy(ctx context.Context, namespace string, img *image.Reference, credentials credential.Credentials, policy *policyv1.Policy) (*bytes.Buffer, error, error) {
 	notaryURL := policy.Trust.TrustServer
 	var err error
 	if notaryURL == "" {
@@ -73,14 +74,18 @@ func (v *Verifier) VerifyByPolicy(namespace string, img *image.Reference, creden
 		// Generate a []Singer with the values for each signerSecret
 		signers = make([]Signer, len(policy.Trust.SignerSecrets))
 		for i, secretName := range policy.Trust.SignerSecrets {
-			signers[i], err = v.getSignerSecret(namespace, secretName.Name)
+			secretNamespace := namespace
+			if secretName.Namespace != "" {
+				secretNamespace = secretName.Namespace
+			}
+			signers[i], err = v.getSignerSecret(secretNamespace, secretName.Name)
 			if err != nil {
 				return nil, nil, fmt.Errorf("Deny %q, could not get signerSecret from your cluster, %s", img.String(), err.Error())
 			}
 		}
 	}
 
-	authEndpoint, err := v.trust.CheckAuthRequired(notaryURL, img)
+	authEndpoint, err := v.trust.CheckAuthRequired(ctx, notaryURL, img)
 	if err != nil {
 		return nil, nil, fmt.Errorf("Deny %q, could not resolve the auth-endpoint, %s", img.String(), err.Error())
 	}
@@ -93,14 +98,14 @@ func (v *Verifier) VerifyByPolicy(namespace string, img *image.Reference, creden
 		var notaryToken string
 
 		if authEndpoint != nil {
-			notaryToken, err = v.cr.GetContentTrustToken(authEndpoint.URL, credential.Username, credential.Password, authEndpoint.Service, authEndpoint.Scope)
+			notaryToken, err = v.cr.GetContentTrustToken(ctx, authEndpoint.URL, credential.Username, credential.Password, authEndpoint.Service, authEndpoint.Scope)
 			if err != nil {
 				glog.Error(err)
 				continue
 			}
 		}
 
-		digest, err := v.getDigest(notaryURL, img.NameWithoutTag(), notaryToken, img.GetTag(), signers)
+		digest, err := v.getDigest(ctx, notaryURL, img.NameWithoutTag(), notaryToken, img.GetTag(), signers)
 		if err != nil {
 			if strings.Contains(err.Error(), "401") {
 				continue
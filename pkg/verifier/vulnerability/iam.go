@@ -1,4 +1,4 @@
-// Copyright 2020, 2023  Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -46,8 +46,10 @@ type authenticatorFactoryInterface interface {
 	NewAuthenticator(string) (requestAuthenticator, error)
 }
 
-type authenticatorFactory struct{}
+type authenticatorFactory struct {
+	client *http.Client
+}
 
 func (a authenticatorFactory) NewAuthenticator(apiKey string) (requestAuthenticator, error) {
-	return ibmcloud.NewIamAuthenticatorBuilder().SetApiKey(apiKey).Build()
+	return ibmcloud.NewIamAuthenticatorBuilder().SetApiKey(apiKey).SetClient(a.client).Build()
 }
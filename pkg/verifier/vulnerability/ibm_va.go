@@ -1,4 +1,4 @@
-// Copyright 2020,2021 Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,6 +15,7 @@
 package vulnerability
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -49,15 +50,19 @@ var (
 	ErrorUnauthorised = errors.New("unauthorised")
 )
 
-// NewIBMVulnerabilityAdvisorScanner returns a new client for IBM's Vulnerability Advisor
-func NewIBMVulnerabilityAdvisorScanner(credentials credential.Credentials, account string) *ICCRVAScanner {
-	return &ICCRVAScanner{
-		credentials:   credentials,
-		AccountHeader: account,
-		client: &http.Client{
+// NewIBMVulnerabilityAdvisorScanner returns a new client for IBM's Vulnerability Advisor, which sends requests to
+// Vulnerability Advisor and IAM with client, or a client of its own when it is nil
+func NewIBMVulnerabilityAdvisorScanner(credentials credential.Credentials, account string, client *http.Client) *ICCRVAScanner {
+	if client == nil {
+		client = &http.Client{
 			Timeout: time.Second * time.Duration(10),
-		},
-		authenticatorFactory: authenticatorFactory{},
+		}
+	}
+	return &ICCRVAScanner{
+		credentials:          credentials,
+		AccountHeader:        account,
+		client:               client,
+		authenticatorFactory: authenticatorFactory{client: client},
 	}
 }
 
@@ -74,12 +79,12 @@ type ICCRVASummary struct {
 }
 
 // CanImageDeployBasedOnVulnerabilities is an implementation of the Scanner interface for Vulnerability Advisor for IBM Cloud Container Registry
-func (s *ICCRVAScanner) CanImageDeployBasedOnVulnerabilities(image image.Reference) (scan ScanResponse, err error) {
+func (s *ICCRVAScanner) CanImageDeployBasedOnVulnerabilities(ctx context.Context, image image.Reference) (scan ScanResponse, err error) {
 	if !image.HasIBMRepo() {
 		return scan, fmt.Errorf("Cannot use Vulnerability Advisor for IBM Cloud Container Registry with image %q", image.String())
 	}
 	var summary ICCRVASummary
-	summary, err = s.getImageStatus(image)
+	summary, err = s.getImageStatus(ctx, image)
 	if err == nil {
 		switch summary.Status {
 		// WARN is returned when exemptions cover all of the vulnerabilities in the image
@@ -101,7 +106,7 @@ func (s *ICCRVAScanner) CanImageDeployBasedOnVulnerabilities(image image.Referen
 
 // internal call to VA - API docs: https://cloud.ibm.com/apidocs/container-registry/va#imagestatusquerypath
 // GET /va/api/v3/report/image/status/{name}
-func (s *ICCRVAScanner) getImageStatus(image image.Reference) (ICCRVASummary, error) {
+func (s *ICCRVAScanner) getImageStatus(ctx context.Context, image image.Reference) (ICCRVASummary, error) {
 	if len(s.credentials) == 0 {
 		return ICCRVASummary{}, fmt.Errorf("No credentials on client to call Vulnerability Advisor for IBM Cloud Container Registry with")
 	}
@@ -110,7 +115,7 @@ func (s *ICCRVAScanner) getImageStatus(image image.Reference) (ICCRVASummary, er
 
 	var summary *ICCRVASummary
 	for _, cred := range s.credentials {
-		sum, err := s.callVA(cred, uri)
+		sum, err := s.callVA(ctx, cred, uri)
 		switch {
 		case err == nil:
 			break
@@ -133,15 +138,19 @@ func (s *ICCRVAScanner) getImageStatus(image image.Reference) (ICCRVASummary, er
 	return *summary, nil
 }
 
-func (s *ICCRVAScanner) callVA(cred credential.Credential, uri string) (*ICCRVASummary, error) {
-	req, err := s.createRequest(cred, uri)
+func (s *ICCRVAScanner) callVA(ctx context.Context, cred credential.Credential, uri string) (*ICCRVASummary, error) {
+	req, err := s.createRequest(ctx, cred, uri)
 	if err != nil {
 		return nil, err
 	}
 
 	for try := 0; try < maxRetries; try++ {
-		// Linear backoff
-		time.Sleep(sleepTime * time.Duration(try))
+		// Linear backoff, abandoned if the caller gives up
+		select {
+		case <-time.After(sleepTime * time.Duration(try)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 
 		var resp *http.Response
 		resp, err = s.client.Do(req)
@@ -177,8 +186,8 @@ func (s *ICCRVAScanner) callVA(cred credential.Credential, uri string) (*ICCRVAS
 	return &ICCRVASummary{}, err
 }
 
-func (s *ICCRVAScanner) createRequest(cred credential.Credential, uri string) (*http.Request, error) {
-	req, _ := http.NewRequest(http.MethodGet, uri, nil)
+func (s *ICCRVAScanner) createRequest(ctx context.Context, cred credential.Credential, uri string) (*http.Request, error) {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
 
 	if s.AccountHeader != "" {
 		req.Header.Add("Account", s.AccountHeader)
@@ -1,4 +1,4 @@
-// Copyright 2020 Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -16,6 +16,7 @@ package vulnerability
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
@@ -61,8 +62,14 @@ var tripleCreds = credential.Credentials{
 }
 
 func Test_NewIBMVulnerabilityAdvisorScanner(t *testing.T) {
-	c := NewIBMVulnerabilityAdvisorScanner(singleCreds, "123")
+	c := NewIBMVulnerabilityAdvisorScanner(singleCreds, "123", nil)
 	assert.Equal(t, "123", c.AccountHeader)
+	assert.Equal(t, 10*time.Second, c.client.(*http.Client).Timeout)
+
+	client := &http.Client{}
+	c = NewIBMVulnerabilityAdvisorScanner(singleCreds, "123", client)
+	assert.Same(t, client, c.client)
+	assert.Equal(t, authenticatorFactory{client: client}, c.authenticatorFactory)
 }
 
 func Test_CanImageDeployBasedOnVulnerabilities(t *testing.T) {
@@ -166,7 +173,7 @@ func Test_CanImageDeployBasedOnVulnerabilities(t *testing.T) {
 			image, err := image.NewReference(tt.fullImageName)
 			require.NoError(t, err)
 
-			result, err := client.CanImageDeployBasedOnVulnerabilities(*image)
+			result, err := client.CanImageDeployBasedOnVulnerabilities(context.Background(), *image)
 
 			assert.Equal(t, tt.expectedResult, result)
 			assert.Equal(t, tt.expectedError, err)
@@ -437,7 +444,7 @@ func Test_getImageStatus(t *testing.T) {
 			image, err := image.NewReference(tt.fullImageName)
 			require.NoError(t, err)
 
-			result, err := client.getImageStatus(*image)
+			result, err := client.getImageStatus(context.Background(), *image)
 
 			assert.Equal(t, tt.expectedResult, result)
 			assert.Equal(t, tt.expectedError, err)
@@ -772,7 +779,7 @@ func TestICCRVAScanner_createRequest(t *testing.T) {
 				authenticatorFactory: &authenticatorFactory,
 			}
 
-			gotRequest, gotErr := c.createRequest(tt.cred, tt.uri)
+			gotRequest, gotErr := c.createRequest(context.Background(), tt.cred, tt.uri)
 
 			if tt.wantRequest != nil {
 				assert.Equal(t, "GET", gotRequest.Method)
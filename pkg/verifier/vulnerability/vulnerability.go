@@ -1,4 +1,4 @@
-// Copyright 2020, 2021 Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,6 +15,9 @@
 package vulnerability
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/IBM/portieris/helpers/credential"
 	"github.com/IBM/portieris/helpers/image"
 	policyv1 "github.com/IBM/portieris/pkg/apis/portieris.cloud.ibm.com/v1"
@@ -23,7 +26,7 @@ import (
 
 // Scanner is an interface for vulnerability scanner implementations
 type Scanner interface {
-	CanImageDeployBasedOnVulnerabilities(image.Reference) (ScanResponse, error)
+	CanImageDeployBasedOnVulnerabilities(context.Context, image.Reference) (ScanResponse, error)
 }
 
 // ScanResponse is a struct for vulnerability scanners to return
@@ -38,18 +41,20 @@ type ScannerFactory interface {
 }
 
 // DefaultScannerFactory is the defaul implementation of ScannerFactory
-type DefaultScannerFactory struct{}
+type DefaultScannerFactory struct {
+	client *http.Client
+}
 
-// NewScannerFactory returns a new DefaultScannerFactory
-func NewScannerFactory() DefaultScannerFactory {
-	return DefaultScannerFactory{}
+// NewScannerFactory returns a new DefaultScannerFactory, whose scanners send requests with client
+func NewScannerFactory(client *http.Client) DefaultScannerFactory {
+	return DefaultScannerFactory{client: client}
 }
 
 // GetScanners returns a slice of suitable Scanners based on the provided policy
 func (f *DefaultScannerFactory) GetScanners(img image.Reference, credentials credential.Credentials, policy policyv1.Policy) (scanners []Scanner) {
 	if policy.Vulnerability.ICCRVA.Enabled != nil && *policy.Vulnerability.ICCRVA.Enabled {
 		glog.Infof("vulnerability: Using Vulnerability Advisor for IBM Cloud Container Registry for image %q.", img.String())
-		scanners = append(scanners, NewIBMVulnerabilityAdvisorScanner(credentials, policy.Vulnerability.ICCRVA.Account))
+		scanners = append(scanners, NewIBMVulnerabilityAdvisorScanner(credentials, policy.Vulnerability.ICCRVA.Account, f.client))
 	}
 
 	return
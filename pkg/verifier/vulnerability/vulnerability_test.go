@@ -1,4 +1,4 @@
-// Copyright 2020, 2021 Portieris Authors.
+// Copyright 2020, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,6 +15,7 @@
 package vulnerability
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/IBM/portieris/helpers/credential"
@@ -26,8 +27,9 @@ import (
 )
 
 func Test_NewScannerFactory(t *testing.T) {
-	f := NewScannerFactory()
-	assert.Equal(t, DefaultScannerFactory{}, f)
+	client := &http.Client{}
+	f := NewScannerFactory(client)
+	assert.Equal(t, DefaultScannerFactory{client: client}, f)
 }
 
 func boolToPointer(in bool) *bool {
@@ -35,7 +37,8 @@ func boolToPointer(in bool) *bool {
 }
 
 func Test_GetScanners(t *testing.T) {
-	f := NewScannerFactory()
+	client := &http.Client{}
+	f := NewScannerFactory(client)
 
 	tests := []struct {
 		name         string
@@ -93,6 +96,7 @@ func Test_GetScanners(t *testing.T) {
 					wantAccount := test.policy.Vulnerability.ICCRVA.Account
 					gotAccount := s.AccountHeader
 					assert.Equal(t, wantAccount, gotAccount)
+					assert.Same(t, client, s.client)
 				}
 			}
 		})
@@ -0,0 +1,82 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// HandleAuthorized serves requests at path with handler, for callers whose bearer token the API server authenticates
+// and who are allowed the attributes by the API server, for example to delete a resource
+func (s *Server) HandleAuthorized(path string, handler http.Handler, clientset kubernetes.Interface, attributes authorizationv1.ResourceAttributes) {
+	s.mux.Handle(path, authorize(handler, clientset, attributes))
+}
+
+func authorize(handler http.Handler, clientset kubernetes.Interface, attributes authorizationv1.ResourceAttributes) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || token == r.Header.Get("Authorization") {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		review, err := clientset.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{Token: token},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			glog.Errorf("Unable to review the token of a request to %s: %v", r.URL.Path, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !review.Status.Authenticated {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		user := review.Status.User
+		extra := map[string]authorizationv1.ExtraValue{}
+		for k, v := range user.Extra {
+			extra[k] = authorizationv1.ExtraValue(v)
+		}
+		access, err := clientset.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &attributes,
+				User:               user.Username,
+				Groups:             user.Groups,
+				UID:                user.UID,
+				Extra:              extra,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			glog.Errorf("Unable to review the access of %s to %s: %v", user.Username, r.URL.Path, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !access.Status.Allowed {
+			glog.Warningf("Denied request to %s by %s", r.URL.Path, user.Username)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		glog.Infof("Request to %s by %s", r.URL.Path, user.Username)
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/golang/glog"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ConvertFunc converts a custom resource, encoded as JSON, to desiredAPIVersion
+type ConvertFunc func(object []byte, desiredAPIVersion string) ([]byte, error)
+
+// HandleConversion serves custom resource conversion reviews at path with convert
+func (s *Server) HandleConversion(path string, convert ConvertFunc) {
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		handleConversionReview(w, r, convert)
+	})
+}
+
+func handleConversionReview(w http.ResponseWriter, r *http.Request, convert ConvertFunc) {
+	defer r.Body.Close()
+	body, _ := ioutil.ReadAll(r.Body)
+
+	var conversionReview apiextensionsv1.ConversionReview
+	if err := json.Unmarshal(body, &conversionReview); err != nil || conversionReview.Request == nil {
+		http.Error(w, "bad request, unable to decode conversion review body", http.StatusBadRequest)
+		return
+	}
+	request := conversionReview.Request
+	response := &apiextensionsv1.ConversionResponse{
+		UID:    request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, object := range request.Objects {
+		converted, err := convert(object.Raw, request.DesiredAPIVersion)
+		if err != nil {
+			glog.Errorf("Conversion to %s failed: %v", request.DesiredAPIVersion, err)
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	resp, err := json.Marshal(apiextensionsv1.ConversionReview{TypeMeta: conversionReview.TypeMeta, Response: response})
+	if err != nil {
+		glog.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(resp)
+}
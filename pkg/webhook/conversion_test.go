@@ -0,0 +1,108 @@
+// Copyright 2026 Portieris Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	fakeController "github.com/IBM/portieris/pkg/controller/fakecontroller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestServer_HandleConversion(t *testing.T) {
+	convert := func(object []byte, desiredAPIVersion string) ([]byte, error) {
+		if string(object) == `"bad"` {
+			return nil, fmt.Errorf("can not convert")
+		}
+		return []byte(fmt.Sprintf(`{"apiVersion":%q}`, desiredAPIVersion)), nil
+	}
+	tests := []struct {
+		name         string
+		body         []byte
+		wantCode     int
+		wantResult   metav1.Status
+		wantObjects  []string
+		wantResponse bool
+	}{
+		{
+			name:         "objects are converted",
+			body:         conversionReview(`{"apiVersion":"example.com/v1"}`, `{"apiVersion":"example.com/v1"}`),
+			wantCode:     http.StatusOK,
+			wantResult:   metav1.Status{Status: metav1.StatusSuccess},
+			wantObjects:  []string{`{"apiVersion":"example.com/v2"}`, `{"apiVersion":"example.com/v2"}`},
+			wantResponse: true,
+		},
+		{
+			name:         "one failure fails the review",
+			body:         conversionReview(`{"apiVersion":"example.com/v1"}`, `"bad"`),
+			wantCode:     http.StatusOK,
+			wantResult:   metav1.Status{Status: metav1.StatusFailure, Message: "can not convert"},
+			wantResponse: true,
+		},
+		{
+			name:     "a review without a request is rejected",
+			body:     []byte(`{}`),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "a body that is not a review is rejected",
+			body:     []byte(`not json`),
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer("test", &fakeController.Controller{}, nil, nil)
+			server.HandleConversion("/convert", convert)
+
+			req, _ := http.NewRequest("POST", "/convert", bytes.NewBuffer(tt.body))
+			rr := httptest.NewRecorder()
+			server.mux.ServeHTTP(rr, req)
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if !tt.wantResponse {
+				return
+			}
+
+			var reviewOut apiextensionsv1.ConversionReview
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &reviewOut))
+			require.NotNil(t, reviewOut.Response)
+			assert.Equal(t, "requestUID", string(reviewOut.Response.UID))
+			assert.Equal(t, tt.wantResult, reviewOut.Response.Result)
+			var objects []string
+			for _, object := range reviewOut.Response.ConvertedObjects {
+				objects = append(objects, string(object.Raw))
+			}
+			assert.Equal(t, tt.wantObjects, objects)
+		})
+	}
+}
+
+func conversionReview(objects ...string) []byte {
+	request := &apiextensionsv1.ConversionRequest{UID: "requestUID", DesiredAPIVersion: "example.com/v2"}
+	for _, object := range objects {
+		request.Objects = append(request.Objects, runtime.RawExtension{Raw: []byte(object)})
+	}
+	review, _ := json.Marshal(apiextensionsv1.ConversionReview{Request: request})
+	return review
+}
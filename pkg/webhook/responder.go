@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -27,16 +27,18 @@ import (
 // AdmissionResponder is a helper for handling admission response creation
 // It supports adding and returning multiple errors to the user
 type AdmissionResponder struct {
-	allowed bool
-	errors  []string
-	patches []byte
+	allowed  bool
+	errors   []string
+	warnings []string
+	patches  []byte
 }
 
 // Flush creates the admission response to return
 func (a *AdmissionResponder) Flush() *v1.AdmissionResponse {
 	if a.allowed && !a.HasErrors() {
 		res := &v1.AdmissionResponse{
-			Allowed: true,
+			Allowed:  true,
+			Warnings: a.warnings,
 		}
 
 		if a.patches != nil {
@@ -47,7 +49,8 @@ func (a *AdmissionResponder) Flush() *v1.AdmissionResponse {
 		return res
 	}
 	return &v1.AdmissionResponse{
-		Allowed: false,
+		Allowed:  false,
+		Warnings: a.warnings,
 		Result: &metav1.Status{
 			Message: fmt.Sprintf("\n%s", strings.Join(a.errors, "\n")),
 		},
@@ -59,6 +62,16 @@ func (a *AdmissionResponder) HasErrors() bool {
 	return len(a.errors) != 0
 }
 
+// Errors returns the errors added to the response
+func (a *AdmissionResponder) Errors() []string {
+	return a.errors
+}
+
+// ClearErrors removes the errors from the response, so that it can be allowed when a denial is overridden
+func (a *AdmissionResponder) ClearErrors() {
+	a.errors = nil
+}
+
 // SetAllowed sets the admission response to allow the admission
 func (a *AdmissionResponder) SetAllowed() {
 	a.allowed = true
@@ -101,6 +114,12 @@ func (a *AdmissionResponder) StringsToAdmissionResponse(msgs []string) {
 	}
 }
 
+// AddWarning adds a warning to be returned to the user whether or not the admission is allowed
+func (a *AdmissionResponder) AddWarning(msg string) {
+	glog.Warning(msg)
+	a.warnings = append(a.warnings, msg)
+}
+
 // MapStringsToAdmissionResponse adds a map of a slice of strings as errors to the reponse
 func (a *AdmissionResponder) MapStringsToAdmissionResponse(mapofmsgs map[string][]string) {
 	for _, msgs := range mapofmsgs {
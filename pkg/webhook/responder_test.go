@@ -1,4 +1,4 @@
-// Copyright 2018 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -40,4 +40,29 @@ func TestAdmissionResponder(t *testing.T) {
 		assert.Equal(t, string(patch), string(resp.Patch))
 		assert.True(t, resp.Allowed)
 	})
+
+	t.Run("should include warnings in allowed and denied responses", func(t *testing.T) {
+		responder := &AdmissionResponder{}
+		responder.AddWarning("FAKE_WARNING")
+		responder.SetAllowed()
+		resp := responder.Flush()
+		assert.Equal(t, []string{"FAKE_WARNING"}, resp.Warnings)
+		assert.True(t, resp.Allowed)
+
+		responder.ToAdmissionResponse(fmt.Errorf("FAKE_ERROR"))
+		resp = responder.Flush()
+		assert.Equal(t, []string{"FAKE_WARNING"}, resp.Warnings)
+		assert.False(t, resp.Allowed)
+	})
+	t.Run("should allow the response when the errors are cleared", func(t *testing.T) {
+		responder := &AdmissionResponder{}
+		responder.ToAdmissionResponse(fmt.Errorf("FAKE_ERROR"))
+		responder.SetAllowed()
+		assert.Equal(t, []string{"FAKE_ERROR"}, responder.Errors())
+		assert.False(t, responder.Flush().Allowed)
+
+		responder.ClearErrors()
+		assert.False(t, responder.HasErrors())
+		assert.True(t, responder.Flush().Allowed)
+	})
 }
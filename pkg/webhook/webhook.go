@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -42,6 +42,8 @@ type Server struct {
 	controller controller.Interface
 
 	serverCert, serverKey []byte
+	// readinessChecks must all pass before the server reports that it is ready
+	readinessChecks []func() bool
 }
 
 // NewServer creates a new admission webhook server with the passed controller handling the admissions
@@ -59,7 +61,17 @@ func NewServer(name string, ctrl controller.Interface, cert, key []byte) *Server
 // HandleAdmissionRequest handles an incoming request and calls the controllers admit function
 // It writes the response from the Admit to the response writer
 func (s *Server) HandleAdmissionRequest(w http.ResponseWriter, r *http.Request) {
+	handleAdmissionRequest(w, r, s.controller)
+}
+
+// HandleController serves admission requests at path with ctrl, in addition to the controller of the server at /admit
+func (s *Server) HandleController(path string, ctrl controller.Interface) {
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		handleAdmissionRequest(w, r, ctrl)
+	})
+}
 
+func handleAdmissionRequest(w http.ResponseWriter, r *http.Request, ctrl controller.Interface) {
 	defer r.Body.Close()
 	body, _ := ioutil.ReadAll(r.Body)
 
@@ -76,7 +88,7 @@ func (s *Server) HandleAdmissionRequest(w http.ResponseWriter, r *http.Request)
 		responder.Write(w, admissionReview)
 		return
 	}
-	admissionResponse := s.controller.Admit(admissionReview.Request)
+	admissionResponse := ctrl.Admit(r.Context(), admissionReview.Request)
 	w.Write(reviewResponseToByte(admissionResponse, admissionReview))
 }
 
@@ -88,11 +100,23 @@ func (s *Server) HandleLiveness(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// AddReadinessCheck adds a check that must pass before the server reports that it is ready,
+// for example that the informer caches the controller reads from have synced
+func (s *Server) AddReadinessCheck(check func() bool) {
+	s.readinessChecks = append(s.readinessChecks, check)
+}
+
 // HandleReadiness responds to a Kubernetes Readiness probe
 // Fail this request if this instance can't accept traffic, but Kubernetes shouldn't restart it
 func (s *Server) HandleReadiness(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
+	for _, check := range s.readinessChecks {
+		if !check() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -1,4 +1,4 @@
-// Copyright 2018, 2021 Portieris Authors.
+// Copyright 2018, 2026 Portieris Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -16,6 +16,7 @@ package webhook
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -73,6 +74,65 @@ func TestServer_HandleAdmissionRequest(t *testing.T) {
 	}
 }
 
+type denyController struct{}
+
+func (denyController) Admit(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: false}
+}
+
+func TestServer_HandleController(t *testing.T) {
+	server := NewServer("test", &fakeController.Controller{}, nil, nil)
+	server.HandleController("/validate", denyController{})
+
+	bytesIn, _ := json.Marshal(admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{UID: "requestUID"}})
+	req, _ := http.NewRequest("POST", "/validate", bytes.NewBuffer(bytesIn))
+	rr := httptest.NewRecorder()
+	server.mux.ServeHTTP(rr, req)
+
+	var reviewOut admissionv1.AdmissionReview
+	bytesOut, _ := ioutil.ReadAll(rr.Body)
+	json.Unmarshal(bytesOut, &reviewOut)
+	if assert.NotNil(t, reviewOut.Response) {
+		assert.False(t, reviewOut.Response.Allowed)
+		assert.Equal(t, "requestUID", string(reviewOut.Response.UID))
+	}
+}
+
+func TestServer_HandleReadiness(t *testing.T) {
+	tests := []struct {
+		name     string
+		checks   []func() bool
+		wantCode int
+	}{
+		{
+			name:     "Ready without checks",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Ready when every check passes",
+			checks:   []func() bool{func() bool { return true }, func() bool { return true }},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Not ready when a check fails",
+			checks:   []func() bool{func() bool { return true }, func() bool { return false }},
+			wantCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := getTestWebhookServer()
+			for _, check := range tt.checks {
+				server.AddReadinessCheck(check)
+			}
+			req := httptest.NewRequest("GET", "/health/readiness", nil)
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(server.HandleReadiness).ServeHTTP(rr, req)
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
+
 func Test_reviewResponseToByte(t *testing.T) {
 	tests := []struct {
 		name                string